@@ -0,0 +1,34 @@
+// Package chi provides request logging middleware for the chi router that logs the matched route pattern:
+//
+//	r := chi.NewRouter()
+//	r.Use(slogutilschi.Middleware(nil))
+package chi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/networkteam/slogutils/httplog"
+)
+
+// Middleware returns a middleware logging requests with httplog.Middleware.
+// If opts.Route is nil, the route pattern matched by chi (e.g. "/users/{id}") is logged.
+func Middleware(opts *httplog.MiddlewareOptions) func(http.Handler) http.Handler {
+	var o httplog.MiddlewareOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Route == nil {
+		o.Route = RoutePattern
+	}
+	return httplog.NewMiddleware(&o).Handler
+}
+
+// RoutePattern returns the route pattern matched by chi for the request.
+func RoutePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
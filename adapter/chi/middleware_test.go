@@ -0,0 +1,44 @@
+package chi_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/networkteam/slogutils"
+	slogutilschi "github.com/networkteam/slogutils/adapter/chi"
+)
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.TimeKey, slogutils.StartKey, slogutils.DurationKey:
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	r := chi.NewRouter()
+	r.Use(slogutilschi.Middleware(nil))
+	r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(chi.URLParam(r, "id")))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req = req.WithContext(slogutils.WithLogger(req.Context(), logger))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	want := `level=INFO msg="HTTP request" method=GET path=/users/42 route=/users/{id} status=200 bytes=2`
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
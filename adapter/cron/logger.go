@@ -0,0 +1,111 @@
+// Package cron provides a bridge from the logging of robfig/cron to slog and helpers to log scheduled jobs.
+//
+// The Logger implements cron.Logger without depending on the cron module:
+//
+//	c := cron.New(cron.WithLogger(slogutilscron.NewLogger(logger.With("component", "cron"))))
+//	c.AddFunc("@hourly", slogutilscron.JobFunc(logger, "cleanup", nil, cleanup))
+package cron
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/joblog"
+)
+
+// Logger is an adapter for cron to slog.
+// Info messages of cron (e.g. "schedule", "wake" and "run") are logged at debug level by default, see WithInfoLevel.
+type Logger struct {
+	logger        *slog.Logger
+	infoLevel     slog.Level
+	errorLevel    slog.Level
+	messageLevels map[string]slog.Level
+}
+
+// NewLogger builds a new logger instance given a slog.Logger instance
+func NewLogger(logger *slog.Logger, opts ...LoggerOpt) *Logger {
+	l := &Logger{
+		logger:     logger,
+		infoLevel:  slog.LevelDebug,
+		errorLevel: slog.LevelError,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Info logs routine messages, implements cron.Logger
+func (l *Logger) Info(msg string, keysAndValues ...any) {
+	level := l.infoLevel
+	if mappedLevel, ok := l.messageLevels[msg]; ok {
+		level = mappedLevel
+	}
+	l.log(level, msg, nil, keysAndValues)
+}
+
+// Error logs errors (e.g. panics of jobs), implements cron.Logger
+func (l *Logger) Error(err error, msg string, keysAndValues ...any) {
+	l.log(l.errorLevel, msg, err, keysAndValues)
+}
+
+func (l *Logger) log(level slog.Level, msg string, err error, keysAndValues []any) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers, log and the exported logging method
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	if err != nil {
+		r.AddAttrs(slogutils.Err(err))
+	}
+	r.Add(keysAndValues...)
+	_ = l.logger.Handler().Handle(ctx, r)
+}
+
+// LoggerOpt sets options for the logger
+type LoggerOpt func(*Logger)
+
+// WithInfoLevel sets the level of info messages (debug by default)
+func WithInfoLevel(level slog.Level) LoggerOpt {
+	return func(l *Logger) {
+		l.infoLevel = level
+	}
+}
+
+// WithErrorLevel sets the level of error messages (error by default)
+func WithErrorLevel(level slog.Level) LoggerOpt {
+	return func(l *Logger) {
+		l.errorLevel = level
+	}
+}
+
+// WithMessageLevel sets the level for info messages with the given message, e.g. to log "wake" at trace level
+func WithMessageLevel(msg string, level slog.Level) LoggerOpt {
+	return func(l *Logger) {
+		if l.messageLevels == nil {
+			l.messageLevels = make(map[string]slog.Level)
+		}
+		l.messageLevels[msg] = level
+	}
+}
+
+// JobFunc returns a function for cron.AddFunc that runs fn with joblog.Run. The job logger is a child of logger
+// with the name as job kind and a sequential number of the run as job ID, fn gets it with slogutils.FromContext.
+// Errors and panics of fn are logged by joblog.Run.
+func JobFunc(logger *slog.Logger, name string, opts *joblog.Options, fn func(ctx context.Context) error) func() {
+	var runs atomic.Int64
+	return func() {
+		ctx := slogutils.WithLogger(context.Background(), logger)
+		job := joblog.Job{ID: strconv.FormatInt(runs.Add(1), 10), Kind: name, Attempt: 1}
+		_ = joblog.Run(ctx, job, opts, fn)
+	}
+}
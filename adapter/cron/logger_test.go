@@ -0,0 +1,60 @@
+package cron_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	slogutilscron "github.com/networkteam/slogutils/adapter/cron"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slogutils.LevelTrace,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.TimeKey, slogutils.StartKey, slogutils.DurationKey:
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := slogutilscron.NewLogger(newTestLogger(&buf), slogutilscron.WithMessageLevel("wake", slogutils.LevelTrace))
+
+	l.Info("wake", "now", "2024-01-01")
+	l.Info("run", "entry", 1)
+	l.Error(errors.New("boom"), "panic", "stack", "...")
+
+	want := strings.Join([]string{
+		`level=DEBUG-4 msg=wake now=2024-01-01`,
+		`level=DEBUG msg=run entry=1`,
+		`level=ERROR msg=panic err=boom stack=...`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+func TestJobFunc(t *testing.T) {
+	var buf bytes.Buffer
+	job := slogutilscron.JobFunc(newTestLogger(&buf), "cleanup", nil, func(ctx context.Context) error {
+		slogutils.FromContext(ctx).Info("Removing files")
+		return nil
+	})
+	job()
+	job()
+
+	got := strings.TrimRight(buf.String(), "\n")
+	if !strings.Contains(got, `msg="Removing files" job_id=1 job_kind=cleanup`) || !strings.Contains(got, `msg="Job completed" job_id=2 job_kind=cleanup`) {
+		t.Fatalf("expected job records, got:\n%s", got)
+	}
+}
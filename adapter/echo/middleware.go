@@ -0,0 +1,39 @@
+// Package echo provides request logging middleware for Echo that logs the matched route and errors returned by
+// handlers:
+//
+//	e := echo.New()
+//	e.Use(slogutilsecho.Middleware(nil))
+package echo
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/networkteam/slogutils/httplog"
+)
+
+// Middleware returns a middleware logging requests with httplog.Middleware.
+// The route of Echo (e.g. "/users/:id") is logged. Errors returned by handlers are passed to the error handler of
+// Echo, so the status of the error response is logged, and added to the record.
+func Middleware(opts *httplog.MiddlewareOptions) echo.MiddlewareFunc {
+	m := httplog.NewMiddleware(opts)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Handlers get the request with the context logger and write to the wrapped writer
+				c.SetRequest(r)
+				c.Response().Writer = w
+
+				httplog.SetRoute(r.Context(), c.Path())
+				if err := next(c); err != nil {
+					httplog.SetError(r.Context(), err)
+					c.Error(err)
+				}
+			})).ServeHTTP(c.Response().Writer, c.Request())
+
+			// Errors were already handled by the error handler
+			return nil
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package echo_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/networkteam/slogutils"
+	slogutilsecho "github.com/networkteam/slogutils/adapter/echo"
+)
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.TimeKey, slogutils.StartKey, slogutils.DurationKey:
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	e := echo.New()
+	e.Use(slogutilsecho.Middleware(nil))
+	e.GET("/users/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Param("id"))
+	})
+	e.GET("/fail", func(c echo.Context) error {
+		return errors.New("database unavailable")
+	})
+
+	for _, path := range []string{"/users/42", "/fail"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req = req.WithContext(slogutils.WithLogger(req.Context(), logger))
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	want := strings.Join([]string{
+		`level=INFO msg="HTTP request" method=GET path=/users/42 route=/users/:id status=200 bytes=2`,
+		`level=ERROR msg="HTTP request" method=GET path=/fail route=/fail status=500 bytes=36 err="database unavailable"`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
@@ -0,0 +1,233 @@
+// Package goredis provides a hook for go-redis that logs commands, pipelines and dials to slog:
+//
+//	rdb.AddHook(goredis.NewHook(logger, goredis.WithSlowThreshold(100*time.Millisecond)))
+package goredis
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Hook is a go-redis hook logging to slog.
+// Successful commands and pipelines are logged at debug level, slow ones at warn level and failed ones at error level.
+// A redis.Nil reply (e.g. of GET for a missing key) is not treated as an error.
+type Hook struct {
+	logger        *slog.Logger
+	contextLogger bool
+	ignoreErrors  func(err error) bool
+	level         slog.Level
+	slowThreshold time.Duration
+	slowLevel     slog.Level
+	errorLevel    slog.Level
+	redactArgs    func(args []any) []any
+}
+
+var _ redis.Hook = (*Hook)(nil)
+
+// NewHook builds a new hook instance given a slog.Logger instance
+func NewHook(logger *slog.Logger, opts ...HookOpt) *Hook {
+	h := &Hook{
+		logger:     logger,
+		level:      slog.LevelDebug,
+		slowLevel:  slog.LevelWarn,
+		errorLevel: slog.LevelError,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// DialHook logs failed dials at error level and successful dials at trace level, implements redis.Hook
+func (h *Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		start := time.Now()
+		conn, err := next(ctx, network, addr)
+
+		level := slogutils.LevelTrace
+		attrs := []slog.Attr{slog.String("network", network), slog.String("addr", addr), slog.Duration("duration", time.Since(start))}
+		if err != nil {
+			if h.ignoreErrors != nil && h.ignoreErrors(err) {
+				return conn, err
+			}
+			level = h.errorLevel
+			attrs = append([]slog.Attr{slogutils.Err(err)}, attrs...)
+		}
+		h.loggerFor(ctx).LogAttrs(ctx, level, "Dial", attrs...)
+		return conn, err
+	}
+}
+
+// ProcessHook logs commands, implements redis.Hook
+func (h *Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		cmdErr := next(ctx, cmd)
+		duration := time.Since(start)
+
+		err := loggedErr(cmdErr)
+		if err != nil && h.ignoreErrors != nil && h.ignoreErrors(err) {
+			return cmdErr
+		}
+		level := h.toLevel(err, duration)
+		logger := h.loggerFor(ctx)
+		if !logger.Enabled(ctx, level) {
+			return cmdErr
+		}
+
+		var attrs []slog.Attr
+		if err != nil {
+			attrs = append(attrs, slogutils.Err(err))
+		}
+		attrs = append(attrs,
+			slog.String("cmd", cmd.FullName()),
+			slog.Any("args", h.args(cmd)),
+			slog.Duration("duration", duration),
+		)
+		logger.LogAttrs(ctx, level, "Command", attrs...)
+		return cmdErr
+	}
+}
+
+// ProcessPipelineHook logs pipelines (and transactions), implements redis.Hook
+func (h *Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		pipelineErr := next(ctx, cmds)
+		duration := time.Since(start)
+
+		// The error of a pipeline is the first error of its commands
+		err := pipelineErr
+		for _, cmd := range cmds {
+			if loggedErr(err) != nil {
+				break
+			}
+			err = cmd.Err()
+		}
+
+		err = loggedErr(err)
+		if err != nil && h.ignoreErrors != nil && h.ignoreErrors(err) {
+			return pipelineErr
+		}
+		level := h.toLevel(err, duration)
+		logger := h.loggerFor(ctx)
+		if !logger.Enabled(ctx, level) {
+			return pipelineErr
+		}
+
+		names := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			names[i] = cmd.FullName()
+		}
+		var attrs []slog.Attr
+		if err != nil {
+			attrs = append(attrs, slogutils.Err(err))
+		}
+		attrs = append(attrs,
+			slog.Any("cmds", names),
+			slog.Duration("duration", duration),
+		)
+		logger.LogAttrs(ctx, level, "Pipeline", attrs...)
+		return pipelineErr
+	}
+}
+
+// loggedErr returns the error to log for a command or pipeline, a redis.Nil reply is not an error.
+func loggedErr(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+func (h *Hook) toLevel(err error, duration time.Duration) slog.Level {
+	if err != nil {
+		return h.errorLevel
+	}
+	if h.slowThreshold > 0 && duration >= h.slowThreshold {
+		return h.slowLevel
+	}
+	return h.level
+}
+
+func (h *Hook) args(cmd redis.Cmder) []any {
+	// The first argument is the command name
+	args := cmd.Args()
+	if len(args) > 0 {
+		args = args[1:]
+	}
+	if h.redactArgs != nil {
+		return h.redactArgs(args)
+	}
+	return args
+}
+
+func (h *Hook) loggerFor(ctx context.Context) *slog.Logger {
+	if h.contextLogger {
+		return slogutils.FromContext(ctx)
+	}
+	return h.logger
+}
+
+// HookOpt sets options for the hook
+type HookOpt func(*Hook)
+
+// WithIgnoreErrors sets an option to ignore certain errors based on a matcher function.
+// Commands failing with an ignored error are not logged at all.
+func WithIgnoreErrors(matcher func(err error) bool) HookOpt {
+	return func(h *Hook) {
+		h.ignoreErrors = matcher
+	}
+}
+
+// WithContextLogger sets an option to use the logger from the context (see slogutils.FromContext) instead of the
+// logger given to NewHook, so attributes of the context logger (e.g. a request ID) are added automatically.
+// The logger given to NewHook is ignored and can be nil.
+func WithContextLogger() HookOpt {
+	return func(h *Hook) {
+		h.contextLogger = true
+	}
+}
+
+// WithLevel sets the level of successful commands and pipelines (debug by default)
+func WithLevel(level slog.Level) HookOpt {
+	return func(h *Hook) {
+		h.level = level
+	}
+}
+
+// WithErrorLevel sets the level of failed commands, pipelines and dials (error by default)
+func WithErrorLevel(level slog.Level) HookOpt {
+	return func(h *Hook) {
+		h.errorLevel = level
+	}
+}
+
+// WithSlowThreshold sets an option to log commands and pipelines taking at least the given duration at warn level
+func WithSlowThreshold(threshold time.Duration) HookOpt {
+	return func(h *Hook) {
+		h.slowThreshold = threshold
+	}
+}
+
+// WithSlowLevel sets the level of slow commands and pipelines (warn by default), see WithSlowThreshold
+func WithSlowLevel(level slog.Level) HookOpt {
+	return func(h *Hook) {
+		h.slowLevel = level
+	}
+}
+
+// WithRedactArgs sets an option to transform command arguments before logging, e.g. to mask values of SET or AUTH.
+// The function must not modify the given slice, but return a new one.
+func WithRedactArgs(redact func(args []any) []any) HookOpt {
+	return func(h *Hook) {
+		h.redactArgs = redact
+	}
+}
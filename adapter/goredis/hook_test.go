@@ -0,0 +1,79 @@
+package goredis_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/networkteam/slogutils/adapter/goredis"
+)
+
+func TestHook(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if (a.Key == slog.TimeKey || a.Key == "duration") && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	errTimeout := errors.New("i/o timeout")
+	errPool := errors.New("pool exhausted")
+	hook := goredis.NewHook(logger,
+		goredis.WithSlowThreshold(10*time.Millisecond),
+		goredis.WithIgnoreErrors(func(err error) bool { return err.Error() == "ignored" }),
+	)
+
+	process := hook.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		switch cmd.Name() {
+		case "get":
+			cmd.SetErr(redis.Nil)
+		case "set":
+			cmd.SetErr(errTimeout)
+		case "del":
+			cmd.SetErr(errors.New("ignored"))
+		case "keys":
+			time.Sleep(10 * time.Millisecond)
+		case "ping":
+			// Errors of the client are not necessarily set on the command
+			return errPool
+		}
+		return cmd.Err()
+	})
+	ctx := context.Background()
+
+	_ = process(ctx, redis.NewStringCmd(ctx, "get", "missing"))
+	if err := process(ctx, redis.NewStatusCmd(ctx, "set", "key", "value")); !errors.Is(err, errTimeout) {
+		t.Fatalf("expected error to be returned, got %v", err)
+	}
+	_ = process(ctx, redis.NewIntCmd(ctx, "del", "key"))
+	_ = process(ctx, redis.NewStringSliceCmd(ctx, "keys", "*"))
+	if err := process(ctx, redis.NewStatusCmd(ctx, "ping")); !errors.Is(err, errPool) {
+		t.Fatalf("expected error of next hook to be returned, got %v", err)
+	}
+
+	pipeline := hook.ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+		return nil
+	})
+	_ = pipeline(ctx, []redis.Cmder{redis.NewStatusCmd(ctx, "ping"), redis.NewIntCmd(ctx, "incr", "counter")})
+
+	want := strings.Join([]string{
+		`level=DEBUG msg=Command cmd=get args=[missing]`,
+		`level=ERROR msg=Command err="i/o timeout" cmd=set args="[key value]"`,
+		`level=WARN msg=Command cmd=keys args=[*]`,
+		`level=ERROR msg=Command err="pool exhausted" cmd=ping args=[]`,
+		`level=DEBUG msg=Pipeline cmds="[ping incr]"`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
@@ -0,0 +1,10 @@
+package grpclog
+
+// SetOSExit replaces the function called after logging a fatal message.
+func SetOSExit(f func(code int)) (restore func()) {
+	prev := osExit
+	osExit = f
+	return func() {
+		osExit = prev
+	}
+}
@@ -0,0 +1,194 @@
+// Package grpclog provides a bridge from gRPC's internal logging to slog.
+//
+// The Logger implements grpclog.LoggerV2 and grpclog.DepthLoggerV2 without depending on the gRPC module:
+//
+//	grpclog.SetLoggerV2(slogutilsgrpclog.NewLogger(logger.With("component", "grpc")))
+package grpclog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Severity is a gRPC log severity.
+type Severity int
+
+// gRPC log severities
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+	SeverityFatal
+)
+
+// osExit is called after logging a fatal message, it is replaced in tests.
+var osExit = os.Exit
+
+// Logger is an adapter for grpclog to slog
+type Logger struct {
+	logger    *slog.Logger
+	levelsMap map[Severity]slog.Level
+}
+
+// NewLogger builds a new logger instance given a slog.Logger instance
+func NewLogger(logger *slog.Logger, opts ...LoggerOpt) *Logger {
+	l := &Logger{logger: logger}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Info logs to the info severity, implements grpclog.LoggerV2
+func (l *Logger) Info(args ...any) {
+	l.log(0, SeverityInfo, fmt.Sprint(args...))
+}
+
+// Infoln logs to the info severity, implements grpclog.LoggerV2
+func (l *Logger) Infoln(args ...any) {
+	l.log(0, SeverityInfo, sprintln(args...))
+}
+
+// Infof logs to the info severity, implements grpclog.LoggerV2
+func (l *Logger) Infof(format string, args ...any) {
+	l.log(0, SeverityInfo, fmt.Sprintf(format, args...))
+}
+
+// Warning logs to the warning severity, implements grpclog.LoggerV2
+func (l *Logger) Warning(args ...any) {
+	l.log(0, SeverityWarning, fmt.Sprint(args...))
+}
+
+// Warningln logs to the warning severity, implements grpclog.LoggerV2
+func (l *Logger) Warningln(args ...any) {
+	l.log(0, SeverityWarning, sprintln(args...))
+}
+
+// Warningf logs to the warning severity, implements grpclog.LoggerV2
+func (l *Logger) Warningf(format string, args ...any) {
+	l.log(0, SeverityWarning, fmt.Sprintf(format, args...))
+}
+
+// Error logs to the error severity, implements grpclog.LoggerV2
+func (l *Logger) Error(args ...any) {
+	l.log(0, SeverityError, fmt.Sprint(args...))
+}
+
+// Errorln logs to the error severity, implements grpclog.LoggerV2
+func (l *Logger) Errorln(args ...any) {
+	l.log(0, SeverityError, sprintln(args...))
+}
+
+// Errorf logs to the error severity, implements grpclog.LoggerV2
+func (l *Logger) Errorf(format string, args ...any) {
+	l.log(0, SeverityError, fmt.Sprintf(format, args...))
+}
+
+// Fatal logs to the fatal severity and exits, implements grpclog.LoggerV2
+func (l *Logger) Fatal(args ...any) {
+	l.log(0, SeverityFatal, fmt.Sprint(args...))
+	osExit(1)
+}
+
+// Fatalln logs to the fatal severity and exits, implements grpclog.LoggerV2
+func (l *Logger) Fatalln(args ...any) {
+	l.log(0, SeverityFatal, sprintln(args...))
+	osExit(1)
+}
+
+// Fatalf logs to the fatal severity and exits, implements grpclog.LoggerV2
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.log(0, SeverityFatal, fmt.Sprintf(format, args...))
+	osExit(1)
+}
+
+// InfoDepth logs to the info severity at the given call depth, implements grpclog.DepthLoggerV2
+func (l *Logger) InfoDepth(depth int, args ...any) {
+	l.log(depth, SeverityInfo, sprintln(args...))
+}
+
+// WarningDepth logs to the warning severity at the given call depth, implements grpclog.DepthLoggerV2
+func (l *Logger) WarningDepth(depth int, args ...any) {
+	l.log(depth, SeverityWarning, sprintln(args...))
+}
+
+// ErrorDepth logs to the error severity at the given call depth, implements grpclog.DepthLoggerV2
+func (l *Logger) ErrorDepth(depth int, args ...any) {
+	l.log(depth, SeverityError, sprintln(args...))
+}
+
+// FatalDepth logs to the fatal severity at the given call depth and exits, implements grpclog.DepthLoggerV2
+func (l *Logger) FatalDepth(depth int, args ...any) {
+	l.log(depth, SeverityFatal, sprintln(args...))
+	osExit(1)
+}
+
+// V reports whether the verbosity level is enabled, implements grpclog.LoggerV2.
+// Verbosities are mapped to levels as by the logr adapter: 0 to info, 1 to debug, 2 to slogutils.LevelTrace and
+// higher verbosities to levels below trace.
+//
+// gRPC guards verbose messages with V and logs them with Info, so the verbosity is not known to Info. Use
+// WithRemapLevel(SeverityInfo, slog.LevelDebug) to log all info messages of gRPC at debug level.
+func (l *Logger) V(verbosity int) bool {
+	return l.logger.Enabled(context.Background(), VerbosityLevel(verbosity))
+}
+
+func (l *Logger) log(depth int, severity Severity, msg string) {
+	ctx := context.Background()
+	level := l.toLevel(severity)
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers, log and the exported logging method
+	runtime.Callers(3+depth, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	_ = l.logger.Handler().Handle(ctx, r)
+}
+
+func (l *Logger) toLevel(severity Severity) slog.Level {
+	if l.levelsMap != nil {
+		if mappedLevel, ok := l.levelsMap[severity]; ok {
+			return mappedLevel
+		}
+	}
+	switch severity {
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarning:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// VerbosityLevel returns the slog level of a gRPC verbosity, see Logger.V.
+func VerbosityLevel(verbosity int) slog.Level {
+	if verbosity <= 0 {
+		return slog.LevelInfo
+	}
+	return slog.LevelInfo - slog.Level(4*verbosity)
+}
+
+func sprintln(args ...any) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+// LoggerOpt sets options for the logger
+type LoggerOpt func(*Logger)
+
+// WithRemapLevel sets a mapping entry between gRPC severities and slog levels
+func WithRemapLevel(in Severity, out slog.Level) LoggerOpt {
+	return func(l *Logger) {
+		if l.levelsMap == nil {
+			l.levelsMap = make(map[Severity]slog.Level)
+		}
+		l.levelsMap[in] = out
+	}
+}
@@ -0,0 +1,136 @@
+package grpclog_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	slogutilsgrpclog "github.com/networkteam/slogutils/adapter/grpclog"
+)
+
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []slogutilsgrpclog.LoggerOpt
+		f    func(l *slogutilsgrpclog.Logger)
+		want string
+	}{
+		{
+			name: "info is logged as info",
+			f: func(l *slogutilsgrpclog.Logger) {
+				l.Info("connecting", " to ", "localhost")
+			},
+			want: `level=INFO msg="connecting to localhost"`,
+		},
+		{
+			name: "warningln is logged as warn",
+			f: func(l *slogutilsgrpclog.Logger) {
+				l.Warningln("retrying", 3)
+			},
+			want: `level=WARN msg="retrying 3"`,
+		},
+		{
+			name: "errorf is logged as error",
+			f: func(l *slogutilsgrpclog.Logger) {
+				l.Errorf("failed after %d attempts", 3)
+			},
+			want: `level=ERROR msg="failed after 3 attempts"`,
+		},
+		{
+			name: "fatal is logged as error and exits",
+			f: func(l *slogutilsgrpclog.Logger) {
+				l.Fatal("boom")
+			},
+			want: "level=ERROR msg=boom\nexit=1",
+		},
+		{
+			name: "level can be remapped",
+			opts: []slogutilsgrpclog.LoggerOpt{
+				slogutilsgrpclog.WithRemapLevel(slogutilsgrpclog.SeverityInfo, slog.LevelDebug),
+			},
+			f: func(l *slogutilsgrpclog.Logger) {
+				l.Info("noisy")
+			},
+			want: `level=DEBUG msg=noisy`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			restore := slogutilsgrpclog.SetOSExit(func(code int) {
+				buf.WriteString("exit=1\n")
+			})
+			defer restore()
+
+			logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+				Level:       slogutils.LevelTrace,
+				ReplaceAttr: drop(slog.TimeKey),
+			}))
+			tt.f(slogutilsgrpclog.NewLogger(logger, tt.opts...))
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLogger_V(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(new(bytes.Buffer), &slog.HandlerOptions{Level: slog.LevelDebug}))
+	l := slogutilsgrpclog.NewLogger(logger)
+
+	if !l.V(0) {
+		t.Error("verbosity 0 should be enabled")
+	}
+	if !l.V(1) {
+		t.Error("verbosity 1 should be enabled for debug")
+	}
+	if l.V(2) {
+		t.Error("verbosity 2 should not be enabled for debug")
+	}
+}
+
+func TestVerbosityLevel(t *testing.T) {
+	tests := []struct {
+		v    int
+		want slog.Level
+	}{
+		{v: 0, want: slog.LevelInfo},
+		{v: 1, want: slog.LevelDebug},
+		{v: 2, want: slogutils.LevelTrace},
+		{v: 3, want: slogutils.LevelTrace - 4},
+	}
+	for _, test := range tests {
+		if got := slogutilsgrpclog.VerbosityLevel(test.v); got != test.want {
+			t.Errorf("VerbosityLevel(%d): expected %v, got %v", test.v, test.want, got)
+		}
+	}
+}
+
+func TestLogger_source(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{AddSource: true}))
+	l := slogutilsgrpclog.NewLogger(logger)
+
+	l.Info("test")
+
+	if !strings.Contains(buf.String(), "grpclog/logger_test.go:") {
+		t.Fatalf("expected source to point to the caller, got: %s", buf.String())
+	}
+}
+
+// drop returns a ReplaceAttr that drops the given keys.
+func drop(keys ...string) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		for _, key := range keys {
+			if a.Key == key {
+				a = slog.Attr{}
+			}
+		}
+		return a
+	}
+}
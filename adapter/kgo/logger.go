@@ -0,0 +1,79 @@
+// Package kgo provides a kgo.Logger for the Kafka client franz-go backed by slog:
+//
+//	client, err := kgo.NewClient(kgo.WithLogger(slogutilskgo.NewLogger(logger.With("component", "kafka"))))
+package kgo
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Logger is an adapter for franz-go to slog
+type Logger struct {
+	logger    *slog.Logger
+	levelsMap map[kgo.LogLevel]slog.Level
+}
+
+var _ kgo.Logger = (*Logger)(nil)
+
+// NewLogger builds a new logger instance given a slog.Logger instance
+func NewLogger(logger *slog.Logger, opts ...LoggerOpt) *Logger {
+	l := &Logger{logger: logger}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Level returns the most verbose franz-go log level enabled by the logger, implements kgo.Logger
+func (l *Logger) Level() kgo.LogLevel {
+	ctx := context.Background()
+	for _, level := range []kgo.LogLevel{kgo.LogLevelDebug, kgo.LogLevelInfo, kgo.LogLevelWarn, kgo.LogLevelError} {
+		if l.logger.Enabled(ctx, l.toLevel(level)) {
+			return level
+		}
+	}
+	return kgo.LogLevelNone
+}
+
+// Log logs a message with key/value pairs, implements kgo.Logger
+func (l *Logger) Log(level kgo.LogLevel, msg string, keyvals ...any) {
+	if level == kgo.LogLevelNone {
+		return
+	}
+	l.logger.Log(context.Background(), l.toLevel(level), msg, keyvals...)
+}
+
+func (l *Logger) toLevel(level kgo.LogLevel) slog.Level {
+	if l.levelsMap != nil {
+		if mappedLevel, ok := l.levelsMap[level]; ok {
+			return mappedLevel
+		}
+	}
+	switch level {
+	case kgo.LogLevelDebug:
+		return slog.LevelDebug
+	case kgo.LogLevelInfo:
+		return slog.LevelInfo
+	case kgo.LogLevelWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// LoggerOpt sets options for the logger
+type LoggerOpt func(*Logger)
+
+// WithRemapLevel sets a mapping entry between franz-go log levels and slog levels,
+// e.g. to log the verbose info messages of franz-go at debug.
+func WithRemapLevel(in kgo.LogLevel, out slog.Level) LoggerOpt {
+	return func(l *Logger) {
+		if l.levelsMap == nil {
+			l.levelsMap = make(map[kgo.LogLevel]slog.Level)
+		}
+		l.levelsMap[in] = out
+	}
+}
@@ -0,0 +1,38 @@
+package kgo_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	slogutilskgo "github.com/networkteam/slogutils/adapter/kgo"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	l := slogutilskgo.NewLogger(logger, slogutilskgo.WithRemapLevel(kgo.LogLevelInfo, slog.LevelDebug))
+	if got := l.Level(); got != kgo.LogLevelWarn {
+		t.Fatalf("expected warn level, got %v", got)
+	}
+
+	l.Log(kgo.LogLevelInfo, "metadata update triggered", "why", "client initialization")
+	l.Log(kgo.LogLevelWarn, "unable to open connection to broker", "addr", "localhost:9092", "err", "connection refused")
+
+	want := `level=WARN msg="unable to open connection to broker" addr=localhost:9092 err="connection refused"`
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
@@ -0,0 +1,103 @@
+// Package logr provides a logr.LogSink on top of slog, so libraries of the Kubernetes ecosystem (e.g. client-go and
+// controller-runtime) can log through slog handlers:
+//
+//	ctrl.SetLogger(logr.New(slogutilslogr.NewSink(logger)))
+package logr
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Level returns the slog level of a logr V-level: V(0) is mapped to info, V(1) to debug, V(2) to
+// slogutils.LevelTrace and higher V-levels to levels below trace.
+func Level(v int) slog.Level {
+	return slog.LevelInfo - slog.Level(4*v)
+}
+
+// Sink implements logr.LogSink by logging to a slog.Logger.
+// Names of the logger (see logr.Logger.WithName) are joined with dots and added as the slogutils.LoggerKey attribute.
+type Sink struct {
+	logger    *slog.Logger
+	name      string
+	callDepth int
+}
+
+var (
+	_ logr.LogSink          = (*Sink)(nil)
+	_ logr.CallDepthLogSink = (*Sink)(nil)
+)
+
+// NewSink creates a new sink logging to the given logger.
+func NewSink(logger *slog.Logger) *Sink {
+	return &Sink{logger: logger}
+}
+
+// Init receives runtime info about the logr library, implements logr.LogSink
+func (s *Sink) Init(info logr.RuntimeInfo) {
+	s.callDepth = info.CallDepth
+}
+
+// Enabled checks if the V-level is enabled, implements logr.LogSink
+func (s *Sink) Enabled(level int) bool {
+	return s.logger.Enabled(context.Background(), Level(level))
+}
+
+// Info logs a message with the level of the V-level, implements logr.LogSink
+func (s *Sink) Info(level int, msg string, keysAndValues ...any) {
+	s.log(Level(level), msg, keysAndValues)
+}
+
+// Error logs an error at error level, implements logr.LogSink
+func (s *Sink) Error(err error, msg string, keysAndValues ...any) {
+	s.log(slog.LevelError, msg, append([]any{slogutils.Err(err)}, keysAndValues...))
+}
+
+func (s *Sink) log(level slog.Level, msg string, keysAndValues []any) {
+	ctx := context.Background()
+	if !s.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers, log, Info or Error and the frames of logr
+	runtime.Callers(3+s.callDepth, pcs[:])
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	// The name is added to records, since handler attributes with the same key cannot be replaced by WithName
+	if s.name != "" {
+		r.AddAttrs(slog.String(slogutils.LoggerKey, s.name))
+	}
+	r.Add(keysAndValues...)
+	_ = s.logger.Handler().Handle(ctx, r)
+}
+
+// WithValues returns a sink with additional key/value pairs, implements logr.LogSink
+func (s *Sink) WithValues(keysAndValues ...any) logr.LogSink {
+	s2 := *s // Copy sink
+	s2.logger = s.logger.With(keysAndValues...)
+	return &s2
+}
+
+// WithName returns a sink with the name appended to the name of the logger, implements logr.LogSink
+func (s *Sink) WithName(name string) logr.LogSink {
+	s2 := *s // Copy sink
+	if s.name != "" {
+		name = s.name + "." + name
+	}
+	s2.name = name
+	return &s2
+}
+
+// WithCallDepth returns a sink that skips additional stack frames for the source, implements logr.CallDepthLogSink
+func (s *Sink) WithCallDepth(depth int) logr.LogSink {
+	s2 := *s // Copy sink
+	s2.callDepth += depth
+	return &s2
+}
@@ -0,0 +1,59 @@
+package logr_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/networkteam/slogutils"
+	slogutilslogr "github.com/networkteam/slogutils/adapter/logr"
+)
+
+func TestSink(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	l := logr.New(slogutilslogr.NewSink(logger)).WithName("controller").WithName("pod").WithValues("namespace", "default")
+
+	l.Info("Reconciling", "pod", "app-1")
+	l.V(1).Info("Fetched pod")
+	l.V(2).Info("Not logged")
+	l.Error(errors.New("conflict"), "Update failed", "retry", true)
+
+	want := strings.Join([]string{
+		`level=INFO msg=Reconciling namespace=default logger=controller.pod pod=app-1`,
+		`level=DEBUG msg="Fetched pod" namespace=default logger=controller.pod`,
+		`level=ERROR msg="Update failed" namespace=default logger=controller.pod err=conflict retry=true`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		v    int
+		want slog.Level
+	}{
+		{v: 0, want: slog.LevelInfo},
+		{v: 1, want: slog.LevelDebug},
+		{v: 2, want: slogutils.LevelTrace},
+	}
+	for _, test := range tests {
+		if got := slogutilslogr.Level(test.v); got != test.want {
+			t.Errorf("Level(%d): expected %v, got %v", test.v, test.want, got)
+		}
+	}
+}
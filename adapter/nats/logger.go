@@ -0,0 +1,145 @@
+// Package nats logs connection events of nats.go to slog:
+//
+//	nc, err := nats.Connect(url, slogutilsnats.NewLogger(logger.With("component", "nats")).Options()...)
+package nats
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Event is a connection event of nats.go
+type Event string
+
+// Connection events of nats.go
+const (
+	EventDisconnect       Event = "Disconnected"
+	EventReconnect        Event = "Reconnected"
+	EventClosed           Event = "Connection closed"
+	EventError            Event = "Async error"
+	EventSlowConsumer     Event = "Slow consumer"
+	EventLameDuck         Event = "Server entered lame duck mode"
+	EventDiscoveredServer Event = "Discovered servers"
+)
+
+// Logger logs connection events of nats.go, the event is used as the message.
+// By default errors are logged at error level, slow consumers, disconnects with an error and lame duck mode at warn
+// level, discovered servers at debug level and all other events at info level.
+type Logger struct {
+	logger    *slog.Logger
+	levelsMap map[Event]slog.Level
+}
+
+// NewLogger builds a new logger instance given a slog.Logger instance
+func NewLogger(logger *slog.Logger, opts ...LoggerOpt) *Logger {
+	l := &Logger{logger: logger}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Options returns options for nats.Connect that set all event handlers of the logger.
+// Use the handler methods directly to combine them with custom handlers.
+func (l *Logger) Options() []nats.Option {
+	return []nats.Option{
+		nats.DisconnectErrHandler(l.DisconnectErrHandler),
+		nats.ReconnectHandler(l.ReconnectHandler),
+		nats.ClosedHandler(l.ClosedHandler),
+		nats.ErrorHandler(l.ErrorHandler),
+		nats.LameDuckModeHandler(l.LameDuckModeHandler),
+		nats.DiscoveredServersHandler(l.DiscoveredServersHandler),
+	}
+}
+
+// DisconnectErrHandler logs a disconnect, implements nats.ConnErrHandler
+func (l *Logger) DisconnectErrHandler(nc *nats.Conn, err error) {
+	level := slog.LevelInfo
+	var attrs []slog.Attr
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slogutils.Err(err))
+	}
+	l.log(EventDisconnect, level, nc, attrs...)
+}
+
+// ReconnectHandler logs a reconnect, implements nats.ConnHandler
+func (l *Logger) ReconnectHandler(nc *nats.Conn) {
+	l.log(EventReconnect, slog.LevelInfo, nc, slog.Uint64("reconnects", nc.Stats().Reconnects))
+}
+
+// ClosedHandler logs a closed connection, implements nats.ConnHandler
+func (l *Logger) ClosedHandler(nc *nats.Conn) {
+	var attrs []slog.Attr
+	if err := nc.LastError(); err != nil {
+		attrs = append(attrs, slogutils.Err(err))
+	}
+	l.log(EventClosed, slog.LevelInfo, nc, attrs...)
+}
+
+// ErrorHandler logs an asynchronous error (e.g. a slow consumer) with the subject of the subscription,
+// implements nats.ErrHandler
+func (l *Logger) ErrorHandler(nc *nats.Conn, sub *nats.Subscription, err error) {
+	event, level := EventError, slog.LevelError
+	if errors.Is(err, nats.ErrSlowConsumer) {
+		event, level = EventSlowConsumer, slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{slogutils.Err(err)}
+	if sub != nil {
+		attrs = append(attrs, slog.String("subject", sub.Subject))
+		if sub.Queue != "" {
+			attrs = append(attrs, slog.String("queue", sub.Queue))
+		}
+		if event == EventSlowConsumer {
+			if msgs, bytes, err := sub.Pending(); err == nil {
+				attrs = append(attrs, slog.Int("pending_msgs", msgs), slog.Int("pending_bytes", bytes))
+			}
+		}
+	}
+	l.log(event, level, nc, attrs...)
+}
+
+// LameDuckModeHandler logs that the server entered lame duck mode, implements nats.ConnHandler
+func (l *Logger) LameDuckModeHandler(nc *nats.Conn) {
+	l.log(EventLameDuck, slog.LevelWarn, nc)
+}
+
+// DiscoveredServersHandler logs discovered servers, implements nats.ConnHandler
+func (l *Logger) DiscoveredServersHandler(nc *nats.Conn) {
+	l.log(EventDiscoveredServer, slog.LevelDebug, nc, slog.Any("servers", nc.DiscoveredServers()))
+}
+
+func (l *Logger) log(event Event, level slog.Level, nc *nats.Conn, attrs ...slog.Attr) {
+	if mappedLevel, ok := l.levelsMap[event]; ok {
+		level = mappedLevel
+	}
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	if nc != nil {
+		if url := nc.ConnectedUrlRedacted(); url != "" {
+			attrs = append(attrs, slog.String("server", url))
+		}
+	}
+	l.logger.LogAttrs(ctx, level, string(event), attrs...)
+}
+
+// LoggerOpt sets options for the logger
+type LoggerOpt func(*Logger)
+
+// WithRemapLevel sets the level for events of the given kind, e.g. to log reconnects at warn level
+func WithRemapLevel(event Event, level slog.Level) LoggerOpt {
+	return func(l *Logger) {
+		if l.levelsMap == nil {
+			l.levelsMap = make(map[Event]slog.Level)
+		}
+		l.levelsMap[event] = level
+	}
+}
@@ -0,0 +1,43 @@
+package nats_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+
+	slogutilsnats "github.com/networkteam/slogutils/adapter/nats"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	l := slogutilsnats.NewLogger(logger, slogutilsnats.WithRemapLevel(slogutilsnats.EventLameDuck, slog.LevelError))
+
+	// A zero connection is not connected, so no server is added
+	nc := &nats.Conn{}
+	l.DisconnectErrHandler(nc, errors.New("connection reset"))
+	l.ErrorHandler(nc, &nats.Subscription{Subject: "orders.created", Queue: "workers"}, errors.New("permissions violation"))
+	l.LameDuckModeHandler(nc)
+	l.DiscoveredServersHandler(nc)
+
+	want := strings.Join([]string{
+		`level=WARN msg=Disconnected err="connection reset"`,
+		`level=ERROR msg="Async error" err="permissions violation" subject=orders.created queue=workers`,
+		`level=ERROR msg="Server entered lame duck mode"`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"slices"
 	"sort"
+	"unicode/utf8"
 
 	"github.com/jackc/pgx/v5/tracelog"
 
@@ -13,11 +14,29 @@ import (
 
 // Logger is an adapter for pgx tracelog to slog
 type Logger struct {
-	logger       *slog.Logger
-	ignoreErrors func(err error) bool
-	levelsMap    map[tracelog.LogLevel]slog.Level
+	logger        *slog.Logger
+	contextLogger bool
+	ignoreErrors  func(err error) bool
+	levelsMap     map[tracelog.LogLevel]slog.Level
+	messageLevels map[MessageKind]slog.Level
+	suppressed    map[MessageKind]bool
+	truncateSQL   int
+	redactArgs    func(args []any) []any
 }
 
+// MessageKind is the kind of message logged by pgx tracelog
+type MessageKind string
+
+// Message kinds of pgx tracelog
+const (
+	MessageQuery      MessageKind = "Query"
+	MessageBatchQuery MessageKind = "BatchQuery"
+	MessageBatchClose MessageKind = "BatchClose"
+	MessageCopyFrom   MessageKind = "CopyFrom"
+	MessageConnect    MessageKind = "Connect"
+	MessagePrepare    MessageKind = "Prepare"
+)
+
 // NewLogger builds a new logger instance given a slog.Logger instance
 func NewLogger(logger *slog.Logger, opts ...LoggerOpt) *Logger {
 	l := &Logger{logger: logger}
@@ -29,8 +48,19 @@ func NewLogger(logger *slog.Logger, opts ...LoggerOpt) *Logger {
 
 // Log a pgx log message to the underlying log instance, implements tracelog.Logger
 func (l *Logger) Log(ctx context.Context, level tracelog.LogLevel, msg string, data map[string]interface{}) {
+	logger := l.loggerFor(ctx)
+
+	// Errors are never suppressed or remapped by message kind
+	kind := MessageKind(msg)
+	if level != tracelog.LogLevelError && l.suppressed[kind] {
+		return
+	}
+
 	lvl, levelOK := l.toLevel(level)
-	if !l.logger.Enabled(ctx, lvl) {
+	if mappedLevel, ok := l.messageLevels[kind]; ok && level != tracelog.LogLevelError {
+		lvl = mappedLevel
+	}
+	if !logger.Enabled(ctx, lvl) {
 		return
 	}
 
@@ -44,7 +74,14 @@ func (l *Logger) Log(ctx context.Context, level tracelog.LogLevel, msg string, d
 		attrs = append(attrs, slog.Any("INVALID_PGX_LOG_LEVEL", level))
 	}
 
-	l.logger.LogAttrs(ctx, lvl, msg, attrs...)
+	logger.LogAttrs(ctx, lvl, msg, attrs...)
+}
+
+func (l *Logger) loggerFor(ctx context.Context) *slog.Logger {
+	if l.contextLogger {
+		return slogutils.FromContext(ctx)
+	}
+	return l.logger
 }
 
 func (l *Logger) buildAttrs(data map[string]any) []slog.Attr {
@@ -63,13 +100,39 @@ func (l *Logger) buildAttrs(data map[string]any) []slog.Attr {
 	var attrs []slog.Attr
 	for _, k := range allKeys {
 		if v, ok := data[k]; ok {
-			attrs = append(attrs, slog.Any(k, v))
+			attrs = append(attrs, slog.Any(k, l.sanitize(k, v)))
 		}
 	}
 
 	return attrs
 }
 
+func (l *Logger) sanitize(key string, v any) any {
+	switch key {
+	case "sql":
+		if sql, ok := v.(string); ok && l.truncateSQL > 0 {
+			return truncate(sql, l.truncateSQL)
+		}
+	case "args":
+		if args, ok := v.([]any); ok && l.redactArgs != nil {
+			return l.redactArgs(args)
+		}
+	}
+	return v
+}
+
+// truncate shortens s to at most maxLen bytes (without splitting a UTF-8 sequence) and adds an ellipsis
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
+
 func (l *Logger) toLevel(level tracelog.LogLevel) (slog.Level, bool) {
 	if l.levelsMap != nil {
 		if mappedLevel, ok := l.levelsMap[level]; ok {
@@ -102,6 +165,54 @@ func WithIgnoreErrors(matcher func(err error) bool) LoggerOpt {
 	}
 }
 
+// WithContextLogger sets an option to use the logger from the context (see slogutils.FromContext) instead of the
+// logger given to NewLogger, so attributes of the context logger (e.g. a request ID) are added automatically.
+// The logger given to NewLogger is ignored and can be nil.
+func WithContextLogger() LoggerOpt {
+	return func(l *Logger) {
+		l.contextLogger = true
+	}
+}
+
+// WithMessageLevel sets the level for messages of the given kind, e.g. to log Connect at trace but Query at debug.
+// Messages logged by pgx with the error level are not affected.
+func WithMessageLevel(kind MessageKind, level slog.Level) LoggerOpt {
+	return func(l *Logger) {
+		if l.messageLevels == nil {
+			l.messageLevels = make(map[MessageKind]slog.Level)
+		}
+		l.messageLevels[kind] = level
+	}
+}
+
+// WithSuppressMessages sets an option to drop messages of the given kinds.
+// Messages logged by pgx with the error level are not affected.
+func WithSuppressMessages(kinds ...MessageKind) LoggerOpt {
+	return func(l *Logger) {
+		if l.suppressed == nil {
+			l.suppressed = make(map[MessageKind]bool)
+		}
+		for _, kind := range kinds {
+			l.suppressed[kind] = true
+		}
+	}
+}
+
+// WithTruncateSQL sets an option to truncate SQL statements longer than maxLen bytes
+func WithTruncateSQL(maxLen int) LoggerOpt {
+	return func(l *Logger) {
+		l.truncateSQL = maxLen
+	}
+}
+
+// WithRedactArgs sets an option to transform query arguments before logging, e.g. to mask passwords or tokens.
+// The function must not modify the given slice, but return a new one.
+func WithRedactArgs(redact func(args []any) []any) LoggerOpt {
+	return func(l *Logger) {
+		l.redactArgs = redact
+	}
+}
+
 // WithRemapLevel sets a mapping entry between pgx log levels and slog levels
 func WithRemapLevel(in tracelog.LogLevel, out slog.Level) LoggerOpt {
 	return func(l *Logger) {
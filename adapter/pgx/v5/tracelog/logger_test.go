@@ -8,10 +8,10 @@ import (
 	"testing"
 
 	"github.com/jackc/pgx/v5/tracelog"
-	"github.com/vgarvardt/slogex/observer"
 
 	"github.com/networkteam/slogutils"
 	logutilstracelog "github.com/networkteam/slogutils/adapter/pgx/v5/tracelog"
+	"github.com/networkteam/slogutils/observer"
 )
 
 func TestLogger_Log(t *testing.T) {
@@ -25,9 +25,11 @@ func TestLogger_Log(t *testing.T) {
 	tests := []struct {
 		name        string
 		applyLogger func(logger *slog.Logger) *slog.Logger
-		args        args
-		opts        []logutilstracelog.LoggerOpt
-		expected    *observer.LoggedRecord
+		// contextLogger sets the logger in the context instead of passing it to NewLogger
+		contextLogger bool
+		args          args
+		opts          []logutilstracelog.LoggerOpt
+		expected      *observer.LoggedRecord
 	}{
 		{
 			name: "pgx trace is logged as trace",
@@ -179,7 +181,135 @@ func TestLogger_Log(t *testing.T) {
 					Level:   slog.LevelInfo,
 					Message: "Hey, it's a test",
 				},
-				Attrs: []slog.Attr{slog.String("foo", "bar"), slog.String("component", "driver.sql")},
+				Attrs: []slog.Attr{slog.String("component", "driver.sql"), slog.String("foo", "bar")},
+			},
+		},
+		{
+			name: "logger from context is used",
+			opts: []logutilstracelog.LoggerOpt{
+				logutilstracelog.WithContextLogger(),
+			},
+			applyLogger: func(logger *slog.Logger) *slog.Logger {
+				return logger.With("request_id", "abc")
+			},
+			contextLogger: true,
+			args: args{
+				level: tracelog.LogLevelInfo,
+				msg:   "Hey, it's a test",
+				data: map[string]any{
+					"foo": "bar",
+				},
+			},
+			expected: &observer.LoggedRecord{
+				Record: slog.Record{
+					Level:   slog.LevelInfo,
+					Message: "Hey, it's a test",
+				},
+				Attrs: []slog.Attr{slog.String("request_id", "abc"), slog.String("foo", "bar")},
+			},
+		},
+		{
+			name: "message kind level can be set",
+			opts: []logutilstracelog.LoggerOpt{
+				logutilstracelog.WithMessageLevel(logutilstracelog.MessageConnect, slogutils.LevelTrace),
+			},
+			args: args{
+				level: tracelog.LogLevelInfo,
+				msg:   "Connect",
+				data: map[string]any{
+					"host": "localhost",
+				},
+			},
+			expected: &observer.LoggedRecord{
+				Record: slog.Record{
+					Level:   slogutils.LevelTrace,
+					Message: "Connect",
+				},
+				Attrs: []slog.Attr{slog.String("host", "localhost")},
+			},
+		},
+		{
+			name: "message kind level does not change errors",
+			opts: []logutilstracelog.LoggerOpt{
+				logutilstracelog.WithMessageLevel(logutilstracelog.MessageQuery, slog.LevelDebug),
+			},
+			args: args{
+				level: tracelog.LogLevelError,
+				msg:   "Query",
+				data: map[string]any{
+					"err": testErr,
+				},
+			},
+			expected: &observer.LoggedRecord{
+				Record: slog.Record{
+					Level:   slog.LevelError,
+					Message: "Query",
+				},
+				Attrs: []slog.Attr{slog.Any("err", testErr)},
+			},
+		},
+		{
+			name: "message kind can be suppressed",
+			opts: []logutilstracelog.LoggerOpt{
+				logutilstracelog.WithSuppressMessages(logutilstracelog.MessagePrepare),
+			},
+			args: args{
+				level: tracelog.LogLevelInfo,
+				msg:   "Prepare",
+				data: map[string]any{
+					"sql": "SELECT 1",
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "sql is truncated",
+			opts: []logutilstracelog.LoggerOpt{
+				logutilstracelog.WithTruncateSQL(8),
+			},
+			args: args{
+				level: tracelog.LogLevelInfo,
+				msg:   "Query",
+				data: map[string]any{
+					"sql": "SELECT * FROM users",
+				},
+			},
+			expected: &observer.LoggedRecord{
+				Record: slog.Record{
+					Level:   slog.LevelInfo,
+					Message: "Query",
+				},
+				Attrs: []slog.Attr{slog.String("sql", "SELECT *…")},
+			},
+		},
+		{
+			name: "args are redacted",
+			opts: []logutilstracelog.LoggerOpt{
+				logutilstracelog.WithRedactArgs(func(args []any) []any {
+					redacted := make([]any, len(args))
+					for i := range args {
+						redacted[i] = "***"
+					}
+					return redacted
+				}),
+			},
+			args: args{
+				level: tracelog.LogLevelInfo,
+				msg:   "Query",
+				data: map[string]any{
+					"sql":  "UPDATE users SET password = $1",
+					"args": []any{"secret"},
+				},
+			},
+			expected: &observer.LoggedRecord{
+				Record: slog.Record{
+					Level:   slog.LevelInfo,
+					Message: "Query",
+				},
+				Attrs: []slog.Attr{
+					slog.String("sql", "UPDATE users SET password = $1"),
+					slog.Any("args", []any{"***"}),
+				},
 			},
 		},
 		{
@@ -215,8 +345,14 @@ func TestLogger_Log(t *testing.T) {
 				logger = tt.applyLogger(logger)
 			}
 
+			ctx := context.Background()
+			if tt.contextLogger {
+				ctx = slogutils.WithLogger(ctx, logger)
+				logger = nil
+			}
+
 			p := logutilstracelog.NewLogger(logger, tt.opts...)
-			p.Log(context.Background(), tt.args.level, tt.args.msg, tt.args.data)
+			p.Log(ctx, tt.args.level, tt.args.msg, tt.args.data)
 
 			logs := observedLogs.All()
 
@@ -0,0 +1,78 @@
+// Package prometheus exposes the record counts of a metrics.Handler as a Prometheus collector.
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/networkteam/slogutils/metrics"
+)
+
+const defaultName = "log_records_total"
+
+// Options are options for a Collector.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Namespace and Subsystem are prefixes of the metric name.
+	Namespace string
+	Subsystem string
+
+	// Name of the counter metric, defaults to "log_records_total".
+	Name string
+
+	// ConstLabels are added to the metric.
+	ConstLabels prometheus.Labels
+}
+
+// Collector exposes record counts as a counter with a level label (and a component label, if the handler counts
+// records per component). The rate of records with level "error" is a simple alerting signal:
+//
+//	rate(log_records_total{level="error"}[5m]) > 0
+type Collector struct {
+	handler   *metrics.Handler
+	desc      *prometheus.Desc
+	component bool
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates a collector for the counts of the handler. Options can be nil.
+func NewCollector(h *metrics.Handler, opts *Options) *Collector {
+	if opts == nil {
+		opts = &Options{}
+	}
+	name := opts.Name
+	if name == "" {
+		name = defaultName
+	}
+
+	labels := []string{"level"}
+	component := h.ComponentKey() != ""
+	if component {
+		labels = append(labels, "component")
+	}
+
+	return &Collector{
+		handler: h,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(opts.Namespace, opts.Subsystem, name),
+			"Number of log records by level.",
+			labels,
+			opts.ConstLabels,
+		),
+		component: component,
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, count := range c.handler.Counts() {
+		labelValues := []string{metrics.LevelName(count.Level)}
+		if c.component {
+			labelValues = append(labelValues, count.Component)
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(count.Value), labelValues...)
+	}
+}
@@ -0,0 +1,37 @@
+package prometheus_test
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/networkteam/slogutils/metrics"
+
+	slogprometheus "github.com/networkteam/slogutils/adapter/prometheus"
+)
+
+func TestCollector(t *testing.T) {
+	h := metrics.NewHandler(slog.NewTextHandler(io.Discard, nil), &metrics.Options{ComponentKey: "component"})
+	logger := slog.New(h)
+
+	logger.Info("test")
+	logger.Error("test", "component", "db")
+	logger.Error("test", "component", "db")
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(slogprometheus.NewCollector(h, &slogprometheus.Options{Namespace: "app"}))
+
+	want := `
+# HELP app_log_records_total Number of log records by level.
+# TYPE app_log_records_total counter
+app_log_records_total{component="",level="info"} 1
+app_log_records_total{component="db",level="error"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,79 @@
+// Package resty provides a bridge from the logging of go-resty to slog.
+//
+// The Logger implements resty.Logger without depending on the resty module:
+//
+//	client := resty.New().SetLogger(slogutilsresty.NewLogger(logger.With("component", "http")))
+package resty
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Logger is an adapter for resty to slog
+type Logger struct {
+	logger    *slog.Logger
+	levelsMap map[slog.Level]slog.Level
+}
+
+// NewLogger builds a new logger instance given a slog.Logger instance
+func NewLogger(logger *slog.Logger, opts ...LoggerOpt) *Logger {
+	l := &Logger{logger: logger}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Errorf logs at error level, implements resty.Logger
+func (l *Logger) Errorf(format string, v ...any) {
+	l.log(slog.LevelError, format, v)
+}
+
+// Warnf logs at warn level, implements resty.Logger
+func (l *Logger) Warnf(format string, v ...any) {
+	l.log(slog.LevelWarn, format, v)
+}
+
+// Debugf logs at debug level, implements resty.Logger
+func (l *Logger) Debugf(format string, v ...any) {
+	l.log(slog.LevelDebug, format, v)
+}
+
+func (l *Logger) log(level slog.Level, format string, v []any) {
+	if mappedLevel, ok := l.levelsMap[level]; ok {
+		level = mappedLevel
+	}
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers, log and the exported logging method
+	runtime.Callers(3, pcs[:])
+	// resty prefixes messages with the log level and adds newlines
+	msg := strings.TrimSpace(fmt.Sprintf(format, v...))
+	for _, prefix := range []string{"ERROR", "WARN", "DEBUG"} {
+		msg = strings.TrimPrefix(msg, prefix+" ")
+	}
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	_ = l.logger.Handler().Handle(ctx, r)
+}
+
+// LoggerOpt sets options for the logger
+type LoggerOpt func(*Logger)
+
+// WithRemapLevel sets a mapping entry between levels of resty and slog levels
+func WithRemapLevel(in, out slog.Level) LoggerOpt {
+	return func(l *Logger) {
+		if l.levelsMap == nil {
+			l.levelsMap = make(map[slog.Level]slog.Level)
+		}
+		l.levelsMap[in] = out
+	}
+}
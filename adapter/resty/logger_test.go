@@ -0,0 +1,37 @@
+package resty_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	slogutilsresty "github.com/networkteam/slogutils/adapter/resty"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	l := slogutilsresty.NewLogger(logger, slogutilsresty.WithRemapLevel(slog.LevelDebug, slog.LevelInfo))
+
+	l.Errorf("ERROR %v, Attempt %v", "connection refused", 2)
+	l.Warnf("Using Basic Auth in HTTP mode is not secure, use HTTPS\n")
+	l.Debugf("\n==============================================================================\n~~~ REQUEST ~~~\n")
+
+	want := strings.Join([]string{
+		`level=ERROR msg="connection refused, Attempt 2"`,
+		`level=WARN msg="Using Basic Auth in HTTP mode is not secure, use HTTPS"`,
+		`level=INFO msg="==============================================================================\n~~~ REQUEST ~~~"`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
@@ -0,0 +1,108 @@
+// Package retryablehttp provides a bridge from the logging of hashicorp/go-retryablehttp to slog.
+//
+// The Logger implements retryablehttp.LeveledLogger without depending on the retryablehttp module:
+//
+//	client := retryablehttp.NewClient()
+//	client.Logger = slogutilsretryablehttp.NewLogger(logger.With("component", "http"))
+package retryablehttp
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Logger is an adapter for retryablehttp to slog.
+// Key/value pairs are added as attributes, an error value with the key "error" is added as slogutils.ErrorKey.
+type Logger struct {
+	logger    *slog.Logger
+	levelsMap map[slog.Level]slog.Level
+}
+
+// NewLogger builds a new logger instance given a slog.Logger instance
+func NewLogger(logger *slog.Logger, opts ...LoggerOpt) *Logger {
+	l := &Logger{logger: logger}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Error logs at error level, implements retryablehttp.LeveledLogger
+func (l *Logger) Error(msg string, keysAndValues ...any) {
+	l.log(slog.LevelError, msg, keysAndValues)
+}
+
+// Warn logs at warn level, implements retryablehttp.LeveledLogger
+func (l *Logger) Warn(msg string, keysAndValues ...any) {
+	l.log(slog.LevelWarn, msg, keysAndValues)
+}
+
+// Info logs at info level, implements retryablehttp.LeveledLogger
+func (l *Logger) Info(msg string, keysAndValues ...any) {
+	l.log(slog.LevelInfo, msg, keysAndValues)
+}
+
+// Debug logs at debug level, implements retryablehttp.LeveledLogger
+func (l *Logger) Debug(msg string, keysAndValues ...any) {
+	l.log(slog.LevelDebug, msg, keysAndValues)
+}
+
+func (l *Logger) log(level slog.Level, msg string, keysAndValues []any) {
+	if mappedLevel, ok := l.levelsMap[level]; ok {
+		level = mappedLevel
+	}
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers, log and the exported logging method
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(attrs(keysAndValues)...)
+	_ = l.logger.Handler().Handle(ctx, r)
+}
+
+// attrs converts key/value pairs to attributes.
+func attrs(keysAndValues []any) []slog.Attr {
+	as := make([]slog.Attr, 0, len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok || i+1 == len(keysAndValues) {
+			// Keep malformed pairs as slog does
+			r := slog.Record{}
+			r.Add(keysAndValues[i:]...)
+			r.Attrs(func(a slog.Attr) bool {
+				as = append(as, a)
+				return true
+			})
+			break
+		}
+		value := keysAndValues[i+1]
+		if err, ok := value.(error); ok && key == "error" {
+			as = append(as, slogutils.Err(err))
+			continue
+		}
+		as = append(as, slog.Any(key, value))
+	}
+	return as
+}
+
+// LoggerOpt sets options for the logger
+type LoggerOpt func(*Logger)
+
+// WithRemapLevel sets a mapping entry between levels of retryablehttp and slog levels,
+// e.g. to log the debug messages of every request at trace level.
+func WithRemapLevel(in, out slog.Level) LoggerOpt {
+	return func(l *Logger) {
+		if l.levelsMap == nil {
+			l.levelsMap = make(map[slog.Level]slog.Level)
+		}
+		l.levelsMap[in] = out
+	}
+}
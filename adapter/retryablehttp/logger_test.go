@@ -0,0 +1,43 @@
+package retryablehttp_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+	slogutilsretryablehttp "github.com/networkteam/slogutils/adapter/retryablehttp"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slogutils.LevelTrace,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	l := slogutilsretryablehttp.NewLogger(logger, slogutilsretryablehttp.WithRemapLevel(slog.LevelDebug, slogutils.LevelTrace))
+
+	l.Debug("performing request", "method", "GET", "url", "https://example.com")
+	l.Error("request failed", "error", errors.New("connection refused"), "method", "GET")
+	l.Warn("retrying request", "timeout", time.Second, "remaining", 3)
+	l.Info("malformed", "key")
+
+	want := strings.Join([]string{
+		`level=DEBUG-4 msg="performing request" method=GET url=https://example.com`,
+		`level=ERROR msg="request failed" err="connection refused" method=GET`,
+		`level=WARN msg="retrying request" timeout=1s remaining=3`,
+		`level=INFO msg=malformed !BADKEY=key`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
@@ -0,0 +1,49 @@
+// Package river provides a worker middleware for River that logs jobs with joblog:
+//
+//	client, err := river.NewClient(riverpgxv5.New(pool), &river.Config{
+//		WorkerMiddleware: []rivertype.WorkerMiddleware{slogutilsriver.NewMiddleware(logger, nil)},
+//	})
+//
+// Workers get the job logger with slogutils.FromContext.
+package river
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/joblog"
+)
+
+// Middleware is a River worker middleware running jobs with joblog.Run.
+type Middleware struct {
+	river.WorkerMiddlewareDefaults
+
+	logger *slog.Logger
+	opts   *joblog.Options
+}
+
+var _ rivertype.WorkerMiddleware = (*Middleware)(nil)
+
+// NewMiddleware creates a new middleware. The logger is the parent of job loggers, if it is nil the logger
+// of the context is used (see slogutils.FromContext).
+func NewMiddleware(logger *slog.Logger, opts *joblog.Options) *Middleware {
+	return &Middleware{logger: logger, opts: opts}
+}
+
+// Work runs the job with a job logger, implements rivertype.WorkerMiddleware
+func (m *Middleware) Work(ctx context.Context, job *rivertype.JobRow, doInner func(ctx context.Context) error) error {
+	if m.logger != nil {
+		ctx = slogutils.WithLogger(ctx, m.logger)
+	}
+	return joblog.Run(ctx, joblog.Job{
+		ID:      strconv.FormatInt(job.ID, 10),
+		Kind:    job.Kind,
+		Attempt: job.Attempt,
+		Queue:   job.Queue,
+	}, m.opts, doInner)
+}
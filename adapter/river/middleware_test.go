@@ -0,0 +1,46 @@
+package river_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/riverqueue/river/rivertype"
+
+	"github.com/networkteam/slogutils"
+	slogutilsriver "github.com/networkteam/slogutils/adapter/river"
+)
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.TimeKey, slogutils.StartKey, slogutils.DurationKey:
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	m := slogutilsriver.NewMiddleware(logger, nil)
+	job := &rivertype.JobRow{ID: 7, Kind: "sort", Attempt: 1, Queue: "default"}
+	err := m.Work(context.Background(), job, func(ctx context.Context) error {
+		slogutils.FromContext(ctx).Info("Sorting")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		`level=INFO msg=Sorting job_id=7 job_kind=sort job_attempt=1 job_queue=default`,
+		`level=INFO msg="Job completed" job_id=7 job_kind=sort job_attempt=1 job_queue=default`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
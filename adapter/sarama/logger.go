@@ -0,0 +1,97 @@
+// Package sarama provides a bridge from the logging of the Kafka client sarama to slog.
+//
+// The Logger implements sarama.StdLogger without depending on the sarama module:
+//
+//	sarama.Logger = slogutilssarama.NewLogger(logger.With("component", "kafka"))
+//	sarama.DebugLogger = slogutilssarama.NewLogger(logger.With("component", "kafka"), slogutilssarama.WithLevel(slog.LevelDebug))
+package sarama
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Logger is an adapter for sarama to slog
+type Logger struct {
+	logger        *slog.Logger
+	level         slog.Level
+	messageLevels []messageLevel
+}
+
+type messageLevel struct {
+	prefix string
+	level  slog.Level
+}
+
+// NewLogger builds a new logger instance given a slog.Logger instance.
+// Messages are logged at info level by default, see WithLevel.
+func NewLogger(logger *slog.Logger, opts ...LoggerOpt) *Logger {
+	l := &Logger{logger: logger, level: slog.LevelInfo}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Print logs a message, implements sarama.StdLogger
+func (l *Logger) Print(v ...any) {
+	l.log(fmt.Sprint(v...))
+}
+
+// Printf logs a message, implements sarama.StdLogger
+func (l *Logger) Printf(format string, v ...any) {
+	l.log(fmt.Sprintf(format, v...))
+}
+
+// Println logs a message, implements sarama.StdLogger
+func (l *Logger) Println(v ...any) {
+	l.log(fmt.Sprintln(v...))
+}
+
+func (l *Logger) log(msg string) {
+	// sarama adds newlines to most messages
+	msg = strings.TrimRight(msg, "\n")
+
+	ctx := context.Background()
+	level := l.toLevel(msg)
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers, log and the exported logging method
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	_ = l.logger.Handler().Handle(ctx, r)
+}
+
+func (l *Logger) toLevel(msg string) slog.Level {
+	for _, ml := range l.messageLevels {
+		if strings.HasPrefix(msg, ml.prefix) {
+			return ml.level
+		}
+	}
+	return l.level
+}
+
+// LoggerOpt sets options for the logger
+type LoggerOpt func(*Logger)
+
+// WithLevel sets the level of messages, e.g. slog.LevelDebug for sarama.DebugLogger
+func WithLevel(level slog.Level) LoggerOpt {
+	return func(l *Logger) {
+		l.level = level
+	}
+}
+
+// WithMessageLevel sets the level for messages starting with the given prefix, e.g. to log the frequent
+// "client/metadata" messages at trace. The first matching prefix is used.
+func WithMessageLevel(prefix string, level slog.Level) LoggerOpt {
+	return func(l *Logger) {
+		l.messageLevels = append(l.messageLevels, messageLevel{prefix: prefix, level: level})
+	}
+}
@@ -0,0 +1,51 @@
+package sarama_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	slogutilssarama "github.com/networkteam/slogutils/adapter/sarama"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level:     slogutils.LevelTrace,
+		AddSource: true,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			if a.Key == slog.SourceKey {
+				source := a.Value.Any().(*slog.Source)
+				if !strings.HasSuffix(source.File, "logger_test.go") {
+					t.Errorf("expected source in test file, got %s", source.File)
+				}
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	l := slogutilssarama.NewLogger(logger, slogutilssarama.WithMessageLevel("client/metadata", slogutils.LevelTrace))
+	l.Printf("Connected to broker at %s (registered as #%d)\n", "localhost:9092", 1)
+	l.Println("client/metadata fetching metadata for all topics from broker", "localhost:9092")
+	l.Print("Closing Client")
+
+	debugLogger := slogutilssarama.NewLogger(logger, slogutilssarama.WithLevel(slog.LevelDebug))
+	debugLogger.Print("Consumer group rebalance")
+
+	want := strings.Join([]string{
+		`level=INFO msg="Connected to broker at localhost:9092 (registered as #1)"`,
+		`level=DEBUG-4 msg="client/metadata fetching metadata for all topics from broker localhost:9092"`,
+		`level=INFO msg="Closing Client"`,
+		`level=DEBUG msg="Consumer group rebalance"`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
@@ -0,0 +1,281 @@
+// Package sentry provides a handler forwarding records to Sentry.
+package sentry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/networkteam/slogutils"
+)
+
+const defaultFlushTimeout = 2 * time.Second
+
+// Options are options for a Handler.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Level is the minimum level of records forwarded to Sentry.
+	// If Level is nil, slog.LevelError is used.
+	Level slog.Leveler
+
+	// Hub is the Sentry hub to capture events with.
+	// If Hub is nil, the hub from the context or sentry.CurrentHub() is used.
+	Hub *sentry.Hub
+
+	// TagKeys are keys of attributes that are converted to event tags instead of extras.
+	TagKeys []string
+
+	// FlushTimeout is the maximum duration Close waits for events to be sent, defaults to 2 seconds.
+	FlushTimeout time.Duration
+}
+
+// Handler forwards records to Sentry and passes all records to the wrapped handler (if not nil).
+// Attributes become event extras (or tags, see Options.TagKeys), the error attribute (see slogutils.Err)
+// is converted to an exception for grouping.
+type Handler struct {
+	next    slog.Handler
+	opts    Options
+	tagKeys map[string]bool
+	goas    []slogutils.GroupOrAttrs
+}
+
+var (
+	_ slog.Handler      = (*Handler)(nil)
+	_ slogutils.Wrapper = (*Handler)(nil)
+)
+
+// NewHandler creates a new Sentry handler wrapping the given handler, which can be nil.
+func NewHandler(next slog.Handler, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	o := *opts
+	if o.Level == nil {
+		o.Level = slog.LevelError
+	}
+	if o.FlushTimeout <= 0 {
+		o.FlushTimeout = defaultFlushTimeout
+	}
+	tagKeys := make(map[string]bool, len(o.TagKeys))
+	for _, key := range o.TagKeys {
+		tagKeys[key] = true
+	}
+
+	return &Handler{next: next, opts: o, tagKeys: tagKeys}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level >= h.opts.Level.Level() {
+		return true
+	}
+	return h.next != nil && h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *Handler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= h.opts.Level.Level() {
+		h.hub(ctx).CaptureEvent(h.buildEvent(r))
+	}
+
+	if h.next != nil && h.next.Enabled(ctx, r.Level) {
+		return h.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (h *Handler) hub(ctx context.Context) *sentry.Hub {
+	if h.opts.Hub != nil {
+		return h.opts.Hub
+	}
+	if hub := sentry.GetHubFromContext(ctx); hub != nil {
+		return hub
+	}
+	return sentry.CurrentHub()
+}
+
+func (h *Handler) buildEvent(r slog.Record) *sentry.Event {
+	event := sentry.NewEvent()
+	event.Timestamp = r.Time
+	event.Level = toSentryLevel(r.Level)
+	event.Message = r.Message
+
+	prefix := ""
+	for _, goa := range h.goas {
+		if goa.Group != "" {
+			prefix += goa.Group + "."
+			continue
+		}
+		for _, a := range goa.Attrs {
+			h.addAttr(event, prefix, a, 0)
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		h.addAttr(event, prefix, a, 0)
+		return true
+	})
+
+	return event
+}
+
+// addAttr adds an attribute to the event. Values are resolved one level at a time, so errors are converted to
+// exceptions before a LogValuer (e.g. of slogutils.Errs) turns them into strings.
+func (h *Handler) addAttr(event *sentry.Event, prefix string, a slog.Attr, depth int) {
+	if a.Key == slogutils.ErrorKey && h.addException(event, a.Value) {
+		return
+	}
+	a.Value = a.Value.Resolve()
+	if a.Key == slogutils.ErrorKey && h.addException(event, a.Value) {
+		return
+	}
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	key := prefix + a.Key
+	if a.Value.Kind() == slog.KindGroup {
+		if depth >= slogutils.DefaultMaxDepth {
+			event.Extra[key] = slogutils.TruncatedValue
+			return
+		}
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			h.addAttr(event, groupPrefix, ga, depth+1)
+		}
+		return
+	}
+
+	if h.tagKeys[key] {
+		event.Tags[key] = a.Value.String()
+		return
+	}
+	event.Extra[key] = a.Value.Any()
+}
+
+// addException adds an exception to the event if the value is an error and reports whether it was added.
+func (h *Handler) addException(event *sentry.Event, v slog.Value) bool {
+	if v.Kind() != slog.KindAny && v.Kind() != slog.KindLogValuer {
+		return false
+	}
+	err, ok := v.Any().(error)
+	if !ok {
+		return false
+	}
+	event.Exception = append(event.Exception, sentry.Exception{
+		Type:       errorType(err),
+		Value:      err.Error(),
+		Stacktrace: extractStacktrace(err),
+	})
+	return true
+}
+
+func errorType(err error) string {
+	// Use the innermost error for grouping, wrappers like StackError are not meaningful.
+	// Joined errors are grouped by their first error.
+	for {
+		var unwrapped error
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			if errs := e.Unwrap(); len(errs) > 0 {
+				unwrapped = errs[0]
+			}
+		case interface{ Unwrap() error }:
+			unwrapped = e.Unwrap()
+		}
+		if unwrapped == nil {
+			return reflect.TypeOf(err).String()
+		}
+		err = unwrapped
+	}
+}
+
+func extractStacktrace(err error) *sentry.Stacktrace {
+	var stackErr *slogutils.StackError
+	if !errors.As(err, &stackErr) {
+		return sentry.ExtractStacktrace(err)
+	}
+
+	var frames []sentry.Frame
+	callersFrames := runtime.CallersFrames(stackErr.StackTrace())
+	for {
+		frame, more := callersFrames.Next()
+		frames = append(frames, sentry.NewFrame(frame))
+		if !more {
+			break
+		}
+	}
+	// Sentry expects the oldest frame first
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return &sentry.Stacktrace{Frames: frames}
+}
+
+func toSentryLevel(level slog.Level) sentry.Level {
+	switch {
+	case level >= slog.LevelError+4:
+		return sentry.LevelFatal
+	case level >= slog.LevelError:
+		return sentry.LevelError
+	case level >= slog.LevelWarn:
+		return sentry.LevelWarning
+	case level >= slog.LevelInfo:
+		return sentry.LevelInfo
+	default:
+		return sentry.LevelDebug
+	}
+}
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs) *Handler {
+	h2 := *h // Copy handler
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs})
+	if h.next != nil {
+		h2.next = h.next.WithAttrs(attrs)
+	}
+	return h2
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name})
+	if h.next != nil {
+		h2.next = h.next.WithGroup(name)
+	}
+	return h2
+}
+
+// Close flushes buffered events, waiting at most for the configured flush timeout.
+func (h *Handler) Close() error {
+	hub := h.opts.Hub
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	if !hub.Flush(h.opts.FlushTimeout) {
+		return errors.New("flushing sentry events timed out")
+	}
+	return nil
+}
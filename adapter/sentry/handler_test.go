@@ -0,0 +1,103 @@
+package sentry_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/networkteam/slogutils"
+	slogutilssentry "github.com/networkteam/slogutils/adapter/sentry"
+)
+
+func TestHandler(t *testing.T) {
+	var events []*sentry.Event
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		BeforeSend: func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+			events = append(events, event)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	logger := slog.New(slogutilssentry.NewHandler(nil, &slogutilssentry.Options{
+		Hub:     hub,
+		TagKeys: []string{"component"},
+	}))
+
+	logger.Info("not forwarded")
+	logger.With("component", "db").WithGroup("query").Error("Query failed", slogutils.ErrWithStack(errors.New("connection reset")), "sql", "SELECT 1")
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	event := events[0]
+	if event.Level != sentry.LevelError || event.Message != "Query failed" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if event.Tags["component"] != "db" {
+		t.Errorf("expected component tag, got: %v", event.Tags)
+	}
+	if event.Extra["query.sql"] != "SELECT 1" {
+		t.Errorf("expected query.sql extra, got: %v", event.Extra)
+	}
+	if len(event.Exception) != 1 || event.Exception[0].Value != "connection reset" {
+		t.Fatalf("expected exception from err attribute, got: %+v", event.Exception)
+	}
+	if event.Exception[0].Stacktrace == nil || len(event.Exception[0].Stacktrace.Frames) == 0 {
+		t.Fatal("expected stack trace from ErrWithStack")
+	}
+}
+
+func TestHandler_joinedErrors(t *testing.T) {
+	var events []*sentry.Event
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		BeforeSend: func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+			events = append(events, event)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	logger := slog.New(slogutilssentry.NewHandler(nil, &slogutilssentry.Options{Hub: hub}))
+
+	stackErr := slogutils.ErrWithStack(&queryError{}).Value.Any().(error)
+	logger.Error("Closing failed", slogutils.Errs(stackErr, errors.New("other")))
+	logger.Error("Lazy error", slog.Any(slogutils.ErrorKey, errorValuer{errors.New("lazy")}))
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	exc := events[0].Exception
+	if len(exc) != 1 || exc[0].Type != "*sentry_test.queryError" {
+		t.Fatalf("expected exception with type of first joined error, got: %+v", exc)
+	}
+	if exc[0].Stacktrace == nil || len(exc[0].Stacktrace.Frames) == 0 {
+		t.Fatal("expected stack trace from joined error")
+	}
+	if exc := events[1].Exception; len(exc) != 1 || exc[0].Value != "lazy" {
+		t.Fatalf("expected exception from resolved value, got: %+v", exc)
+	}
+}
+
+type queryError struct{}
+
+func (*queryError) Error() string {
+	return "query failed"
+}
+
+// errorValuer resolves to an error.
+type errorValuer struct {
+	err error
+}
+
+func (v errorValuer) LogValue() slog.Value {
+	return slog.AnyValue(v.err)
+}
@@ -0,0 +1,231 @@
+// Package audit provides a handler for an append-only audit trail with a tamper-evident hash chain.
+//
+// Records are written as JSON lines (as with slog.JSONHandler) and every entry is extended by the hash of the
+// previous entry and its own hash, which covers the entry including the previous hash. Changing, removing or
+// reordering entries breaks the chain and is detected by Verify. Since anyone with write access could recompute
+// the chain, an optional HMAC key can be used to authenticate entries.
+//
+//	h, err := audit.OpenFile("audit.log", &audit.Options{HMACKey: key})
+//	// ...
+//	defer h.Close()
+//	auditLog := slog.New(h)
+//	auditLog.Info("User deleted", "admin", "alice", "user", "bob")
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Keys of the fields added to every entry. They are always the last fields of an entry.
+const (
+	PrevHashKey = "prev_hash"
+	HashKey     = "hash"
+	HMACKey     = "hmac"
+)
+
+// GenesisHash is the previous hash of the first entry of a chain.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// Options are options for a Handler.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Level reports the minimum record level that will be logged.
+	// If Level is nil, the handler assumes slog.LevelInfo.
+	Level slog.Leveler
+
+	// ReplaceAttr is called to rewrite each non-group attribute before it is logged, see slog.HandlerOptions.
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+
+	// HMACKey optionally authenticates entries with an HMAC-SHA256 of the entry, which is added as HMACKey field.
+	HMACKey []byte
+
+	// PrevHash is the hash of the last entry to continue an existing chain, defaults to GenesisHash.
+	PrevHash string
+
+	// OnError is called with the record and the error if writing an entry fails, e.g. to alert on a full disk.
+	// The chain is not advanced for failed entries. The error is also returned by Handle, but discarded by
+	// slog.Logger.
+	OnError func(r slog.Record, err error)
+}
+
+// Handler writes records as JSON lines with a hash chain.
+type Handler struct {
+	chain *chain
+	opts  Options
+	goas  []slogutils.GroupOrAttrs
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler creates a handler appending entries to w.
+// Use OpenFile to continue the chain of an existing file.
+func NewHandler(w io.Writer, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	o := *opts
+	if o.Level == nil {
+		o.Level = slog.LevelInfo
+	}
+	if o.PrevHash == "" {
+		o.PrevHash = GenesisHash
+	}
+
+	return &Handler{
+		chain: &chain{w: w, prevHash: o.PrevHash, hmacKey: o.HMACKey},
+		opts:  o,
+	}
+}
+
+// OpenFile opens or creates the audit log file at path for appending.
+// An existing file is verified with Verify (using opts.HMACKey) and the chain is continued from its last entry,
+// so an error is returned if the file was tampered with. Close must be called to close the file.
+func OpenFile(path string, opts *Options) (*Handler, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	lastHash, err := Verify(f, &VerifyOptions{HMACKey: opts.HMACKey, PrevHash: opts.PrevHash})
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	o := *opts
+	o.PrevHash = lastHash
+	h := NewHandler(f, &o)
+	h.chain.closer = f
+	return h, nil
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	r, _ = slogutils.ExtractControls(r)
+
+	buf := new(bytes.Buffer)
+	jsonHandler := slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		Level:       slog.Level(-1 << 31),
+		ReplaceAttr: h.opts.ReplaceAttr,
+	})
+	if err := slogutils.ApplyGroupsAndAttrs(jsonHandler, h.goas).Handle(ctx, r); err != nil {
+		return err
+	}
+
+	err := h.chain.append(bytes.TrimSuffix(buf.Bytes(), []byte("}\n")))
+	if err != nil && h.opts.OnError != nil {
+		h.opts.OnError(r, err)
+	}
+	return err
+}
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs) *Handler {
+	h2 := *h // Copy handler
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs, _ = slogutils.StripControlAttrs(attrs)
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs})
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name})
+}
+
+// LastHash returns the hash of the last written entry.
+func (h *Handler) LastHash() string {
+	h.chain.mu.Lock()
+	defer h.chain.mu.Unlock()
+	return h.chain.prevHash
+}
+
+// Close closes the file if the handler was created by OpenFile.
+func (h *Handler) Close() error {
+	if h.chain.closer == nil {
+		return nil
+	}
+	return h.chain.closer.Close()
+}
+
+// chain serializes writes of entries and is shared by all handlers derived with WithAttrs and WithGroup.
+type chain struct {
+	mu       sync.Mutex
+	w        io.Writer
+	closer   io.Closer
+	prevHash string
+	hmacKey  []byte
+}
+
+// append completes the unterminated JSON object with the chain fields and writes it as a line.
+func (c *chain) append(obj []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	payload := appendField(obj, PrevHashKey, c.prevHash)
+	hash := hashPayload(payload)
+
+	line := appendField(payload, HashKey, hash)
+	if c.hmacKey != nil {
+		line = appendField(line, HMACKey, macPayload(c.hmacKey, payload))
+	}
+	line = append(line, '}', '\n')
+
+	if _, err := c.w.Write(line); err != nil {
+		return err
+	}
+	c.prevHash = hash
+	return nil
+}
+
+// appendField appends a string field to an unterminated JSON object.
+func appendField(buf []byte, key, value string) []byte {
+	if len(buf) > 0 && buf[len(buf)-1] != '{' {
+		buf = append(buf, ',')
+	}
+	buf = append(buf, '"')
+	buf = append(buf, key...)
+	buf = append(buf, `":"`...)
+	buf = append(buf, value...)
+	return append(buf, '"')
+}
+
+func hashPayload(payload []byte) string {
+	return sumHex(sha256.New(), payload)
+}
+
+func macPayload(key, payload []byte) string {
+	return sumHex(hmac.New(sha256.New, key), payload)
+}
+
+func sumHex(h hash.Hash, data []byte) string {
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
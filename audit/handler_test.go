@@ -0,0 +1,153 @@
+package audit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils/audit"
+)
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(audit.NewHandler(&buf, nil)).With("admin", "alice")
+
+	logger.Info("User deleted", "user", "bob")
+	logger.WithGroup("req").Warn("Role changed", "role", "admin", "hash", "user-provided")
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), buf.String())
+	}
+
+	var entries []map[string]any
+	for _, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if entries[0]["admin"] != "alice" || entries[0]["user"] != "bob" || entries[0]["msg"] != "User deleted" {
+		t.Errorf("unexpected first entry: %v", entries[0])
+	}
+	if entries[0][audit.PrevHashKey] != audit.GenesisHash {
+		t.Errorf("expected first entry to start chain, got %v", entries[0][audit.PrevHashKey])
+	}
+	if entries[1][audit.PrevHashKey] != entries[0][audit.HashKey] {
+		t.Errorf("expected second entry to reference hash of first entry")
+	}
+	if req := entries[1]["req"].(map[string]any); req["hash"] != "user-provided" {
+		t.Errorf("unexpected group in second entry: %v", entries[1])
+	}
+
+	lastHash, err := audit.Verify(strings.NewReader(buf.String()), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lastHash != entries[1][audit.HashKey] {
+		t.Errorf("expected last hash %v, got %s", entries[1][audit.HashKey], lastHash)
+	}
+}
+
+func TestVerify_Tampered(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(audit.NewHandler(&buf, nil))
+	logger.Info("First", "n", 1)
+	logger.Info("Second", "n", 2)
+	logger.Info("Third", "n", 3)
+	lines := strings.SplitAfter(buf.String(), "\n")
+
+	tests := []struct {
+		name     string
+		log      string
+		wantLine int
+		wantErr  error
+	}{
+		{
+			name:     "modified entry",
+			log:      lines[0] + strings.Replace(lines[1], `"n":2`, `"n":5`, 1) + lines[2],
+			wantLine: 2,
+			wantErr:  audit.ErrHashMismatch,
+		},
+		{
+			name:     "removed entry",
+			log:      lines[0] + lines[2],
+			wantLine: 2,
+			wantErr:  audit.ErrChainBroken,
+		},
+		{
+			name:     "reordered entries",
+			log:      lines[1] + lines[0] + lines[2],
+			wantLine: 1,
+			wantErr:  audit.ErrChainBroken,
+		},
+		{
+			name:     "invalid entry",
+			log:      lines[0] + "garbage\n",
+			wantLine: 2,
+			wantErr:  audit.ErrInvalidEntry,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := audit.Verify(strings.NewReader(tt.log), nil)
+			var verifyErr *audit.VerifyError
+			if !errors.As(err, &verifyErr) {
+				t.Fatalf("expected verify error, got %v", err)
+			}
+			if verifyErr.Line != tt.wantLine || !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v in line %d, got %v", tt.wantErr, tt.wantLine, err)
+			}
+		})
+	}
+}
+
+func TestVerify_HMAC(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(audit.NewHandler(&buf, &audit.Options{HMACKey: []byte("secret")}))
+	logger.Info("User deleted", "user", "bob")
+
+	if _, err := audit.Verify(strings.NewReader(buf.String()), &audit.VerifyOptions{HMACKey: []byte("secret")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := audit.Verify(strings.NewReader(buf.String()), &audit.VerifyOptions{HMACKey: []byte("other")}); !errors.Is(err, audit.ErrHMACMismatch) {
+		t.Fatalf("expected hmac mismatch, got %v", err)
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	for i := 0; i < 2; i++ {
+		h, err := audit.OpenFile(path, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		slog.New(h).Info("Entry", "run", i)
+		if err := h.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := audit.Verify(bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("expected continued chain, got %v", err)
+	}
+
+	if err := os.WriteFile(path, bytes.Replace(data, []byte(`"run":0`), []byte(`"run":9`), 1), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := audit.OpenFile(path, nil); !errors.Is(err, audit.ErrHashMismatch) {
+		t.Fatalf("expected tampered file to be rejected, got %v", err)
+	}
+}
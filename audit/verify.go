@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// VerifyOptions are options for Verify.
+// A zero VerifyOptions consists entirely of default values.
+type VerifyOptions struct {
+	// HMACKey is the key entries were authenticated with. If it is set, every entry must have a valid HMAC.
+	HMACKey []byte
+
+	// PrevHash is the previous hash of the first entry, defaults to GenesisHash.
+	PrevHash string
+}
+
+// VerifyError is returned by Verify if an entry breaks the chain.
+type VerifyError struct {
+	// Line is the 1-based line number of the entry.
+	Line int
+	Err  error
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("audit: line %d: %v", e.Line, e.Err)
+}
+
+func (e *VerifyError) Unwrap() error {
+	return e.Err
+}
+
+// Errors wrapped by a VerifyError.
+var (
+	ErrInvalidEntry = errors.New("invalid entry")
+	ErrChainBroken  = errors.New("previous hash does not match")
+	ErrHashMismatch = errors.New("hash does not match")
+	ErrHMACMismatch = errors.New("hmac does not match")
+)
+
+var hashFieldPrefix = []byte(`,"` + HashKey + `":"`)
+
+// Verify reads entries written by a Handler from r and verifies the hash chain (and HMACs if a key is given).
+// It returns the hash of the last entry (or the previous hash of the first entry if r is empty) to continue the
+// chain. An error of type *VerifyError is returned for the first entry that breaks the chain.
+func Verify(r io.Reader, opts *VerifyOptions) (lastHash string, err error) {
+	if opts == nil {
+		opts = &VerifyOptions{}
+	}
+	prevHash := opts.PrevHash
+	if prevHash == "" {
+		prevHash = GenesisHash
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		hash, err := verifyEntry(scanner.Bytes(), prevHash, opts.HMACKey)
+		if err != nil {
+			return "", &VerifyError{Line: line, Err: err}
+		}
+		prevHash = hash
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return prevHash, nil
+}
+
+// verifyEntry verifies a single entry and returns its hash.
+func verifyEntry(entry []byte, prevHash string, hmacKey []byte) (string, error) {
+	// Duplicate keys are allowed in entries, the last value (which is a chain field) wins
+	var fields struct {
+		PrevHash string `json:"prev_hash"`
+		Hash     string `json:"hash"`
+		HMAC     string `json:"hmac"`
+	}
+	if err := json.Unmarshal(entry, &fields); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidEntry, err)
+	}
+
+	idx := bytes.LastIndex(entry, hashFieldPrefix)
+	if idx == -1 {
+		return "", fmt.Errorf("%w: missing %s", ErrInvalidEntry, HashKey)
+	}
+	payload := entry[:idx]
+
+	if fields.PrevHash != prevHash || !bytes.HasSuffix(payload, []byte(`"`+PrevHashKey+`":"`+prevHash+`"`)) {
+		return "", ErrChainBroken
+	}
+	if hashPayload(payload) != fields.Hash {
+		return "", ErrHashMismatch
+	}
+	if hmacKey != nil && !hmac.Equal([]byte(macPayload(hmacKey, payload)), []byte(fields.HMAC)) {
+		return "", ErrHMACMismatch
+	}
+
+	return fields.Hash, nil
+}
@@ -0,0 +1,121 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+)
+
+// DefaultTruncatedKey is the default key of the attribute with the number of attributes dropped by a BudgetHandler.
+const DefaultTruncatedKey = "attrs_truncated"
+
+// BudgetOptions limit the attributes of a record.
+// A zero BudgetOptions consists entirely of default values (no limits).
+type BudgetOptions struct {
+	// MaxAttrs is the maximum number of (top-level) attributes of a record. If MaxAttrs is 0, it is not limited.
+	MaxAttrs int
+
+	// MaxSize is the maximum estimated size of the attributes of a record in bytes (see EstimateAttrSize).
+	// Attributes exceeding it are dropped, but smaller attributes after them are kept.
+	// If MaxSize is 0, the size is not limited.
+	MaxSize int
+
+	// TruncatedKey is the key of the attribute with the number of dropped attributes, defaults to
+	// DefaultTruncatedKey.
+	TruncatedKey string
+}
+
+func (o *BudgetOptions) truncatedKey() string {
+	if o.TruncatedKey == "" {
+		return DefaultTruncatedKey
+	}
+	return o.TruncatedKey
+}
+
+// budgetUsage is the number and estimated size of attributes already counted against a budget.
+type budgetUsage struct {
+	attrs int
+	size  int
+}
+
+// apply returns the attributes within the budget after the given usage, the number of dropped attributes and the
+// usage including the kept attributes.
+func (o *BudgetOptions) apply(attrs []slog.Attr, used budgetUsage) ([]slog.Attr, int, budgetUsage) {
+	kept := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if o.MaxAttrs > 0 && used.attrs >= o.MaxAttrs {
+			break
+		}
+		if o.MaxSize > 0 {
+			attrSize := EstimateAttrSize(a)
+			if used.size+attrSize > o.MaxSize {
+				continue
+			}
+			used.size += attrSize
+		}
+		used.attrs++
+		kept = append(kept, a)
+	}
+	return kept, len(attrs) - len(kept), used
+}
+
+// BudgetHandler enforces a budget for the number and size of attributes of records, so accidentally huge records
+// do not flood terminals or remote sinks. Dropped attributes are summarized by an attribute with their number
+// (e.g. attrs_truncated=3). Attributes added with WithAttrs count against the budget of every record, so a record
+// only gets the budget left by the attributes of the handler.
+type BudgetHandler struct {
+	next slog.Handler
+	opts *BudgetOptions
+	// used is the budget used by attributes of the handler
+	used budgetUsage
+}
+
+var (
+	_ slog.Handler = (*BudgetHandler)(nil)
+	_ AttrsEnabler = (*BudgetHandler)(nil)
+	_ Wrapper      = (*BudgetHandler)(nil)
+)
+
+// NewBudgetHandler creates a new BudgetHandler wrapping the given handler.
+func NewBudgetHandler(next slog.Handler, opts *BudgetOptions) *BudgetHandler {
+	if opts == nil {
+		opts = &BudgetOptions{}
+	}
+	return &BudgetHandler{next: next, opts: opts}
+}
+
+func (h *BudgetHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *BudgetHandler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *BudgetHandler) EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+	return EnabledForAttrs(ctx, h.next, level, attrs)
+}
+
+func (h *BudgetHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs, dropped, _ := h.opts.apply(RecordAttrs(r), h.used)
+	if dropped == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	r2 := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r2.AddAttrs(attrs...)
+	r2.AddAttrs(slog.Int(h.opts.truncatedKey(), dropped))
+	return h.next.Handle(ctx, r2)
+}
+
+func (h *BudgetHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs, dropped, used := h.opts.apply(attrs, h.used)
+	if dropped > 0 {
+		attrs = append(attrs, slog.Int(h.opts.truncatedKey(), dropped))
+	}
+	return &BudgetHandler{next: h.next.WithAttrs(attrs), opts: h.opts, used: used}
+}
+
+func (h *BudgetHandler) WithGroup(name string) slog.Handler {
+	return &BudgetHandler{next: h.next.WithGroup(name), opts: h.opts, used: h.used}
+}
@@ -0,0 +1,74 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestBudgetHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *slogutils.BudgetOptions
+		f    func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "no limits",
+			f: func(l *slog.Logger) {
+				l.Info("test", "a", 1, "b", 2)
+			},
+			want: `level=INFO msg=test a=1 b=2`,
+		},
+		{
+			name: "max attrs",
+			opts: &slogutils.BudgetOptions{MaxAttrs: 2},
+			f: func(l *slog.Logger) {
+				l.Info("test", "a", 1, "b", 2, "c", 3, "d", 4)
+			},
+			want: `level=INFO msg=test a=1 b=2 attrs_truncated=2`,
+		},
+		{
+			name: "max size drops large attributes",
+			opts: &slogutils.BudgetOptions{MaxSize: 100, TruncatedKey: "dropped"},
+			f: func(l *slog.Logger) {
+				l.Info("test", "a", 1, "payload", strings.Repeat("x", 1000), "b", 2)
+			},
+			want: `level=INFO msg=test a=1 b=2 dropped=1`,
+		},
+		{
+			name: "attributes of the handler count against the budget of records",
+			opts: &slogutils.BudgetOptions{MaxAttrs: 3},
+			f: func(l *slog.Logger) {
+				l.With("a", 1, "b", 2).WithGroup("g").Info("test", "c", 3, "d", 4)
+			},
+			want: `level=INFO msg=test a=1 b=2 g.c=3 g.attrs_truncated=1`,
+		},
+		{
+			name: "attributes of the handler exceeding the budget",
+			opts: &slogutils.BudgetOptions{MaxAttrs: 1},
+			f: func(l *slog.Logger) {
+				l.With("a", 1, "b", 2).Info("test", "c", 3)
+			},
+			want: `level=INFO msg=test a=1 attrs_truncated=1 attrs_truncated=1`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := slog.New(slogutils.NewBudgetHandler(slog.NewTextHandler(buf, &slog.HandlerOptions{
+				ReplaceAttr: drop(slog.TimeKey),
+			}), tt.opts))
+
+			tt.f(logger)
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
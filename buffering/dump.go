@@ -0,0 +1,77 @@
+package buffering
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Dump writes all buffered records as JSON lines to w without removing them from the buffer.
+// Records are written regardless of their level, so the detailed history (e.g. trace and debug records) is kept.
+// Nothing is written if a handler is already attached, since records are not buffered anymore.
+func (h *Handler) Dump(w io.Writer) error {
+	s := h.state
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jsonHandler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		AddSource: true,
+		Level:     slog.Level(-1 << 31),
+	})
+	var errs []error
+	for _, br := range s.records {
+		if err := slogutils.ApplyGroupsAndAttrs(jsonHandler, br.goas).Handle(br.ctx, br.record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DumpTo writes all buffered records as JSON lines to a new file at path, see Dump.
+func (h *Handler) DumpTo(path string) (err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+
+	return h.Dump(f)
+}
+
+// DumpOnPanic dumps all buffered records to a temporary file if the calling goroutine panics and prints the path of
+// the file to stderr, so the history leading up to a crash can be attached to bug reports. The panic is continued
+// after the dump. It must be called directly by defer:
+//
+//	buf := buffering.NewHandler(nil)
+//	defer buf.DumpOnPanic()
+func (h *Handler) DumpOnPanic() {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	if path, err := h.dumpTemp(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to dump buffered log records: %v\n", err)
+	} else {
+		fmt.Fprintf(os.Stderr, "buffered log records dumped to %s\n", path)
+	}
+	panic(v)
+}
+
+func (h *Handler) dumpTemp() (path string, err error) {
+	f, err := os.CreateTemp("", "slog-dump-*.jsonl")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+
+	return f.Name(), h.Dump(f)
+}
@@ -0,0 +1,83 @@
+package buffering_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/buffering"
+)
+
+func TestHandler_DumpTo(t *testing.T) {
+	h := buffering.NewHandler(nil)
+	logger := slog.New(h)
+
+	logger.Log(context.Background(), slogutils.LevelTrace, "Reading file", "path", "config.yaml")
+	logger.WithGroup("db").Debug("Connecting", "host", "localhost")
+
+	path := filepath.Join(t.TempDir(), "dump.jsonl")
+	if err := h.DumpTo(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 dumped records, got %d: %s", len(lines), data)
+	}
+
+	var entry struct {
+		Msg string `json:"msg"`
+		DB  struct {
+			Host string `json:"host"`
+		} `json:"db"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", lines[1], err)
+	}
+	if entry.Msg != "Connecting" || entry.DB.Host != "localhost" {
+		t.Errorf("unexpected record: %s", lines[1])
+	}
+
+	// Records are kept in the buffer after dumping
+	var buf strings.Builder
+	_ = h.Attach(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: dropTime}))
+	if want, got := "level=DEBUG msg=Connecting db.host=localhost\n", buf.String(); want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+func TestHandler_DumpOnPanic(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+
+	h := buffering.NewHandler(nil)
+	slog.New(h).Debug("Before crash")
+
+	func() {
+		defer func() {
+			if v := recover(); v != "crash" {
+				t.Errorf("expected panic to be continued, got %v", v)
+			}
+		}()
+		defer h.DumpOnPanic()
+		panic("crash")
+	}()
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "slog-dump-*.jsonl"))
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 dump file, got %v", matches)
+	}
+	data, _ := os.ReadFile(matches[0])
+	if !strings.Contains(string(data), `"msg":"Before crash"`) {
+		t.Errorf("expected record in dump, got %s", data)
+	}
+}
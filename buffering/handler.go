@@ -0,0 +1,296 @@
+// Package buffering provides a handler that buffers records until the final handler is attached.
+//
+// A process can start logging before the sink configuration is known (e.g. before flags or config files are parsed),
+// then attach the real handler, which receives all buffered records first:
+//
+//	buf := buffering.NewHandler(nil)
+//	slog.SetDefault(slog.New(buf))
+//
+//	cfg, err := loadConfig() // might log with slog
+//	// ...
+//	err = buf.Attach(newHandler(cfg))
+package buffering
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/networkteam/slogutils"
+)
+
+const defaultMaxRecords = 10000
+
+// ErrAttached is returned by Handler.Attach if a handler was already attached.
+var ErrAttached = errors.New("handler already attached")
+
+// Options are options for a Handler.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Level reports the minimum record level that is buffered.
+	// If Level is nil, all records down to slogutils.LevelTrace are buffered, since the level of the
+	// attached handler is not known yet. The attached handler still filters buffered records by its level.
+	Level slog.Leveler
+
+	// MaxRecords is the maximum number of buffered records, defaults to 10000.
+	// The oldest records are dropped if the limit is reached.
+	MaxRecords int
+
+	// MaxSize is the maximum estimated size of buffered records in bytes (see slogutils.EstimateSize).
+	// The oldest records are dropped if the limit is reached. If MaxSize is zero, the size is not limited.
+	MaxSize int
+}
+
+// Handler buffers records until a handler is attached with Attach, after that records are passed to the attached
+// handler. All handlers derived with WithAttrs and WithGroup share the buffer and the attached handler.
+type Handler struct {
+	state *state
+	goas  []slogutils.GroupOrAttrs
+
+	// mu guards next, the attached handler with groups and attributes of this handler applied
+	mu   sync.Mutex
+	next slog.Handler
+}
+
+var (
+	_ slog.Handler      = (*Handler)(nil)
+	_ slogutils.Wrapper = (*Handler)(nil)
+)
+
+type state struct {
+	level      slog.Leveler
+	maxRecords int
+	maxSize    int
+
+	mu       sync.RWMutex
+	attached slog.Handler
+	// attaching is set while buffered records are emitted, records are still buffered to keep their order
+	attaching bool
+	records   []bufferedRecord
+	size      int
+	dropped   int
+}
+
+type bufferedRecord struct {
+	ctx    context.Context
+	record slog.Record
+	goas   []slogutils.GroupOrAttrs
+	size   int
+}
+
+// NewHandler creates a new buffering handler. Options can be nil.
+func NewHandler(opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+	var level slog.Leveler = slogutils.LevelTrace
+	if opts.Level != nil {
+		level = opts.Level
+	}
+	maxRecords := opts.MaxRecords
+	if maxRecords <= 0 {
+		maxRecords = defaultMaxRecords
+	}
+
+	return &Handler{
+		state: &state{
+			level:      level,
+			maxRecords: maxRecords,
+			maxSize:    opts.MaxSize,
+		},
+	}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if next := h.attachedHandler(); next != nil {
+		return next.Enabled(ctx, level)
+	}
+	return level >= h.state.level.Level()
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if next := h.attachedHandler(); next != nil {
+		return next.Handle(ctx, r)
+	}
+
+	s := h.state
+	s.mu.Lock()
+	if s.attached != nil {
+		// Attached concurrently
+		s.mu.Unlock()
+		return h.attachedHandler().Handle(ctx, r)
+	}
+	defer s.mu.Unlock()
+
+	br := bufferedRecord{
+		// The context is kept for values (e.g. trace IDs), but must not cancel the replay
+		ctx:    context.WithoutCancel(ctx),
+		record: r.Clone(),
+		goas:   h.goas,
+		size:   slogutils.EstimateSize(r, nil),
+	}
+	s.records = append(s.records, br)
+	s.size += br.size
+	s.trim()
+
+	return nil
+}
+
+// trim drops the oldest records exceeding the limits, must be called with mu held.
+func (s *state) trim() {
+	n := 0
+	for len(s.records)-n > s.maxRecords || (s.maxSize > 0 && s.size > s.maxSize && len(s.records)-n > 0) {
+		s.size -= s.records[n].size
+		n++
+	}
+	if n == 0 {
+		return
+	}
+	clear(s.records[:n])
+	s.records = s.records[n:]
+	s.dropped += n
+}
+
+// attachedHandler returns the attached handler with groups and attributes of h applied or nil if no handler
+// is attached yet.
+func (h *Handler) attachedHandler() slog.Handler {
+	h.state.mu.RLock()
+	attached := h.state.attached
+	h.state.mu.RUnlock()
+	if attached == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.next == nil {
+		h.next = slogutils.ApplyGroupsAndAttrs(attached, h.goas)
+	}
+	return h.next
+}
+
+// EmitOptions control how buffered records are emitted to the attached handler.
+// A zero EmitOptions consists entirely of default values.
+type EmitOptions struct {
+	// Level optionally filters buffered records by their original level before they are remapped.
+	// If Level is nil, all buffered records are emitted (if enabled by the attached handler).
+	Level slog.Leveler
+
+	// RemapLevel optionally changes the level of buffered records (e.g. from trace to debug).
+	RemapLevel func(level slog.Level) slog.Level
+
+	// Attrs are added to every buffered record on the top level (e.g. slog.Bool("replayed", true)).
+	Attrs []slog.Attr
+}
+
+// Attach attaches the handler and emits all buffered records to it in order. Records that are logged concurrently
+// (or by the handler itself, e.g. through slog.Default) are passed to the handler after the buffered records.
+// Records not enabled by the handler are skipped.
+// A handler can only be attached once, ErrAttached is returned for further calls.
+// The errors of handling buffered records are joined.
+func (h *Handler) Attach(next slog.Handler) error {
+	return h.AttachWith(next, nil)
+}
+
+// AttachWith attaches the handler like Attach, buffered records are filtered, remapped and extended according to
+// opts before they are emitted. Options can be nil.
+func (h *Handler) AttachWith(next slog.Handler, opts *EmitOptions) error {
+	if opts == nil {
+		opts = &EmitOptions{}
+	}
+
+	s := h.state
+	s.mu.Lock()
+	if s.attached != nil || s.attaching {
+		s.mu.Unlock()
+		return ErrAttached
+	}
+	s.attaching = true
+
+	emitTo := next
+	if len(opts.Attrs) > 0 {
+		emitTo = next.WithAttrs(opts.Attrs)
+	}
+
+	// Records are emitted without holding the lock, records logged meanwhile are buffered and emitted in the next
+	// round as they were logged, so the options only apply to the records buffered before
+	var errs []error
+	for round := 0; ; round++ {
+		records := s.records
+		s.records = nil
+		s.size = 0
+		if len(records) == 0 {
+			s.attached = next
+			s.attaching = false
+			s.mu.Unlock()
+			break
+		}
+		s.mu.Unlock()
+
+		if round == 0 {
+			errs = emit(errs, emitTo, records, opts)
+		} else {
+			errs = emit(errs, next, records, &EmitOptions{})
+		}
+
+		s.mu.Lock()
+	}
+
+	return errors.Join(errs...)
+}
+
+// emit emits buffered records to the handler and appends errors to errs.
+func emit(errs []error, next slog.Handler, records []bufferedRecord, opts *EmitOptions) []error {
+	for _, br := range records {
+		r := br.record
+		if opts.Level != nil && r.Level < opts.Level.Level() {
+			continue
+		}
+		if opts.RemapLevel != nil {
+			r.Level = opts.RemapLevel(r.Level)
+		}
+		if !next.Enabled(br.ctx, r.Level) {
+			continue
+		}
+		if err := slogutils.ApplyGroupsAndAttrs(next, br.goas).Handle(br.ctx, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Unwrap returns the attached handler or nil if no handler is attached yet.
+func (h *Handler) Unwrap() slog.Handler {
+	return h.attachedHandler()
+}
+
+// Dropped returns the number of records that were dropped because the buffer limits were reached.
+func (h *Handler) Dropped() int {
+	h.state.mu.RLock()
+	defer h.state.mu.RUnlock()
+
+	return h.state.dropped
+}
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs) *Handler {
+	h2 := &Handler{state: h.state}
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs})
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name})
+}
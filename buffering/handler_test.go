@@ -0,0 +1,166 @@
+package buffering_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/buffering"
+)
+
+func TestHandler_Attach(t *testing.T) {
+	h := buffering.NewHandler(nil)
+	logger := slog.New(h)
+
+	logger.Debug("Parsing config", "path", "config.yaml")
+	logger.With("component", "db").WithGroup("conn").Info("Connecting", "host", "localhost")
+
+	buf := new(bytes.Buffer)
+	err := h.Attach(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: dropTime,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logger.Info("Started")
+
+	want := strings.Join([]string{
+		`level=INFO msg=Connecting component=db conn.host=localhost`,
+		`level=INFO msg=Started`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+
+	if !errors.Is(h.Attach(slog.Default().Handler()), buffering.ErrAttached) {
+		t.Fatal("expected ErrAttached")
+	}
+}
+
+func TestHandler_MaxRecords(t *testing.T) {
+	h := buffering.NewHandler(&buffering.Options{MaxRecords: 2})
+	logger := slog.New(h)
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Info("three")
+
+	if h.Dropped() != 1 {
+		t.Fatalf("expected 1 dropped record, got %d", h.Dropped())
+	}
+
+	buf := new(bytes.Buffer)
+	_ = h.Attach(slog.NewTextHandler(buf, &slog.HandlerOptions{ReplaceAttr: dropTime}))
+
+	want := "level=INFO msg=two\nlevel=INFO msg=three\n"
+	if got := buf.String(); want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+func TestHandler_AttachWith(t *testing.T) {
+	h := buffering.NewHandler(nil)
+	logger := slog.New(h)
+
+	logger.Log(context.Background(), slogutils.LevelTrace, "Reading file", "path", "config.yaml")
+	logger.WithGroup("db").Log(context.Background(), slogutils.LevelTrace, "Connecting", "host", "localhost")
+
+	buf := new(bytes.Buffer)
+	err := h.AttachWith(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level:       slog.LevelDebug,
+		ReplaceAttr: dropTime,
+	}), &buffering.EmitOptions{
+		RemapLevel: func(level slog.Level) slog.Level {
+			return max(level, slog.LevelDebug)
+		},
+		Attrs: []slog.Attr{slog.Bool("replayed", true)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logger.Debug("Started")
+
+	want := strings.Join([]string{
+		`level=DEBUG msg="Reading file" replayed=true path=config.yaml`,
+		`level=DEBUG msg=Connecting replayed=true db.host=localhost`,
+		`level=DEBUG msg=Started`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+func TestHandler_AttachWith_Level(t *testing.T) {
+	h := buffering.NewHandler(nil)
+	logger := slog.New(h)
+
+	logger.Log(context.Background(), slogutils.LevelTrace, "Trace")
+	logger.Info("Info")
+
+	buf := new(bytes.Buffer)
+	_ = h.AttachWith(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level:       slogutils.LevelTrace,
+		ReplaceAttr: dropTime,
+	}), &buffering.EmitOptions{Level: slog.LevelInfo})
+
+	if want, got := "level=INFO msg=Info\n", buf.String(); want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+func TestHandler_Attach_Reentrant(t *testing.T) {
+	h := buffering.NewHandler(nil)
+	logger := slog.New(h)
+
+	logger.Info("one")
+	logger.Info("two")
+
+	buf := new(bytes.Buffer)
+	next := &reentrantHandler{
+		Handler: slog.NewTextHandler(buf, &slog.HandlerOptions{ReplaceAttr: dropTime}),
+		logger:  logger,
+	}
+	if err := h.Attach(next); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		`level=INFO msg=one`,
+		`level=INFO msg=two`,
+		`level=WARN msg="handled one"`,
+		`level=WARN msg="handled two"`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+// reentrantHandler logs a warning through the buffering handler for every info record, like a handler logging
+// its own errors through slog.Default.
+type reentrantHandler struct {
+	slog.Handler
+	logger *slog.Logger
+}
+
+func (h *reentrantHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level == slog.LevelInfo {
+		h.logger.Warn("handled " + r.Message)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func dropTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return a
+}
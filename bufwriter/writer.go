@@ -0,0 +1,179 @@
+// Package bufwriter provides a buffered writer that is flushed periodically, so handlers writing to files
+// do not need one syscall per record.
+package bufwriter
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSize          = 64 * 1024
+	defaultFlushInterval = time.Second
+)
+
+// Options are options for a Writer.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Size is the size of the buffer in bytes, defaults to 64 KiB.
+	// The buffer is flushed before a write that would exceed the size.
+	Size int
+
+	// FlushInterval is the maximum time data stays in the buffer, defaults to one second.
+	FlushInterval time.Duration
+}
+
+// Writer buffers writes to an underlying writer. The buffer is flushed on an interval, if its size is reached and
+// on Flush, Sync and Close. Each write is passed to the underlying writer as a whole, so records are never split.
+// Errors of flushes in the background are returned once by the next call of Write, Flush, Sync or Close.
+// Write still accepts p in this case, so no more data is lost once the underlying writer recovers.
+type Writer struct {
+	w    io.Writer
+	size int
+
+	mu     sync.Mutex
+	buf    []byte
+	err    error
+	closed bool
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+var _ io.WriteCloser = (*Writer)(nil)
+
+// New creates a buffered writer writing to w. Options can be nil.
+func New(w io.Writer, opts *Options) *Writer {
+	if opts == nil {
+		opts = &Options{}
+	}
+	size := opts.Size
+	if size <= 0 {
+		size = defaultSize
+	}
+	interval := opts.FlushInterval
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+
+	bw := &Writer{
+		w:      w,
+		size:   size,
+		buf:    make([]byte, 0, size),
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	go bw.run(interval)
+	return bw
+}
+
+func (bw *Writer) run(interval time.Duration) {
+	defer close(bw.doneCh)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bw.stopCh:
+			return
+		case <-ticker.C:
+			bw.mu.Lock()
+			_ = bw.flush()
+			bw.mu.Unlock()
+		}
+	}
+}
+
+// Write appends p to the buffer. The buffer is flushed before if p does not fit, p is written directly if it is
+// larger than the buffer. If a flush failed, the error is returned with n = len(p), since p was still accepted.
+func (bw *Writer) Write(p []byte) (int, error) {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+
+	if bw.closed {
+		return 0, os.ErrClosed
+	}
+
+	if len(bw.buf)+len(p) > bw.size {
+		_ = bw.flush()
+	}
+	err := bw.takeErr()
+	if len(p) > bw.size {
+		n, wErr := bw.w.Write(p)
+		if wErr != nil {
+			return n, wErr
+		}
+		return n, err
+	}
+	bw.buf = append(bw.buf, p...)
+	return len(p), err
+}
+
+// flush writes the buffer to the underlying writer, must be called with mu held.
+// The error is kept until it is returned by takeErr.
+func (bw *Writer) flush() error {
+	if len(bw.buf) == 0 {
+		return nil
+	}
+	_, err := bw.w.Write(bw.buf)
+	bw.buf = bw.buf[:0]
+	if err != nil && bw.err == nil {
+		bw.err = err
+	}
+	return err
+}
+
+// takeErr returns and resets the error of the last failed flush, must be called with mu held.
+func (bw *Writer) takeErr() error {
+	err := bw.err
+	bw.err = nil
+	return err
+}
+
+// Flush writes buffered data to the underlying writer.
+func (bw *Writer) Flush() error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+
+	_ = bw.flush()
+	return bw.takeErr()
+}
+
+// Sync flushes the buffer and commits the data to stable storage if the underlying writer has a Sync method
+// (e.g. *os.File).
+func (bw *Writer) Sync() error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+
+	_ = bw.flush()
+	if err := bw.takeErr(); err != nil {
+		return err
+	}
+	if s, ok := bw.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close stops the periodic flush and flushes the buffer. The underlying writer is not closed.
+func (bw *Writer) Close() error {
+	bw.mu.Lock()
+	if bw.closed {
+		bw.mu.Unlock()
+		return nil
+	}
+	bw.closed = true
+	bw.mu.Unlock()
+
+	close(bw.stopCh)
+	<-bw.doneCh
+
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+
+	_ = bw.flush()
+	return bw.takeErr()
+}
@@ -0,0 +1,124 @@
+package bufwriter_test
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils/bufwriter"
+)
+
+type syncBuffer struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes int
+	err    error
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err != nil {
+		return 0, b.err
+	}
+	b.writes++
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) state() (string, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String(), b.writes
+}
+
+func TestWriter_size(t *testing.T) {
+	out := &syncBuffer{}
+	w := bufwriter.New(out, &bufwriter.Options{Size: 10, FlushInterval: time.Hour})
+
+	_, _ = w.Write([]byte("12345\n"))
+	_, _ = w.Write([]byte("123\n"))
+	if got, _ := out.state(); got != "" {
+		t.Fatalf("expected buffered writes, got %q", got)
+	}
+
+	// Does not fit into the buffer
+	_, _ = w.Write([]byte("12\n"))
+	if got, writes := out.state(); got != "12345\n123\n" || writes != 1 {
+		t.Fatalf("expected one flush before write, got %q in %d writes", got, writes)
+	}
+
+	// Larger than the buffer
+	_, _ = w.Write([]byte("1234567890\n"))
+	if got, _ := out.state(); got != "12345\n123\n12\n1234567890\n" {
+		t.Fatalf("expected flush and direct write, got %q", got)
+	}
+
+	_, _ = w.Write([]byte("end\n"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := out.state(); got != "12345\n123\n12\n1234567890\nend\n" {
+		t.Fatalf("expected flush on close, got %q", got)
+	}
+}
+
+func TestWriter_interval(t *testing.T) {
+	out := &syncBuffer{}
+	w := bufwriter.New(out, &bufwriter.Options{FlushInterval: 10 * time.Millisecond})
+	defer w.Close()
+
+	_, _ = w.Write([]byte("test\n"))
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if got, _ := out.state(); got == "test\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected flush after interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWriter_flushError(t *testing.T) {
+	out := &syncBuffer{err: errors.New("disk full")}
+	w := bufwriter.New(out, &bufwriter.Options{FlushInterval: time.Hour})
+
+	_, _ = w.Write([]byte("test\n"))
+	if err := w.Flush(); err == nil {
+		t.Fatal("expected flush error")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("expected error to be returned only once, got %v", err)
+	}
+}
+
+func TestWriter_writeAfterFlushError(t *testing.T) {
+	out := &syncBuffer{err: errors.New("disk full")}
+	w := bufwriter.New(out, &bufwriter.Options{Size: 10, FlushInterval: time.Hour})
+
+	_, _ = w.Write([]byte("lost\n"))
+	n, err := w.Write([]byte("second\n"))
+	if err == nil || n != len("second\n") {
+		t.Fatalf("expected flush error with accepted write, got %d, %v", n, err)
+	}
+
+	out.mu.Lock()
+	out.err = nil
+	out.mu.Unlock()
+
+	if _, err := w.Write([]byte("third\n")); err != nil {
+		t.Fatalf("expected error to be returned only once, got %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := out.state(); got != "second\nthird\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
@@ -0,0 +1,323 @@
+// Package cef provides a handler writing security events as ArcSight CEF or IBM QRadar LEEF lines for SIEM ingestion.
+//
+// Attributes are written as extension fields, their qualified keys (groups joined by dots) can be mapped to
+// standard CEF or LEEF keys:
+//
+//	h := cef.NewHandler(os.Stdout, &cef.Options{
+//		Vendor:  "Acme",
+//		Product: "Admin",
+//		Version: "1.0",
+//		Extensions: map[string]string{
+//			"user":      "suser",
+//			"client.ip": "src",
+//		},
+//	})
+//	slog.New(h).Warn("Login failed", "event", "auth.failed", "user", "alice", slog.Group("client", "ip", "10.0.0.1"))
+//	// CEF:0|Acme|Admin|1.0|auth.failed|Login failed|6|rt=1700000000000 suser=alice src=10.0.0.1
+package cef
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Format is the format of written lines.
+type Format int
+
+const (
+	// FormatCEF writes ArcSight Common Event Format (CEF) version 0 lines.
+	FormatCEF Format = iota
+	// FormatLEEF writes IBM Log Event Extended Format (LEEF) version 1.0 lines with tab separated attributes.
+	FormatLEEF
+)
+
+// DefaultEventKey is the default key of the attribute used as signature ID (CEF) or event ID (LEEF).
+const DefaultEventKey = "event"
+
+// Time format of devTime in LEEF lines, it is declared as Java pattern in devTimeFormat.
+const (
+	leefTimeFormat     = "2006-01-02T15:04:05.000-0700"
+	leefTimeFormatJava = "yyyy-MM-dd'T'HH:mm:ss.SSSZ"
+)
+
+// Options are options for a Handler.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Level reports the minimum record level that will be logged.
+	// If Level is nil, the handler assumes slog.LevelInfo.
+	Level slog.Leveler
+
+	// Format of the lines, defaults to FormatCEF.
+	Format Format
+
+	// Vendor, Product and Version identify the device in the header.
+	// Product defaults to the base name of the executable, Vendor and Version to "unknown".
+	Vendor  string
+	Product string
+	Version string
+
+	// EventKey is the key of the attribute that is used as signature ID (CEF) or event ID (LEEF) instead of being
+	// written as extension field, defaults to DefaultEventKey. If the attribute is missing, the message is used.
+	EventKey string
+
+	// Extensions maps qualified keys of attributes (e.g. "user" or "client.ip") to extension keys
+	// (e.g. "suser" or "src"). Attributes without a mapping are written with their qualified key, with characters
+	// other than letters, digits and underscores replaced by underscores.
+	Extensions map[string]string
+
+	// DropUnmapped drops attributes without a mapping in Extensions.
+	DropUnmapped bool
+
+	// ToSeverity maps a level to a CEF severity (0-10), defaults to ToSeverity.
+	// LEEF lines use the same value for the "sev" attribute.
+	ToSeverity func(level slog.Level) int
+
+	// OnError is called with the record and the error if writing a line fails.
+	// The error is also returned by Handle, but discarded by slog.Logger.
+	OnError func(r slog.Record, err error)
+}
+
+// ToSeverity is the default mapping of levels to CEF severities.
+func ToSeverity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError+4:
+		return 10
+	case level >= slog.LevelError:
+		return 8
+	case level >= slog.LevelWarn:
+		return 6
+	case level >= slog.LevelInfo:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// Handler writes records as CEF or LEEF lines.
+type Handler struct {
+	w    *syncWriter
+	opts Options
+	goas []slogutils.GroupOrAttrs
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler creates a handler writing one line per record to w.
+func NewHandler(w io.Writer, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	o := *opts
+	if o.Level == nil {
+		o.Level = slog.LevelInfo
+	}
+	if o.Vendor == "" {
+		o.Vendor = "unknown"
+	}
+	if o.Product == "" {
+		o.Product = filepath.Base(os.Args[0])
+	}
+	if o.Version == "" {
+		o.Version = "unknown"
+	}
+	if o.EventKey == "" {
+		o.EventKey = DefaultEventKey
+	}
+	if o.ToSeverity == nil {
+		o.ToSeverity = ToSeverity
+	}
+
+	return &Handler{w: &syncWriter{w: w}, opts: o}
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	r, _ = slogutils.ExtractControls(r)
+
+	eventID := r.Message
+	var fields []slog.Attr
+	for _, a := range h.attrs(r) {
+		if a.Key == h.opts.EventKey {
+			eventID = a.Value.String()
+			continue
+		}
+		key, ok := h.opts.Extensions[a.Key]
+		if !ok {
+			if h.opts.DropUnmapped {
+				continue
+			}
+			key = fieldKey(a.Key)
+		}
+		fields = append(fields, slog.Attr{Key: key, Value: a.Value})
+	}
+
+	buf := new(bytes.Buffer)
+	severity := h.opts.ToSeverity(r.Level)
+	if h.opts.Format == FormatLEEF {
+		h.appendLEEF(buf, r, eventID, severity, fields)
+	} else {
+		h.appendCEF(buf, r, eventID, severity, fields)
+	}
+	buf.WriteByte('\n')
+
+	err := h.w.write(buf.Bytes())
+	if err != nil && h.opts.OnError != nil {
+		h.opts.OnError(r, err)
+	}
+	return err
+}
+
+// attrs returns the attributes of the handler and the record with qualified keys.
+func (h *Handler) attrs(r slog.Record) []slog.Attr {
+	var attrs []slog.Attr
+	prefix := ""
+	appendAttrs := func(as []slog.Attr) {
+		for _, a := range slogutils.FlattenAttrs(as, slogutils.GroupKeysDot) {
+			a.Key = prefix + a.Key
+			attrs = append(attrs, a)
+		}
+	}
+	for _, goa := range h.goas {
+		if goa.Group != "" {
+			prefix += goa.Group + "."
+			continue
+		}
+		appendAttrs(goa.Attrs)
+	}
+	appendAttrs(slogutils.RecordAttrs(r))
+	return attrs
+}
+
+func (h *Handler) appendCEF(buf *bytes.Buffer, r slog.Record, eventID string, severity int, fields []slog.Attr) {
+	// Header: CEF:Version|Device Vendor|Device Product|Device Version|Signature ID|Name|Severity|Extension
+	buf.WriteString("CEF:0|")
+	for _, field := range []string{h.opts.Vendor, h.opts.Product, h.opts.Version, eventID, r.Message} {
+		buf.WriteString(headerReplacer.Replace(field))
+		buf.WriteByte('|')
+	}
+	buf.WriteString(strconv.Itoa(severity))
+	buf.WriteByte('|')
+
+	sep := ""
+	if !r.Time.IsZero() {
+		buf.WriteString("rt=")
+		buf.WriteString(strconv.FormatInt(r.Time.UnixMilli(), 10))
+		sep = " "
+	}
+	for _, f := range fields {
+		buf.WriteString(sep)
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		buf.WriteString(cefValueReplacer.Replace(valueString(f.Value)))
+		sep = " "
+	}
+}
+
+func (h *Handler) appendLEEF(buf *bytes.Buffer, r slog.Record, eventID string, severity int, fields []slog.Attr) {
+	// Header: LEEF:Version|Vendor|Product|Version|EventID|
+	buf.WriteString("LEEF:1.0|")
+	for _, field := range []string{h.opts.Vendor, h.opts.Product, h.opts.Version, eventID} {
+		buf.WriteString(headerReplacer.Replace(field))
+		buf.WriteByte('|')
+	}
+
+	buf.WriteString("sev=")
+	buf.WriteString(strconv.Itoa(severity))
+	if !r.Time.IsZero() {
+		buf.WriteString("\tdevTime=")
+		buf.WriteString(r.Time.Format(leefTimeFormat))
+		buf.WriteString("\tdevTimeFormat=")
+		buf.WriteString(leefTimeFormatJava)
+	}
+	buf.WriteString("\tmsg=")
+	buf.WriteString(leefValueReplacer.Replace(r.Message))
+	for _, f := range fields {
+		buf.WriteByte('\t')
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		buf.WriteString(leefValueReplacer.Replace(valueString(f.Value)))
+	}
+}
+
+func valueString(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindTime:
+		return strconv.FormatInt(v.Time().UnixMilli(), 10)
+	case slog.KindFloat64:
+		return strconv.FormatFloat(v.Float64(), 'g', -1, 64)
+	default:
+		return v.String()
+	}
+}
+
+// fieldKey returns a valid extension key of letters, digits and underscores.
+func fieldKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	var sb strings.Builder
+	for _, c := range key {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			sb.WriteRune(c)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+var (
+	headerReplacer    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefValueReplacer  = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+	leefValueReplacer = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+)
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs) *Handler {
+	h2 := *h // Copy handler
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs, _ = slogutils.StripControlAttrs(attrs)
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs})
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name})
+}
+
+// syncWriter serializes writes of lines and is shared by all handlers derived with WithAttrs and WithGroup.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (sw *syncWriter) write(line []byte) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	_, err := sw.w.Write(line)
+	return err
+}
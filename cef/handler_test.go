@@ -0,0 +1,98 @@
+package cef_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils/cef"
+)
+
+var testTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		opts cef.Options
+		f    func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "cef with mapped extensions",
+			opts: cef.Options{
+				Extensions: map[string]string{"user": "suser", "client.ip": "src"},
+			},
+			f: func(l *slog.Logger) {
+				l.With("event", "auth.failed").WithGroup("client").Warn("Login failed", "ip", "10.0.0.1")
+				l.Info("Role changed", "user", "alice", "role", "admin")
+			},
+			want: "CEF:0|Acme|Admin|1.0|auth.failed|Login failed|6|rt=1704164645000 src=10.0.0.1\n" +
+				"CEF:0|Acme|Admin|1.0|Role changed|Role changed|3|rt=1704164645000 suser=alice role=admin\n",
+		},
+		{
+			name: "cef escaping",
+			f: func(l *slog.Logger) {
+				l.Error("a|b", "query", "x=1\ny", "path", `c:\tmp`, "http.method", "GET")
+			},
+			want: `CEF:0|Acme|Admin|1.0|a\|b|a\|b|8|rt=1704164645000 query=x\=1\ny path=c:\\tmp http_method=GET` + "\n",
+		},
+		{
+			name: "drop unmapped",
+			opts: cef.Options{
+				Extensions:   map[string]string{"user": "suser"},
+				DropUnmapped: true,
+			},
+			f: func(l *slog.Logger) {
+				l.Info("Login", "user", "alice", "session", "s1")
+			},
+			want: "CEF:0|Acme|Admin|1.0|Login|Login|3|rt=1704164645000 suser=alice\n",
+		},
+		{
+			name: "leef",
+			opts: cef.Options{
+				Format:     cef.FormatLEEF,
+				Extensions: map[string]string{"user": "usrName"},
+			},
+			f: func(l *slog.Logger) {
+				l.Warn("Login failed", "event", "auth.failed", "user", "alice")
+			},
+			want: "LEEF:1.0|Acme|Admin|1.0|auth.failed|sev=6\tdevTime=2024-01-02T03:04:05.000+0000\t" +
+				"devTimeFormat=yyyy-MM-dd'T'HH:mm:ss.SSSZ\tmsg=Login failed\tusrName=alice\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			opts := tt.opts
+			opts.Vendor, opts.Product, opts.Version = "Acme", "Admin", "1.0"
+			logger := slog.New(&fixedTimeHandler{cef.NewHandler(buf, &opts)})
+
+			tt.f(logger)
+
+			if got := buf.String(); tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", strings.ReplaceAll(tt.want, "\t", `\t`), strings.ReplaceAll(got, "\t", `\t`))
+			}
+		})
+	}
+}
+
+// fixedTimeHandler sets a fixed time for records.
+type fixedTimeHandler struct {
+	slog.Handler
+}
+
+func (h *fixedTimeHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.Time = testTime
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *fixedTimeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &fixedTimeHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *fixedTimeHandler) WithGroup(name string) slog.Handler {
+	return &fixedTimeHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,155 @@
+package childlog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/childlog"
+)
+
+func TestForward(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "message and level",
+			f: func(l *slog.Logger) {
+				l.Warn("test")
+			},
+			want: `level=WARN msg=test`,
+		},
+		{
+			name: "trace level",
+			f: func(l *slog.Logger) {
+				l.Log(context.Background(), slogutils.LevelTrace, "test")
+			},
+			want: `level=DEBUG-4 msg=test`,
+		},
+		{
+			name: "attribute kinds",
+			f: func(l *slog.Logger) {
+				l.Info("test",
+					"str", "a b",
+					"int", -42,
+					"uint", uint64(42),
+					"float", 1.5,
+					"bool", true,
+					"duration", 2*time.Second,
+					"at", time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC),
+					slogutils.Err(errors.New("fail")),
+				)
+			},
+			want: `level=INFO msg=test str="a b" int=-42 uint=42 float=1.5 bool=true duration=2s at=2023-08-01T12:00:00.000Z err=fail`,
+		},
+		{
+			name: "groups and attrs of handler",
+			f: func(l *slog.Logger) {
+				l.With("worker", 1).WithGroup("job").With("id", "j1").Info("test", slog.Group("result", "ok", true))
+			},
+			want: `level=INFO msg=test worker=1 job.id=j1 job.result.ok=true`,
+		},
+		{
+			name: "empty group is omitted",
+			f: func(l *slog.Logger) {
+				l.WithGroup("job").Info("test")
+			},
+			want: `level=INFO msg=test`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipe := new(bytes.Buffer)
+			child := slog.New(childlog.NewHandler(pipe, &childlog.HandlerOptions{Level: slogutils.LevelTrace}))
+			tt.f(child)
+
+			out := new(bytes.Buffer)
+			parent := slog.NewTextHandler(out, &slog.HandlerOptions{
+				Level: slogutils.LevelTrace,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+
+			if err := childlog.Forward(context.Background(), pipe, parent); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := strings.TrimRight(out.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReader_Read_truncated(t *testing.T) {
+	pipe := new(bytes.Buffer)
+	slog.New(childlog.NewHandler(pipe, nil)).Info("test")
+
+	r := childlog.NewReader(bytes.NewReader(pipe.Bytes()[:pipe.Len()-1]))
+	_, err := r.Read()
+	if err == nil || errors.Is(err, io.EOF) {
+		t.Fatalf("expected error for truncated frame, got: %v", err)
+	}
+}
+
+func TestHandler_OnError(t *testing.T) {
+	errClosed := errors.New("closed pipe")
+	var gotMsg string
+	var gotErr error
+	h := childlog.NewHandler(errWriter{err: errClosed}, &childlog.HandlerOptions{
+		OnError: func(r slog.Record, err error) {
+			gotMsg, gotErr = r.Message, err
+		},
+	})
+
+	if err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)); !errors.Is(err, errClosed) {
+		t.Fatalf("expected write error, got %v", err)
+	}
+	if gotMsg != "test" || !errors.Is(gotErr, errClosed) {
+		t.Fatalf("expected OnError to be called with record and error, got %q, %v", gotMsg, gotErr)
+	}
+}
+
+func TestHandler_frameSizeLimit(t *testing.T) {
+	pipe := new(bytes.Buffer)
+	var gotErr error
+	logger := slog.New(childlog.NewHandler(pipe, &childlog.HandlerOptions{
+		OnError: func(r slog.Record, err error) {
+			gotErr = err
+		},
+	}))
+
+	logger.Info("too large", "data", strings.Repeat("x", 16<<20))
+	logger.Info("next")
+
+	if gotErr == nil {
+		t.Fatal("expected error for oversized record")
+	}
+	r := childlog.NewReader(pipe)
+	record, err := r.Read()
+	if err != nil || record.Message != "next" {
+		t.Fatalf("expected next record to be readable, got %q, %v", record.Message, err)
+	}
+}
+
+type errWriter struct {
+	err error
+}
+
+func (w errWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
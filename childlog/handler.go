@@ -0,0 +1,143 @@
+// Package childlog forwards log records of a child process to its parent.
+//
+// The child process logs to a Handler writing length-prefixed encoded records to a pipe (e.g. stdout or an extra file
+// descriptor), the parent process uses Forward to decode them and pass them to its own handler (e.g. a CLIHandler).
+package childlog
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/networkteam/slogutils"
+)
+
+// HandlerOptions are options for a Handler.
+type HandlerOptions struct {
+	// Level reports the minimum record level that will be forwarded.
+	// If Level is nil, the handler assumes slog.LevelInfo.
+	Level slog.Leveler
+
+	// OnError is called with the record and the error if writing a frame fails, e.g. because the parent process
+	// exited or the encoded record exceeds the frame size limit of 16 MiB (the record is not written then).
+	// The error is also returned by Handle, but discarded by slog.Logger.
+	OnError func(r slog.Record, err error)
+}
+
+// Handler writes records as length-prefixed frames to a writer.
+type Handler struct {
+	w       io.Writer
+	level   slog.Leveler
+	onError func(r slog.Record, err error)
+	goas    []slogutils.GroupOrAttrs
+
+	mu *sync.Mutex
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler creates a handler that writes encoded records to w.
+func NewHandler(w io.Writer, opts *HandlerOptions) *Handler {
+	if opts == nil {
+		opts = &HandlerOptions{}
+	}
+	level := opts.Level
+	if level == nil {
+		level = slog.LevelInfo
+	}
+
+	return &Handler{
+		w:       w,
+		level:   level,
+		onError: opts.OnError,
+
+		mu: &sync.Mutex{},
+	}
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	recordAttrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		recordAttrs = append(recordAttrs, slogutils.ResolveAttr(a, 0))
+		return true
+	})
+
+	data, err := json.Marshal(wireRecord{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   h.buildAttrs(h.goas, encodeAttrs(recordAttrs)),
+	})
+	if err != nil {
+		return err
+	}
+
+	// The reader rejects larger frames and stops forwarding, so the record must not be written
+	if len(data) > maxFrameSize {
+		err = fmt.Errorf("encoded record size %d exceeds frame size limit", len(data))
+	} else {
+		frame := make([]byte, 4+len(data))
+		binary.BigEndian.PutUint32(frame, uint32(len(data)))
+		copy(frame[4:], data)
+
+		h.mu.Lock()
+		_, err = h.w.Write(frame)
+		h.mu.Unlock()
+	}
+
+	if err != nil && h.onError != nil {
+		h.onError(r, err)
+	}
+	return err
+}
+
+// buildAttrs nests the record attributes in the groups of the handler.
+func (h *Handler) buildAttrs(goas []slogutils.GroupOrAttrs, recordAttrs []wireAttr) []wireAttr {
+	if len(goas) == 0 {
+		return recordAttrs
+	}
+
+	goa := goas[0]
+	if goa.Group != "" {
+		inner := h.buildAttrs(goas[1:], recordAttrs)
+		if len(inner) == 0 {
+			return nil
+		}
+		return []wireAttr{{Key: goa.Group, Kind: slog.KindGroup, Group: inner}}
+	}
+	attrs := make([]slog.Attr, len(goa.Attrs))
+	for i, a := range goa.Attrs {
+		attrs[i] = slogutils.ResolveAttr(a, 0)
+	}
+	return append(encodeAttrs(attrs), h.buildAttrs(goas[1:], recordAttrs)...)
+}
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs) *Handler {
+	h2 := *h // Copy handler
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs})
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name})
+}
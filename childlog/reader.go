@@ -0,0 +1,78 @@
+package childlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Reader decodes records written by a Handler.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader creates a reader decoding records from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read decodes the next record. It returns io.EOF if the input ended cleanly between two records.
+func (r *Reader) Read() (slog.Record, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r.r, header[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return slog.Record{}, fmt.Errorf("reading frame header: %w", err)
+		}
+		return slog.Record{}, err
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return slog.Record{}, fmt.Errorf("frame size %d exceeds limit", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return slog.Record{}, fmt.Errorf("reading frame: %w", err)
+	}
+
+	var wr wireRecord
+	if err := json.Unmarshal(data, &wr); err != nil {
+		return slog.Record{}, fmt.Errorf("decoding record: %w", err)
+	}
+	attrs, err := decodeAttrs(wr.Attrs)
+	if err != nil {
+		return slog.Record{}, fmt.Errorf("decoding record attributes: %w", err)
+	}
+
+	record := slog.NewRecord(wr.Time, wr.Level, wr.Message, 0)
+	record.AddAttrs(attrs...)
+	return record, nil
+}
+
+// Forward reads records from r and passes them to the handler until the input ends.
+// Records below the level enabled by the handler are skipped.
+func Forward(ctx context.Context, r io.Reader, handler slog.Handler) error {
+	reader := NewReader(r)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, record); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package childlog
+
+import (
+	"encoding"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// maxFrameSize limits the size of a single encoded record to protect the reader against corrupt input.
+const maxFrameSize = 16 << 20
+
+type wireRecord struct {
+	Time    time.Time  `json:"time"`
+	Level   slog.Level `json:"level"`
+	Message string     `json:"msg"`
+	Attrs   []wireAttr `json:"attrs,omitempty"`
+}
+
+// wireAttr is the encoded form of a slog.Attr. Values are encoded as strings to keep them lossless.
+type wireAttr struct {
+	Key   string     `json:"k"`
+	Kind  slog.Kind  `json:"t"`
+	Value string     `json:"v,omitempty"`
+	Group []wireAttr `json:"g,omitempty"`
+}
+
+func encodeAttrs(attrs []slog.Attr) []wireAttr {
+	result := make([]wireAttr, 0, len(attrs))
+	for _, a := range attrs {
+		if wa, ok := encodeAttr(a); ok {
+			result = append(result, wa)
+		}
+	}
+	return result
+}
+
+func encodeAttr(a slog.Attr) (wireAttr, bool) {
+	if a.Equal(slog.Attr{}) {
+		return wireAttr{}, false
+	}
+
+	v := a.Value.Resolve()
+	wa := wireAttr{Key: a.Key, Kind: v.Kind()}
+	switch v.Kind() {
+	case slog.KindGroup:
+		wa.Group = encodeAttrs(v.Group())
+		if len(wa.Group) == 0 {
+			return wireAttr{}, false
+		}
+		// Inline attributes of groups with an empty key as slog does
+		if a.Key == "" && len(wa.Group) == 1 {
+			return wa.Group[0], true
+		}
+	case slog.KindString:
+		wa.Value = v.String()
+	case slog.KindInt64:
+		wa.Value = strconv.FormatInt(v.Int64(), 10)
+	case slog.KindUint64:
+		wa.Value = strconv.FormatUint(v.Uint64(), 10)
+	case slog.KindFloat64:
+		wa.Value = strconv.FormatFloat(v.Float64(), 'g', -1, 64)
+	case slog.KindBool:
+		wa.Value = strconv.FormatBool(v.Bool())
+	case slog.KindDuration:
+		wa.Value = strconv.FormatInt(int64(v.Duration()), 10)
+	case slog.KindTime:
+		wa.Value = v.Time().Format(time.RFC3339Nano)
+	default:
+		// Other values cannot be reconstructed in the parent process, so they are sent in their textual form
+		wa.Kind = slog.KindString
+		wa.Value = anyString(v.Any())
+	}
+	return wa, true
+}
+
+func anyString(v any) string {
+	switch x := v.(type) {
+	case encoding.TextMarshaler:
+		if data, err := x.MarshalText(); err == nil {
+			return string(data)
+		}
+	case error:
+		return x.Error()
+	}
+	return fmt.Sprint(v)
+}
+
+func decodeAttrs(was []wireAttr) ([]slog.Attr, error) {
+	attrs := make([]slog.Attr, 0, len(was))
+	for _, wa := range was {
+		a, err := decodeAttr(wa)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, a)
+	}
+	return attrs, nil
+}
+
+func decodeAttr(wa wireAttr) (slog.Attr, error) {
+	switch wa.Kind {
+	case slog.KindGroup:
+		attrs, err := decodeAttrs(wa.Group)
+		if err != nil {
+			return slog.Attr{}, err
+		}
+		return slog.Attr{Key: wa.Key, Value: slog.GroupValue(attrs...)}, nil
+	case slog.KindString:
+		return slog.String(wa.Key, wa.Value), nil
+	case slog.KindInt64:
+		i, err := strconv.ParseInt(wa.Value, 10, 64)
+		return slog.Int64(wa.Key, i), err
+	case slog.KindUint64:
+		u, err := strconv.ParseUint(wa.Value, 10, 64)
+		return slog.Uint64(wa.Key, u), err
+	case slog.KindFloat64:
+		f, err := strconv.ParseFloat(wa.Value, 64)
+		return slog.Float64(wa.Key, f), err
+	case slog.KindBool:
+		b, err := strconv.ParseBool(wa.Value)
+		return slog.Bool(wa.Key, b), err
+	case slog.KindDuration:
+		d, err := strconv.ParseInt(wa.Value, 10, 64)
+		return slog.Duration(wa.Key, time.Duration(d)), err
+	case slog.KindTime:
+		t, err := time.Parse(time.RFC3339Nano, wa.Value)
+		return slog.Time(wa.Key, t), err
+	default:
+		return slog.Attr{}, fmt.Errorf("unsupported attribute kind %d for key %q", wa.Kind, wa.Key)
+	}
+}
@@ -8,22 +8,17 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"sync"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
 	"github.com/mattn/go-colorable"
 )
 
-var cliDefaultLevelColors = map[slog.Level]*color.Color{
-	LevelTrace:      color.New(color.Faint),
-	slog.LevelDebug: color.New(color.FgWhite, color.Faint),
-	slog.LevelInfo:  color.New(color.FgBlue),
-	slog.LevelWarn:  color.New(color.FgYellow),
-	slog.LevelError: color.New(color.FgRed),
-}
-
 const cliDefaultPrefixPadding = 2
 
 var cliDefaultLevelPrefixes = map[slog.Level]string{
@@ -36,6 +31,18 @@ var cliDefaultLevelPrefixes = map[slog.Level]string{
 
 const cliDefaultMessagePadding = 25
 
+const cliDefaultDryRunPrefix = "[dry run]"
+
+// cliNoColor is used for all output if colors are disabled by CLIHandlerOptions.NoColor.
+var cliNoColor = func() *color.Color {
+	c := color.New()
+	c.DisableColor()
+	return c
+}()
+
+// sectionIndent is the number of spaces records of a section are indented with per nesting level.
+const sectionIndent = 2
+
 // CLIHandlerOptions are options for a CLIHandler.
 // A zero CLIHandlerOptions consists entirely of default values.
 type CLIHandlerOptions struct {
@@ -49,6 +56,10 @@ type CLIHandlerOptions struct {
 	// Prefix options for setting a custom padding and level prefixes.
 	Prefix *PrefixOptions
 
+	// Theme sets the colors of the handler, defaults to ThemeDark.
+	// LevelColors, DryRun.Color and BadgeColor override colors of the theme.
+	Theme *Theme
+
 	// LevelColors can set a custom map of level colors.
 	// It must be complete, i.e. contain all levels.
 	LevelColors map[slog.Level]*color.Color
@@ -58,9 +69,80 @@ type CLIHandlerOptions struct {
 	// Setting it to a negative value disables padding.
 	MessagePadding int
 
+	// DryRun options for rendering records of a dry run (see DryRun).
+	DryRun *DryRunOptions
+
+	// MaxDepth limits the nesting depth of groups, deeper groups are rendered as a truncation marker.
+	// A default of 16 is used if this is 0.
+	MaxDepth int
+
 	// ReplaceAttr is called to rewrite each non-group attribute before it is logged.
 	// See https://pkg.go.dev/log/slog#HandlerOptions for details.
+	// It is also called for the built-in message, level and time attributes (with nil groups): a level can be replaced
+	// by another level (to use its prefix and color) or a string to replace the prefix, an empty attribute omits the
+	// prefix. The time is only rendered (before the prefix) if it is replaced by a string.
 	ReplaceAttr func(groups []string, attr slog.Attr) slog.Attr
+
+	// Time optionally normalizes the record time and time attributes to a location (UTC by default), the skew offset
+	// is applied to the record time as by TimeHandler. If Time is nil, times are rendered as is.
+	Time *TimeOptions
+
+	// JSON renders maps, slices and structs logged with slog.Any as compact JSON instead of the Go syntax of
+	// fmt.Sprint. If JSON is nil, fmt.Sprint is used.
+	JSON *JSONOptions
+
+	// Formatters optionally format values for humans (e.g. FormatDuration, FormatByteSize or RelativeTime).
+	// The first formatter that handles a value is used, values are rendered unquoted.
+	Formatters []ValueFormatter
+
+	// NoColor disables colors regardless of the output being a terminal.
+	NoColor bool
+
+	// AddSource adds the source code position of the log statement as attribute with the key slog.SourceKey.
+	// ReplaceAttr is called for it with nil groups as for slog.HandlerOptions.
+	AddSource bool
+
+	// Source controls how source positions (of AddSource or attributes with a slog.Source value) are shortened.
+	// If Source is nil, the last two path segments of the file are rendered (e.g. "pkg/file.go:42").
+	Source *SourceOptions
+
+	// GroupKeys controls how keys of attributes in groups are joined, defaults to a dot.
+	// Attributes are always rendered with flat keys, so GroupKeysNested also uses a dot.
+	GroupKeys GroupKeys
+
+	// SortAttrs renders attributes sorted by their qualified key instead of in the order they were added.
+	SortAttrs bool
+
+	// Badges are qualified keys of attributes (e.g. "component" or "request.id") that are rendered as badges in
+	// brackets before the message instead of in the attributes, in the given order.
+	Badges []string
+
+	// BadgeColor is the color of badges, defaults to the badge color of the theme.
+	BadgeColor *color.Color
+
+	// DuplicateKeys is the strategy for attributes with the same qualified key, e.g. if a key was added with
+	// WithAttrs and again in the record. By default all attributes are rendered.
+	DuplicateKeys DuplicateKeys
+
+	// BeforeWrite is called with the output writer before a record is written, e.g. to clear an active spinner or
+	// progress line. AfterWrite is called after the record was written, e.g. to redraw it.
+	// Both are called while holding the lock of the handler, so writes of records are not interleaved.
+	BeforeWrite func(w io.Writer)
+	AfterWrite  func(w io.Writer)
+
+	// OnError is called with the record and the error if writing a record fails, e.g. to detect a broken pipe or a
+	// full disk. It is called while holding the lock of the handler. The error is also returned by Handle, but
+	// discarded by slog.Logger.
+	OnError func(r slog.Record, err error)
+}
+
+// DryRunOptions are options for rendering records of a dry run.
+type DryRunOptions struct {
+	// Prefix is written before the message, defaults to "[dry run]".
+	Prefix string
+
+	// Color of the level prefix and dry run prefix, defaults to the dry run color of the theme.
+	Color *color.Color
 }
 
 type PrefixOptions struct {
@@ -73,8 +155,8 @@ type PrefixOptions struct {
 }
 
 type CLIHandler struct {
-	w    io.Writer
-	goas []groupOrAttrs
+	w     io.Writer
+	state cliState
 
 	level          slog.Leveler
 	prefixPadding  int
@@ -82,6 +164,25 @@ type CLIHandler struct {
 	levelColors    map[slog.Level]*color.Color
 	replaceAttr    func(groups []string, attr slog.Attr) slog.Attr
 	messagePadding int
+	timeOptions    *TimeOptions
+	maxDepth       int
+	dryRunPrefix   string
+	dryRunColor    *color.Color
+	noColor        bool
+	sortAttrs      bool
+	duplicateKeys  DuplicateKeys
+	badges         []string
+	badgeColor     *color.Color
+	timeColor      *color.Color
+	errorLineColor *color.Color
+	jsonOptions    *JSONOptions
+	formatters     []ValueFormatter
+	groupSep       string
+	addSource      bool
+	sourceOptions  *SourceOptions
+	beforeWrite    func(w io.Writer)
+	afterWrite     func(w io.Writer)
+	onError        func(r slog.Record, err error)
 
 	mu *sync.Mutex
 }
@@ -104,8 +205,12 @@ func NewCLIHandler(w io.Writer, opts *CLIHandlerOptions) *CLIHandler {
 		}
 	}
 
+	theme := opts.Theme
+	if theme == nil {
+		theme = ThemeDark
+	}
 	if opts.LevelColors == nil {
-		opts.LevelColors = cliDefaultLevelColors
+		opts.LevelColors = theme.LevelColors
 	}
 
 	if opts.MessagePadding == 0 {
@@ -114,6 +219,22 @@ func NewCLIHandler(w io.Writer, opts *CLIHandlerOptions) *CLIHandler {
 		opts.MessagePadding = 0
 	}
 
+	dryRunPrefix := cliDefaultDryRunPrefix
+	dryRunColor := theme.DryRun
+	if opts.DryRun != nil {
+		if opts.DryRun.Prefix != "" {
+			dryRunPrefix = opts.DryRun.Prefix
+		}
+		if opts.DryRun.Color != nil {
+			dryRunColor = opts.DryRun.Color
+		}
+	}
+
+	badgeColor := theme.Badge
+	if opts.BadgeColor != nil {
+		badgeColor = opts.BadgeColor
+	}
+
 	if f, ok := w.(*os.File); ok {
 		w = colorable.NewColorable(f)
 	}
@@ -127,6 +248,25 @@ func NewCLIHandler(w io.Writer, opts *CLIHandlerOptions) *CLIHandler {
 		levelColors:    opts.LevelColors,
 		messagePadding: opts.MessagePadding,
 		replaceAttr:    opts.ReplaceAttr,
+		timeOptions:    opts.Time,
+		maxDepth:       opts.MaxDepth,
+		dryRunPrefix:   dryRunPrefix,
+		dryRunColor:    dryRunColor,
+		noColor:        opts.NoColor,
+		sortAttrs:      opts.SortAttrs,
+		duplicateKeys:  opts.DuplicateKeys,
+		badges:         opts.Badges,
+		badgeColor:     badgeColor,
+		timeColor:      theme.Time,
+		errorLineColor: theme.ErrorLine,
+		jsonOptions:    opts.JSON,
+		formatters:     opts.Formatters,
+		groupSep:       opts.GroupKeys.Separator(),
+		addSource:      opts.AddSource,
+		sourceOptions:  opts.Source,
+		beforeWrite:    opts.BeforeWrite,
+		afterWrite:     opts.AfterWrite,
+		onError:        opts.OnError,
 
 		mu: &sync.Mutex{},
 	}
@@ -137,8 +277,12 @@ func (h *CLIHandler) Enabled(ctx context.Context, level slog.Level) bool {
 }
 
 func (h *CLIHandler) Handle(ctx context.Context, r slog.Record) error {
-	levelColor := cliDefaultLevelColors[r.Level]
-	levelPrefix := h.levelPrefixes[r.Level]
+	if h.timeOptions != nil {
+		r.Time = h.timeOptions.recordTime(r.Time)
+	}
+	level := r.Level
+	levelPrefix := h.levelPrefixes[level]
+	timePrefix := ""
 
 	// Note: this handler should not be performance critical, so we don't use a buffer pool or pre-formatting for now.
 	buf := new(bytes.Buffer)
@@ -148,6 +292,24 @@ func (h *CLIHandler) Handle(ctx context.Context, r slog.Record) error {
 
 	msg := r.Message
 	if h.replaceAttr != nil {
+		// The time is not rendered, unless it is replaced by a string
+		if !r.Time.IsZero() {
+			if a := h.replaceAttr(nil, slog.Time(slog.TimeKey, r.Time)); a.Key != "" && a.Value.Kind() == slog.KindString {
+				timePrefix = a.Value.String()
+			}
+		}
+		// A level replaced by another level is rendered with its prefix and color, a string replaces the prefix
+		if a := h.replaceAttr(nil, slog.Any(slog.LevelKey, r.Level)); a.Key != "" {
+			switch v := a.Value.Resolve().Any().(type) {
+			case slog.Level:
+				level = v
+				levelPrefix = h.levelPrefixes[level]
+			case string:
+				levelPrefix = v
+			}
+		} else {
+			levelPrefix = ""
+		}
 		if a := h.replaceAttr(nil, slog.String(slog.MessageKey, msg)); a.Key != "" {
 			msg = a.Value.String()
 		} else {
@@ -155,97 +317,251 @@ func (h *CLIHandler) Handle(ctx context.Context, r slog.Record) error {
 		}
 	}
 
-	_, _ = levelColor.Fprintf(buf, "%*s", h.prefixPadding+1, levelPrefix)
-	_, _ = fmt.Fprintf(buf, " %-"+strconv.Itoa(h.messagePadding)+"s", msg)
-
-	// Handle state from WithGroup and WithAttrs.
-	goas := h.goas
-	if r.NumAttrs() == 0 {
-		// If the record has no Attrs, remove groups at the end of the list; they are empty.
-		for len(goas) > 0 && goas[len(goas)-1].group != "" {
-			goas = goas[:len(goas)-1]
-		}
+	levelColor := h.levelColors[level]
+	if levelColor == nil || h.noColor {
+		levelColor = cliNoColor
 	}
 
-	attrPrefix := ""
-	groups := make([]string, 0, len(goas))
-	for _, goa := range goas {
-		if goa.group != "" {
-			attrPrefix += goa.group + "."
-			groups = append(groups, goa.group)
-		} else {
-			for _, a := range goa.attrs {
-				if h.replaceAttr != nil {
-					a = h.replaceAttr(groups, a)
-				}
-				h.appendAttr(buf, levelColor, a, attrPrefix)
-			}
+	indent := sectionIndent * h.state.sectionDepth
+	prefixColor := levelColor
+	if h.state.dryRun || isDryRun(r) {
+		if !h.noColor {
+			prefixColor = h.dryRunColor
 		}
+		msg = prefixColor.Sprint(h.dryRunPrefix) + " " + msg
 	}
+
+	// Attributes of the handler were already collected by WithAttrs, the slices are clipped to be appended safely
+	attrs := slices.Clip(h.state.attrs)
+	errLines := slices.Clip(h.state.errLines)
 	r.Attrs(func(a slog.Attr) bool {
-		if h.replaceAttr != nil {
-			a = h.replaceAttr(groups, a)
+		// Section markers only affect loggers of a section, they are not rendered
+		if _, ok := sectionMarkerOf(a); ok || isDryRunAttr(a) {
+			return true
 		}
-		h.appendAttr(buf, levelColor, a, attrPrefix)
+		attrs, errLines = h.appendResolvedAttr(attrs, errLines, h.state.groups, a, h.state.attrPrefix)
 		return true
 	})
+	if h.addSource && r.PC != 0 {
+		a := slog.Any(slog.SourceKey, recordSource(r))
+		if h.replaceAttr != nil {
+			a = h.replaceAttr(nil, a)
+		}
+		attrs = h.appendAttr(attrs, a, "")
+	}
+
+	attrs = DedupAttrs(attrs, h.duplicateKeys)
+	if h.sortAttrs {
+		sort.SliceStable(attrs, func(i, j int) bool {
+			return attrs[i].Key < attrs[j].Key
+		})
+	}
+
+	var badges []string
+	if len(h.badges) > 0 {
+		badges, attrs = h.extractBadges(attrs)
+	}
+
+	if timePrefix != "" {
+		timeColor := h.timeColor
+		if h.noColor {
+			timeColor = cliNoColor
+		}
+		_, _ = timeColor.Fprint(buf, timePrefix)
+		buf.WriteRune(' ')
+	}
+	_, _ = fmt.Fprintf(buf, "%*s", indent, "")
+	_, _ = prefixColor.Fprintf(buf, "%*s", h.prefixPadding+1, levelPrefix)
+	buf.WriteRune(' ')
+	badgesWidth := 0
+	for _, badge := range badges {
+		badge = "[" + badge + "]"
+		badgeColor := h.badgeColor
+		if h.noColor {
+			badgeColor = cliNoColor
+		}
+		_, _ = badgeColor.Fprint(buf, badge)
+		buf.WriteRune(' ')
+		badgesWidth += utf8.RuneCountInString(badge) + 1
+	}
+	_, _ = fmt.Fprintf(buf, "%-"+strconv.Itoa(max(h.messagePadding-indent-badgesWidth, 0))+"s", msg)
+
+	for _, a := range attrs {
+		buf.WriteRune(' ')
+		levelColor.SetWriter(buf)
+		appendString(buf, a.Key, true)
+		levelColor.UnsetWriter(buf)
+		buf.WriteRune('=')
+		if src, ok := sourceOf(a.Value); ok {
+			buf.WriteString(h.sourceOptions.format(src))
+			continue
+		}
+		if s, ok := formatValue(h.formatters, a.Value); ok {
+			buf.WriteString(s)
+			continue
+		}
+		if s, ok := h.jsonOptions.value(a.Value); ok {
+			buf.WriteString(s)
+			continue
+		}
+		appendValue(buf, a.Value, true)
+	}
+
+	errColor := h.errorLineColor
+	if h.noColor {
+		errColor = cliNoColor
+	}
+	for _, a := range errLines {
+		_, _ = fmt.Fprintf(buf, "\n%*s", indent+h.prefixPadding+2, "")
+		_, _ = errColor.Fprintf(buf, "%s: %s", a.Key, a.Value.String())
+	}
 
 	buf.WriteRune('\n')
 
-	_, _ = buf.WriteTo(h.w)
+	if h.beforeWrite != nil {
+		h.beforeWrite(h.w)
+	}
+	_, err := buf.WriteTo(h.w)
+	if h.afterWrite != nil {
+		h.afterWrite(h.w)
+	}
+	if err != nil && h.onError != nil {
+		h.onError(r, err)
+	}
+
+	return err
+}
+
+// isDryRun checks if the attributes of the record mark a dry run.
+func isDryRun(r slog.Record) bool {
+	dryRun := false
+	r.Attrs(func(a slog.Attr) bool {
+		dryRun = isDryRunAttr(a)
+		return !dryRun
+	})
+	return dryRun
+}
+
+// extractBadges removes attributes with a badge key from attrs and returns their values in the order of the badges.
+func (h *CLIHandler) extractBadges(attrs []slog.Attr) (badges []string, rest []slog.Attr) {
+	values := make(map[string]string, len(h.badges))
+	rest = make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if slices.Contains(h.badges, a.Key) {
+			values[a.Key] = a.Value.String()
+			continue
+		}
+		rest = append(rest, a)
+	}
+	for _, key := range h.badges {
+		if value, ok := values[key]; ok {
+			badges = append(badges, value)
+		}
+	}
+	return badges, rest
+}
+
+// appendResolvedAttr resolves attr, applies ReplaceAttr and appends it to attrs.
+// Errors with joined errors in their chain are appended to errLines instead, so every error is rendered on its own line.
+func (h *CLIHandler) appendResolvedAttr(attrs, errLines []slog.Attr, groups []string, a slog.Attr, groupsPrefix string) ([]slog.Attr, []slog.Attr) {
+	if _, ok := errorChain(a.Value); ok {
+		if h.replaceAttr != nil {
+			a = h.replaceAttr(groups, a)
+		}
+		if chain, ok := errorChain(a.Value); ok {
+			return attrs, appendErrorLines(errLines, groupsPrefix+a.Key, chain)
+		}
+		return h.appendAttr(attrs, ResolveAttr(a, h.maxDepth), groupsPrefix), errLines
+	}
 
-	return nil
+	a = ResolveAttr(a, h.maxDepth)
+	if h.replaceAttr != nil {
+		a = h.replaceAttr(groups, a)
+	}
+	return h.appendAttr(attrs, a, groupsPrefix), errLines
 }
 
-func (h *CLIHandler) appendAttr(buf *bytes.Buffer, levelColor *color.Color, attr slog.Attr, groupsPrefix string) {
+// appendErrorLines appends the messages of the structured error value v with keys qualified by key.
+func appendErrorLines(errLines []slog.Attr, key string, v slog.Value) []slog.Attr {
+	if v.Kind() != slog.KindGroup {
+		return append(errLines, slog.Attr{Key: key, Value: v})
+	}
+	for _, a := range v.Group() {
+		errLines = appendErrorLines(errLines, key+"."+a.Key, a.Value)
+	}
+	return errLines
+}
+
+// appendAttr appends the non-group attributes of attr with keys qualified by groupsPrefix, so attrs
+// contains no groups.
+func (h *CLIHandler) appendAttr(attrs []slog.Attr, attr slog.Attr, groupsPrefix string) []slog.Attr {
 	if attr.Equal(slog.Attr{}) {
-		return
+		return attrs
 	}
 
 	attr.Value = attr.Value.Resolve()
 
 	switch attr.Value.Kind() {
 	case slog.KindGroup:
-		groupsPrefix += attr.Key + "."
+		groupsPrefix += attr.Key + h.groupSep
 		for _, groupAttr := range attr.Value.Group() {
-			h.appendAttr(buf, levelColor, groupAttr, groupsPrefix)
+			attrs = h.appendAttr(attrs, groupAttr, groupsPrefix)
 		}
+		return attrs
 	default:
-		buf.WriteRune(' ')
-		levelColor.SetWriter(buf)
-		appendString(buf, groupsPrefix+attr.Key, true)
-		levelColor.UnsetWriter(buf)
-		buf.WriteRune('=')
-		appendValue(buf, attr.Value, true)
+		if h.timeOptions != nil && attr.Value.Kind() == slog.KindTime {
+			attr.Value = slog.TimeValue(h.timeOptions.attrTime(attr.Value.Time()))
+		}
+		return append(attrs, slog.Attr{Key: groupsPrefix + attr.Key, Value: attr.Value})
 	}
 }
 
-// groupOrAttrs holds either a group name or a list of slog.Attrs.
-type groupOrAttrs struct {
-	group string      // group name if non-empty
-	attrs []slog.Attr // attrs if non-empty
-}
-
-func (h *CLIHandler) withGroupOrAttrs(goa groupOrAttrs) *CLIHandler {
-	h2 := *h // Copy handler
-	h2.goas = make([]groupOrAttrs, len(h.goas)+1)
-	copy(h2.goas, h.goas)
-	h2.goas[len(h2.goas)-1] = goa
-	return &h2
+// cliState is the state of WithGroup and WithAttrs. Attributes are resolved and collected once when a handler is
+// derived (as slog.TextHandler does), so loggers created with With are cheap to use for many records.
+// Slices are shared between derived handlers and must only be appended to after clipping them.
+type cliState struct {
+	// attrs are the non-group attributes with qualified keys, errLines the messages of joined errors
+	attrs, errLines []slog.Attr
+	// groups are the names of open groups, attrPrefix qualifies keys of attributes in the groups
+	groups     []string
+	attrPrefix string
+
+	sectionDepth int
+	dryRun       bool
 }
 
 func (h *CLIHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	if len(attrs) == 0 {
 		return h
 	}
-	return h.withGroupOrAttrs(groupOrAttrs{attrs: attrs})
+
+	h2 := *h // Copy handler
+	s := &h2.state
+	s.attrs = slices.Clip(s.attrs)
+	s.errLines = slices.Clip(s.errLines)
+	for _, a := range attrs {
+		if m, ok := sectionMarkerOf(a); ok {
+			s.sectionDepth = m.depth
+			continue
+		}
+		if isDryRunAttr(a) {
+			s.dryRun = true
+			continue
+		}
+		s.attrs, s.errLines = h.appendResolvedAttr(s.attrs, s.errLines, s.groups, a, s.attrPrefix)
+	}
+	return &h2
 }
 
 func (h *CLIHandler) WithGroup(name string) slog.Handler {
 	if name == "" {
 		return h
 	}
-	return h.withGroupOrAttrs(groupOrAttrs{group: name})
+
+	h2 := *h // Copy handler
+	h2.state.groups = append(slices.Clip(h.state.groups), name)
+	h2.state.attrPrefix += name + h.groupSep
+	return &h2
 }
 
 // Code inspired by github.com/lmittmann/tint
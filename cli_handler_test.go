@@ -3,8 +3,11 @@ package slogutils_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
@@ -138,7 +141,7 @@ func TestCLIHandler(t *testing.T) {
 			F: func(l *slog.Logger) {
 				l.Info("test", "key", "val")
 			},
-			Want: `  •                          `,
+			Want: `                             `,
 		},
 		{
 			F: func(l *slog.Logger) {
@@ -188,6 +191,209 @@ func TestCLIHandler(t *testing.T) {
 			},
 			Want: `  •  foo=bar`,
 		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				Time: &slogutils.TimeOptions{},
+			},
+			F: func(l *slog.Logger) {
+				l.Info("test", "at", time.Date(2023, 8, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600)))
+			},
+			Want: `  • test                      at="2023-08-01 11:00:00 +0000 UTC"`,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				MaxDepth: 1,
+			},
+			F: func(l *slog.Logger) {
+				l.Info("test", slog.Group("a", "b", 1, slog.Group("c", "d", 2)))
+			},
+			Want: `  • test                      a.b=1 a.c=[truncated]`,
+		},
+		{
+			F: func(l *slog.Logger) {
+				l.Error("test", slogutils.Err(errors.Join(errors.New("fail"), errors.New("other"))), "key", "val")
+			},
+			Want: "  ✕ test                      key=val\n    err.0: fail\n    err.1: other",
+		},
+		{
+			F: func(l *slog.Logger) {
+				err := fmt.Errorf("closing: %w", errors.Join(errors.New("fail"), errors.New("other")))
+				l.WithGroup("g").Error("test", slogutils.Errs(err, nil))
+			},
+			Want: "  ✕ test                     \n    g.err.msg: closing\n    g.err.cause.0: fail\n    g.err.cause.1: other",
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				ReplaceAttr: drop(slogutils.ErrorKey),
+			},
+			F: func(l *slog.Logger) {
+				l.Error("test", slogutils.Errs(errors.New("fail"), errors.New("other")))
+			},
+			Want: `  ✕ test                     `,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				DuplicateKeys: slogutils.DuplicateKeysLast,
+			},
+			F: func(l *slog.Logger) {
+				l.With("key", "a").WithGroup("g").With("key", "b").Info("test", "key", "c")
+			},
+			Want: `  • test                      key=a g.key=c`,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				DuplicateKeys: slogutils.DuplicateKeysSuffix,
+			},
+			F: func(l *slog.Logger) {
+				l.With("key", "a").Info("test", "key", "b")
+			},
+			Want: `  • test                      key=a key#2=b`,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.LevelKey && a.Value.Any().(slog.Level) == slog.LevelWarn {
+						return slog.Any(slog.LevelKey, slog.LevelError)
+					}
+					return a
+				},
+			},
+			F: func(l *slog.Logger) {
+				l.Warn("test")
+			},
+			Want: `  ✕ test                     `,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					switch a.Key {
+					case slog.LevelKey:
+						return slog.String(slog.LevelKey, "INFO")
+					case slog.TimeKey:
+						return slog.String(slog.TimeKey, "12:00:00")
+					}
+					return a
+				},
+			},
+			F: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			Want: `12:00:00 INFO test                     `,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				Time: &slogutils.TimeOptions{Location: time.FixedZone("CET", 3600)},
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey {
+						return slog.String(slog.TimeKey, a.Value.Time().Location().String())
+					}
+					return a
+				},
+			},
+			F: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			Want: `CET   • test                     `,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				Time: &slogutils.TimeOptions{
+					Skew: func() time.Duration {
+						return time.Until(time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC))
+					},
+				},
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey {
+						return slog.String(slog.TimeKey, a.Value.Time().Format(time.DateOnly))
+					}
+					return a
+				},
+			},
+			F: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			Want: `2030-01-01   • test                     `,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				ReplaceAttr: drop(slog.LevelKey, slog.TimeKey),
+			},
+			F: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			Want: `    test                     `,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				Badges: []string{"component", "request.id"},
+			},
+			F: func(l *slog.Logger) {
+				l.With("component", "db").Info("test", slog.Group("request", "id", "r1"), "key", "val")
+			},
+			Want: `  • [db] [r1] test            key=val`,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				JSON: &slogutils.JSONOptions{},
+			},
+			F: func(l *slog.Logger) {
+				l.Info("test", "slice", []string{"a", "b"}, "map", map[string]int{"a": 1, "b": 2}, "struct", struct {
+					Name string `json:"name"`
+				}{Name: "x y"}, "err", errors.New("fail"))
+			},
+			Want: `  • test                      slice=["a","b"] map={"a":1,"b":2} struct={"name":"x y"} err=fail`,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				JSON: &slogutils.JSONOptions{MaxDepth: 2, MaxSize: 20},
+			},
+			F: func(l *slog.Logger) {
+				l.Info("test", "deep", map[string]any{"a": map[string]any{"b": map[string]any{"c": 1}}}, "long", []string{"abcdefghij", "klmnopqrst"})
+			},
+			Want: `  • test                      deep={"a":{"b":"…"}} long=["abcdefghij","klmno…`,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				Formatters: []slogutils.ValueFormatter{slogutils.FormatDuration, slogutils.FormatByteSize},
+			},
+			F: func(l *slog.Logger) {
+				l.Info("test", "duration", 497*time.Millisecond, slogutils.Bytes("size", 13002342), "count", 3)
+			},
+			Want: `  • test                      duration=497ms size=12.4 MiB count=3`,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				GroupKeys: slogutils.GroupKeysUnderscore,
+			},
+			F: func(l *slog.Logger) {
+				l.WithGroup("req").Info("test", slog.Group("user", "id", 1))
+			},
+			Want: `  • test                      req_user_id=1`,
+		},
+		{
+			F: func(l *slog.Logger) {
+				l.Info("test", "src", slog.Source{File: "/home/dev/app/pkg/db/conn.go", Line: 42})
+			},
+			Want: `  • test                      src=db/conn.go:42`,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				Source: &slogutils.SourceOptions{TrimPrefix: "/home/dev/app"},
+			},
+			F: func(l *slog.Logger) {
+				l.Info("test", "src", &slog.Source{File: "/home/dev/app/pkg/db/conn.go", Line: 42})
+			},
+			Want: `  • test                      src=pkg/db/conn.go:42`,
+		},
+		{
+			Opts: &slogutils.CLIHandlerOptions{
+				Source: &slogutils.SourceOptions{Segments: 1},
+			},
+			F: func(l *slog.Logger) {
+				l.Info("test", "src", &slog.Source{File: "/home/dev/app/pkg/db/conn.go", Line: 42})
+			},
+			Want: `  • test                      src=conn.go:42`,
+		},
 	}
 
 	for i, test := range tests {
@@ -235,3 +441,78 @@ func replace(new slog.Value, keys ...string) func([]string, slog.Attr) slog.Attr
 		return a
 	}
 }
+
+func TestCLIHandler_BeforeAfterWrite(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slogutils.NewCLIHandler(&buf, &slogutils.CLIHandlerOptions{
+		BeforeWrite: func(w io.Writer) {
+			// Clear the progress line
+			_, _ = io.WriteString(w, "\r\x1b[2K")
+		},
+		AfterWrite: func(w io.Writer) {
+			_, _ = io.WriteString(w, "[=====>    ] 50%")
+		},
+	}))
+
+	l.Info("test")
+	l.With("key", "val").Info("test")
+
+	want := "\r\x1b[2K  • test                     \n[=====>    ] 50%" +
+		"\r\x1b[2K  • test                      key=val\n[=====>    ] 50%"
+	if got := buf.String(); want != got {
+		t.Fatalf("(-want +got)\n- %q\n+ %q", want, got)
+	}
+}
+
+func TestCLIHandler_AddSource(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slogutils.NewCLIHandler(&buf, &slogutils.CLIHandlerOptions{
+		AddSource: true,
+		Source:    &slogutils.SourceOptions{Segments: 1},
+	}))
+
+	logger.Info("test", "foo", "bar")
+
+	got := strings.TrimRight(buf.String(), "\n")
+	if !regexp.MustCompile(`^  • test                      foo=bar source=cli_handler_test\.go:\d+$`).MatchString(got) {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestCLIHandler_OnError(t *testing.T) {
+	var (
+		gotMsg string
+		gotErr error
+	)
+	logger := slog.New(slogutils.NewCLIHandler(failingWriter{}, &slogutils.CLIHandlerOptions{
+		OnError: func(r slog.Record, err error) {
+			gotMsg = r.Message
+			gotErr = err
+		},
+	}))
+
+	logger.Info("test")
+
+	if gotMsg != "test" || gotErr == nil || gotErr.Error() != "broken pipe" {
+		t.Fatalf("expected OnError to be called with record and error, got %q, %v", gotMsg, gotErr)
+	}
+}
+
+func BenchmarkCLIHandler_With(b *testing.B) {
+	logger := slog.New(slogutils.NewCLIHandler(io.Discard, &slogutils.CLIHandlerOptions{NoColor: true}))
+	logger = logger.With("app", "api", "env", "production").WithGroup("req")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l := logger.With("id", i, "method", "GET", "path", "/users")
+		l.Info("Request started")
+		l.Info("Request finished", "status", 200)
+	}
+}
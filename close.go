@@ -0,0 +1,92 @@
+package slogutils
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+)
+
+// Flusher is implemented by handlers that buffer records (e.g. network sinks) to send buffered records immediately.
+type Flusher interface {
+	Flush() error
+}
+
+// Wrapper is implemented by handlers that wrap another handler, so helpers like Flush and Close can walk a chain
+// of composed handlers.
+type Wrapper interface {
+	// Unwrap returns the wrapped handler or nil.
+	Unwrap() slog.Handler
+}
+
+// MultiHandler is implemented by handlers that delegate to multiple handlers (e.g. a fan-out or router).
+type MultiHandler interface {
+	Handlers() []slog.Handler
+}
+
+// Flush walks the handler and all handlers wrapped by it (see Wrapper and MultiHandler) from the outside in
+// and calls Flush on every handler implementing Flusher. The errors are joined.
+func Flush(h slog.Handler) error {
+	var errs []error
+	walkHandlers(h, func(h slog.Handler) {
+		if f, ok := h.(Flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// Close walks the handler and all handlers wrapped by it (see Wrapper and MultiHandler) from the outside in
+// and closes every handler implementing io.Closer, handlers only implementing Flusher are flushed.
+// Outer handlers are closed first, so records they buffered are passed to inner handlers before these are closed.
+// The errors are joined. Call Close with the handler of the logger on graceful shutdown:
+//
+//	defer slogutils.Close(logger.Handler())
+func Close(h slog.Handler) error {
+	var errs []error
+	walkHandlers(h, func(h slog.Handler) {
+		var err error
+		switch c := h.(type) {
+		case io.Closer:
+			err = c.Close()
+		case Flusher:
+			err = c.Flush()
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// walkHandlers calls f for h and all wrapped handlers in pre-order. Comparable handlers are visited only once.
+func walkHandlers(h slog.Handler, f func(h slog.Handler)) {
+	seen := make(map[slog.Handler]bool)
+
+	var walk func(h slog.Handler)
+	walk = func(h slog.Handler) {
+		if h == nil {
+			return
+		}
+		if reflect.TypeOf(h).Comparable() {
+			if seen[h] {
+				return
+			}
+			seen[h] = true
+		}
+
+		f(h)
+
+		switch w := h.(type) {
+		case Wrapper:
+			walk(w.Unwrap())
+		case MultiHandler:
+			for _, inner := range w.Handlers() {
+				walk(inner)
+			}
+		}
+	}
+	walk(h)
+}
@@ -0,0 +1,64 @@
+package slogutils_test
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+type closingHandler struct {
+	slog.Handler
+	name  string
+	calls *[]string
+	err   error
+}
+
+func (h *closingHandler) Close() error {
+	*h.calls = append(*h.calls, "close "+h.name)
+	return h.err
+}
+
+type flushingHandler struct {
+	slog.Handler
+	name  string
+	calls *[]string
+}
+
+func (h *flushingHandler) Flush() error {
+	*h.calls = append(*h.calls, "flush "+h.name)
+	return nil
+}
+
+func TestClose(t *testing.T) {
+	var calls []string
+	text := slog.NewTextHandler(io.Discard, nil)
+	sink := &closingHandler{Handler: text, name: "sink", calls: &calls, err: errors.New("sink failed")}
+	audit := &flushingHandler{Handler: text, name: "audit", calls: &calls}
+
+	h := slogutils.NewTimeHandler(
+		slogutils.NewContextHandler(
+			slogutils.NewChannelRouter(sink, map[string]slog.Handler{"audit": audit, "sink": sink}),
+		),
+		nil,
+	)
+
+	err := slogutils.Close(h)
+	if err == nil || err.Error() != "sink failed" {
+		t.Fatalf("expected joined error of sink, got %v", err)
+	}
+	if want := []string{"close sink", "flush audit"}; !reflect.DeepEqual(want, calls) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+
+	calls = nil
+	if err := slogutils.Flush(h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"flush audit"}; !reflect.DeepEqual(want, calls) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+}
@@ -25,3 +25,15 @@ func FromContext(ctx context.Context) *slog.Logger {
 func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
 	return context.WithValue(ctx, loggerKey, logger)
 }
+
+// With adds attributes (as for slog.Logger.With) to the logger of the context and returns a new context with the
+// derived logger.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
+
+// WithGroup starts a group (as for slog.Logger.WithGroup) for the logger of the context and returns a new context
+// with the derived logger.
+func WithGroup(ctx context.Context, name string) context.Context {
+	return WithLogger(ctx, FromContext(ctx).WithGroup(name))
+}
@@ -0,0 +1,107 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+)
+
+type attrsContextKey struct{}
+
+// ContextWithAttrs returns a new context with the attributes added to the attributes already stored in the context.
+// A ContextHandler adds these attributes to every record logged with the context.
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	existing := AttrsFromContext(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, attrsContextKey{}, merged)
+}
+
+// AttrsFromContext returns the attributes stored in the context by ContextWithAttrs.
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsContextKey{}).([]slog.Attr)
+	return attrs
+}
+
+// ContextHandler merges attributes stored in the context (see ContextWithAttrs) into every record.
+// The attributes are added at the top level, before attributes and groups of the handler.
+// Records must be logged with a context (e.g. logger.InfoContext) for the attributes to be added.
+type ContextHandler struct {
+	// base is the wrapped handler without groups and attributes of this handler
+	base slog.Handler
+	// next is the wrapped handler with groups and attributes of this handler applied
+	next slog.Handler
+	goas []GroupOrAttrs
+	// prefix caches the handler with context attributes before goas
+	prefix *PrefixCache
+}
+
+var (
+	_ slog.Handler = (*ContextHandler)(nil)
+	_ AttrsEnabler = (*ContextHandler)(nil)
+	_ Wrapper      = (*ContextHandler)(nil)
+)
+
+// NewContextHandler creates a new ContextHandler wrapping the given handler.
+func NewContextHandler(next slog.Handler) *ContextHandler {
+	return &ContextHandler{base: next, next: next}
+}
+
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *ContextHandler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *ContextHandler) EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+	return EnabledForAttrs(ctx, h.next, level, append(AttrsFromContext(ctx), attrs...))
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := AttrsFromContext(ctx)
+	if len(attrs) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	if len(h.goas) == 0 {
+		r2 := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+		r2.AddAttrs(attrs...)
+		r.Attrs(func(a slog.Attr) bool {
+			r2.AddAttrs(a)
+			return true
+		})
+		return h.next.Handle(ctx, r2)
+	}
+
+	return h.prefix.Handler(attrs).Handle(ctx, r)
+}
+
+func (h *ContextHandler) withGroupOrAttrs(goa GroupOrAttrs, next slog.Handler) *ContextHandler {
+	h2 := *h // Copy handler
+	h2.next = next
+	h2.goas = make([]GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	h2.prefix = NewPrefixCache(h.base, h2.goas)
+	return &h2
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Attrs: attrs}, h.next.WithAttrs(attrs))
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Group: name}, h.next.WithGroup(name))
+}
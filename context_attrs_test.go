@@ -0,0 +1,59 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestContextHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(ctx context.Context, l *slog.Logger)
+		want string
+	}{
+		{
+			name: "no attributes in context",
+			f: func(_ context.Context, l *slog.Logger) {
+				l.InfoContext(context.Background(), "test", "key", "val")
+			},
+			want: `level=INFO msg=test key=val`,
+		},
+		{
+			name: "attributes from context are added",
+			f: func(ctx context.Context, l *slog.Logger) {
+				l.InfoContext(ctx, "test", "key", "val")
+			},
+			want: `level=INFO msg=test request_id=r1 tenant=t1 key=val`,
+		},
+		{
+			name: "attributes from context are added before groups",
+			f: func(ctx context.Context, l *slog.Logger) {
+				l.With("a", 1).WithGroup("g").InfoContext(ctx, "test", "key", "val")
+			},
+			want: `level=INFO msg=test request_id=r1 tenant=t1 a=1 g.key=val`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := slog.New(slogutils.NewContextHandler(slog.NewTextHandler(buf, &slog.HandlerOptions{
+				ReplaceAttr: drop(slog.TimeKey),
+			})))
+
+			ctx := slogutils.ContextWithAttrs(context.Background(), slog.String("request_id", "r1"))
+			ctx = slogutils.ContextWithAttrs(ctx, slog.String("tenant", "t1"))
+			tt.f(ctx, logger)
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -41,3 +41,20 @@ func TestWithLogger(t *testing.T) {
 		t.Fatalf("unexpected log output: %s", buf.String())
 	}
 }
+
+func TestWith(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: drop(slog.TimeKey),
+	}))
+	ctx := slogutils.WithLogger(context.Background(), logger)
+
+	ctx = slogutils.With(ctx, "component", "test")
+	ctx = slogutils.WithGroup(ctx, "request")
+	ctx = slogutils.With(ctx, slog.Int("id", 1))
+
+	slogutils.FromContext(ctx).Info("Just a test")
+	if buf.String() != "level=INFO msg=\"Just a test\" component=test request.id=1\n" {
+		t.Fatalf("unexpected log output: %s", buf.String())
+	}
+}
@@ -0,0 +1,224 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Keys of reserved control attributes. Handlers interpreting them strip them before output.
+const (
+	controlKeyPrefix = "slogutils."
+
+	// ChannelKey is the key of the control attribute set by Channel.
+	ChannelKey = controlKeyPrefix + "channel"
+	// RetainKey is the key of the control attribute set by Retain.
+	RetainKey = controlKeyPrefix + "retain"
+)
+
+// Channel returns a control attribute that routes a record to the named channel (see ChannelRouter).
+func Channel(name string) slog.Attr {
+	return slog.String(ChannelKey, name)
+}
+
+// Retain returns a control attribute that declares the retention of a record (e.g. "30d") for handlers supporting it.
+func Retain(retention string) slog.Attr {
+	return slog.String(RetainKey, retention)
+}
+
+// Controls are the values of control attributes of a record.
+type Controls struct {
+	Channel string
+	Retain  string
+}
+
+// IsControlAttr reports whether the attribute is a reserved control attribute.
+func IsControlAttr(a slog.Attr) bool {
+	return strings.HasPrefix(a.Key, controlKeyPrefix)
+}
+
+// apply sets the control value of the attribute and reports whether it was a control attribute.
+func (c *Controls) apply(a slog.Attr) bool {
+	switch a.Key {
+	case ChannelKey:
+		c.Channel = a.Value.String()
+	case RetainKey:
+		c.Retain = a.Value.String()
+	default:
+		return IsControlAttr(a)
+	}
+	return true
+}
+
+// Merge returns controls with the non-empty values of other overriding the values of c.
+func (c Controls) Merge(other Controls) Controls {
+	if other.Channel != "" {
+		c.Channel = other.Channel
+	}
+	if other.Retain != "" {
+		c.Retain = other.Retain
+	}
+	return c
+}
+
+// ExtractControls returns the record without control attributes and the values of the control attributes.
+func ExtractControls(r slog.Record) (slog.Record, Controls) {
+	var controls Controls
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		if IsControlAttr(a) {
+			found = true
+			return false
+		}
+		return true
+	})
+	if !found {
+		return r, controls
+	}
+
+	r2 := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		if !controls.apply(a) {
+			r2.AddAttrs(a)
+		}
+		return true
+	})
+	return r2, controls
+}
+
+// StripControlAttrs returns attrs without control attributes and the values of the control attributes.
+// Handlers use it in WithAttrs (and ExtractControls in Handle), so control attributes are never rendered.
+// The attrs are returned unchanged if they contain no control attributes.
+func StripControlAttrs(attrs []slog.Attr) ([]slog.Attr, Controls) {
+	var controls Controls
+	i := slices.IndexFunc(attrs, IsControlAttr)
+	if i < 0 {
+		return attrs, controls
+	}
+
+	filtered := make([]slog.Attr, i, len(attrs)-1)
+	copy(filtered, attrs[:i])
+	for _, a := range attrs[i:] {
+		if !controls.apply(a) {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered, controls
+}
+
+type controlsContextKey struct{}
+
+// ControlsFromContext returns the controls of the record currently handled, as stored by ChannelRouter.
+// Handlers behind a ChannelRouter can use it to interpret controls like Retain.
+func ControlsFromContext(ctx context.Context) Controls {
+	controls, _ := ctx.Value(controlsContextKey{}).(Controls)
+	return controls
+}
+
+// ChannelRouter routes records to handlers by their Channel control attribute and strips all control attributes.
+// Records without a channel or with an unknown channel are passed to the default handler (if not nil).
+// The controls of a record are available to the handlers with ControlsFromContext.
+type ChannelRouter struct {
+	routes   map[string]slog.Handler
+	fallback slog.Handler
+	controls Controls
+}
+
+var (
+	_ slog.Handler = (*ChannelRouter)(nil)
+	_ MultiHandler = (*ChannelRouter)(nil)
+	_ AttrsEnabler = (*ChannelRouter)(nil)
+)
+
+// NewChannelRouter creates a router with handlers per channel name and a default handler (which can be nil).
+func NewChannelRouter(fallback slog.Handler, routes map[string]slog.Handler) *ChannelRouter {
+	return &ChannelRouter{routes: routes, fallback: fallback}
+}
+
+func (h *ChannelRouter) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.fallback != nil && h.fallback.Enabled(ctx, level) {
+		return true
+	}
+	for _, route := range h.routes {
+		if route.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnabledForAttrs reports whether the handler of the channel given by the attributes would handle the record.
+func (h *ChannelRouter) EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+	filtered, controls := StripControlAttrs(attrs)
+	target := h.route(h.controls.Merge(controls).Channel)
+	return target != nil && EnabledForAttrs(ctx, target, level, filtered)
+}
+
+// route returns the handler of the channel or the default handler.
+func (h *ChannelRouter) route(channel string) slog.Handler {
+	if route, ok := h.routes[channel]; ok {
+		return route
+	}
+	return h.fallback
+}
+
+// Handlers returns the default handler (if not nil) and the handlers of all channels sorted by channel name.
+func (h *ChannelRouter) Handlers() []slog.Handler {
+	names := make([]string, 0, len(h.routes))
+	for name := range h.routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	handlers := make([]slog.Handler, 0, len(h.routes)+1)
+	if h.fallback != nil {
+		handlers = append(handlers, h.fallback)
+	}
+	for _, name := range names {
+		handlers = append(handlers, h.routes[name])
+	}
+	return handlers
+}
+
+func (h *ChannelRouter) Handle(ctx context.Context, r slog.Record) error {
+	r, controls := ExtractControls(r)
+	controls = h.controls.Merge(controls)
+
+	target := h.route(controls.Channel)
+	if target == nil || !target.Enabled(ctx, r.Level) {
+		return nil
+	}
+
+	ctx = context.WithValue(ctx, controlsContextKey{}, controls)
+	return target.Handle(ctx, r)
+}
+
+func (h *ChannelRouter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	filtered, controls := StripControlAttrs(attrs)
+
+	return h.with(h.controls.Merge(controls), func(handler slog.Handler) slog.Handler {
+		return handler.WithAttrs(filtered)
+	})
+}
+
+func (h *ChannelRouter) WithGroup(name string) slog.Handler {
+	return h.with(h.controls, func(handler slog.Handler) slog.Handler {
+		return handler.WithGroup(name)
+	})
+}
+
+func (h *ChannelRouter) with(controls Controls, f func(handler slog.Handler) slog.Handler) *ChannelRouter {
+	h2 := &ChannelRouter{
+		routes:   make(map[string]slog.Handler, len(h.routes)),
+		controls: controls,
+	}
+	if h.fallback != nil {
+		h2.fallback = f(h.fallback)
+	}
+	for name, route := range h.routes {
+		h2.routes[name] = f(route)
+	}
+	return h2
+}
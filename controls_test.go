@@ -0,0 +1,104 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestExtractControls(t *testing.T) {
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+	r.AddAttrs(slog.String("key", "val"), slogutils.Channel("audit"), slogutils.Retain("30d"))
+
+	r2, controls := slogutils.ExtractControls(r)
+	if controls.Channel != "audit" || controls.Retain != "30d" {
+		t.Fatalf("unexpected controls: %+v", controls)
+	}
+	if r2.NumAttrs() != 1 {
+		t.Fatalf("expected control attributes to be stripped, got %d attrs", r2.NumAttrs())
+	}
+}
+
+func TestChannelRouter(t *testing.T) {
+	defaultBuf := new(bytes.Buffer)
+	auditBuf := new(bytes.Buffer)
+	newHandler := func(buf *bytes.Buffer) slog.Handler {
+		return slog.NewTextHandler(buf, &slog.HandlerOptions{ReplaceAttr: drop(slog.TimeKey)})
+	}
+
+	var retention string
+	audit := &controlsRecorder{Handler: newHandler(auditBuf), retention: &retention}
+
+	logger := slog.New(slogutils.NewChannelRouter(newHandler(defaultBuf), map[string]slog.Handler{
+		"audit": audit,
+	}))
+
+	logger.Info("regular", "key", "val")
+	logger.Info("user deleted", slogutils.Channel("audit"), slogutils.Retain("365d"), "user", "jane")
+	if retention != "365d" {
+		t.Fatalf("expected retention from controls, got %q", retention)
+	}
+	logger.With(slogutils.Channel("audit")).WithGroup("g").Info("role changed", "role", "admin")
+	logger.Info("unknown", slogutils.Channel("other"))
+
+	if want := "level=INFO msg=regular key=val\nlevel=INFO msg=unknown\n"; defaultBuf.String() != want {
+		t.Fatalf("unexpected default output: %s", defaultBuf.String())
+	}
+	if want := "level=INFO msg=\"user deleted\" user=jane\nlevel=INFO msg=\"role changed\" g.role=admin\n"; auditBuf.String() != want {
+		t.Fatalf("unexpected audit output: %s", auditBuf.String())
+	}
+}
+
+func TestChannelRouter_LogLazy(t *testing.T) {
+	buf := new(bytes.Buffer)
+	audit := slog.NewTextHandler(buf, &slog.HandlerOptions{ReplaceAttr: drop(slog.TimeKey)})
+	debug := slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelError})
+	logger := slog.New(slogutils.NewChannelRouter(nil, map[string]slog.Handler{"audit": audit, "debug": debug}))
+
+	called := false
+	lazy := func() []slog.Attr {
+		called = true
+		return []slog.Attr{slog.String("expensive", "value")}
+	}
+
+	ctx := context.Background()
+	slogutils.LogLazy(ctx, logger, slog.LevelInfo, "test", []slog.Attr{slogutils.Channel("debug")}, lazy)
+	if called {
+		t.Fatal("lazy attrs should not be constructed for disabled channel")
+	}
+	slogutils.LogLazy(ctx, logger, slog.LevelInfo, "test", nil, lazy)
+	if called {
+		t.Fatal("lazy attrs should not be constructed without default handler")
+	}
+
+	slogutils.LogLazy(ctx, logger, slog.LevelInfo, "test", []slog.Attr{slogutils.Channel("audit")}, lazy)
+	if !called {
+		t.Fatal("lazy attrs should be constructed")
+	}
+	if want := "level=INFO msg=test expensive=value\n"; buf.String() != want {
+		t.Fatalf("unexpected log output: %s", buf.String())
+	}
+}
+
+// controlsRecorder records the retention of the last record from the context.
+type controlsRecorder struct {
+	slog.Handler
+	retention *string
+}
+
+func (h *controlsRecorder) Handle(ctx context.Context, r slog.Record) error {
+	*h.retention = slogutils.ControlsFromContext(ctx).Retain
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *controlsRecorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &controlsRecorder{Handler: h.Handler.WithAttrs(attrs), retention: h.retention}
+}
+
+func (h *controlsRecorder) WithGroup(name string) slog.Handler {
+	return &controlsRecorder{Handler: h.Handler.WithGroup(name), retention: h.retention}
+}
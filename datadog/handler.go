@@ -0,0 +1,203 @@
+// Package datadog provides a handler mapping records to the reserved attributes of Datadog, so logs correlate with
+// APM traces and errors are tracked without custom pipelines.
+package datadog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Keys of reserved attributes of Datadog.
+const (
+	StatusKey       = "status"
+	TraceIDKey      = "dd.trace_id"
+	SpanIDKey       = "dd.span_id"
+	ErrorKindKey    = "error.kind"
+	ErrorMessageKey = "error.message"
+	ErrorStackKey   = "error.stack"
+)
+
+const (
+	traceIDAttrKey = "trace_id"
+	spanIDAttrKey  = "span_id"
+)
+
+// Handler maps records to reserved attributes of Datadog before delegating to another handler (usually a
+// slog.JSONHandler):
+//
+//   - the level is added as status (e.g. "info", "warn" or "error")
+//   - trace_id and span_id become dd.trace_id and dd.span_id
+//   - an error attribute (see slogutils.Err) becomes error.kind, error.message and error.stack
+//     (if the error was wrapped with slogutils.ErrWithStack)
+//   - groups are flattened with dot notation ("request.id"), which Datadog treats like nested attributes
+type Handler struct {
+	next slog.Handler
+	goas []slogutils.GroupOrAttrs
+}
+
+var (
+	_ slog.Handler           = (*Handler)(nil)
+	_ slogutils.AttrsEnabler = (*Handler)(nil)
+	_ slogutils.Wrapper      = (*Handler)(nil)
+)
+
+// NewHandler creates a new Handler wrapping the given handler.
+func NewHandler(next slog.Handler) *Handler {
+	return &Handler{next: next}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *Handler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *Handler) EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+	return slogutils.EnabledForAttrs(ctx, h.next, level, attrs)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	r, _ = slogutils.ExtractControls(r)
+
+	attrs := make([]slog.Attr, 0, 1+r.NumAttrs())
+	attrs = append(attrs, slog.String(StatusKey, Status(r.Level)))
+
+	prefix := ""
+	for _, goa := range h.goas {
+		if goa.Group != "" {
+			prefix += goa.Group + "."
+			continue
+		}
+		for _, a := range goa.Attrs {
+			attrs = appendAttr(attrs, prefix, slogutils.ResolveAttr(a, 0))
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendAttr(attrs, prefix, slogutils.ResolveAttr(a, 0))
+		return true
+	})
+
+	r2 := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r2.AddAttrs(attrs...)
+	return h.next.Handle(ctx, r2)
+}
+
+func appendAttr(attrs []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			attrs = appendAttr(attrs, groupPrefix, ga)
+		}
+		return attrs
+	}
+
+	if prefix == "" {
+		switch a.Key {
+		case traceIDAttrKey:
+			return append(attrs, slog.Attr{Key: TraceIDKey, Value: a.Value})
+		case spanIDAttrKey:
+			return append(attrs, slog.Attr{Key: SpanIDKey, Value: a.Value})
+		case slogutils.ErrorKey:
+			if err, ok := a.Value.Any().(error); ok {
+				return appendError(attrs, err)
+			}
+		}
+	}
+
+	a.Key = prefix + a.Key
+	return append(attrs, a)
+}
+
+func appendError(attrs []slog.Attr, err error) []slog.Attr {
+	attrs = append(attrs,
+		slog.String(ErrorKindKey, errorKind(err)),
+		slog.String(ErrorMessageKey, err.Error()),
+	)
+
+	var stackErr *slogutils.StackError
+	if errors.As(err, &stackErr) {
+		attrs = append(attrs, slog.String(ErrorStackKey, formatStack(stackErr.StackTrace())))
+	}
+	return attrs
+}
+
+// errorKind returns the type of the innermost error, since wrappers like StackError are not meaningful.
+func errorKind(err error) string {
+	for {
+		unwrapped := errors.Unwrap(err)
+		if unwrapped == nil {
+			return fmt.Sprintf("%T", err)
+		}
+		err = unwrapped
+	}
+}
+
+// formatStack formats a stack trace like a Go panic.
+func formatStack(pcs []uintptr) string {
+	var sb strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		_, _ = fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// Status maps a level to a Datadog status.
+// Levels below debug (like slogutils.LevelTrace) are mapped to "debug", levels above error to "critical".
+func Status(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError+4:
+		return "critical"
+	case level >= slog.LevelError:
+		return "error"
+	case level >= slog.LevelWarn:
+		return "warn"
+	case level >= slog.LevelInfo:
+		return "info"
+	default:
+		return "debug"
+	}
+}
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs) *Handler {
+	h2 := *h // Copy handler
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs, _ = slogutils.StripControlAttrs(attrs)
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs})
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name})
+}
@@ -0,0 +1,81 @@
+package datadog_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/datadog"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "status",
+			f: func(l *slog.Logger) {
+				l.Warn("test", "key", "val")
+			},
+			want: `{"level":"WARN","msg":"test","status":"warn","key":"val"}`,
+		},
+		{
+			name: "trace and span",
+			f: func(l *slog.Logger) {
+				l.With("trace_id", "123").Info("test", "span_id", "456")
+			},
+			want: `{"level":"INFO","msg":"test","status":"info","dd.trace_id":"123","dd.span_id":"456"}`,
+		},
+		{
+			name: "groups are flattened",
+			f: func(l *slog.Logger) {
+				l.With("a", 1).WithGroup("g").Info("test", slog.Group("h", "key", "val"), "trace_id", "123")
+			},
+			want: `{"level":"INFO","msg":"test","status":"info","a":1,"g.h.key":"val","g.trace_id":"123"}`,
+		},
+		{
+			name: "error",
+			f: func(l *slog.Logger) {
+				l.Error("test", slogutils.Err(fmt.Errorf("wrapped: %w", errors.New("fail"))))
+			},
+			want: `{"level":"ERROR","msg":"test","status":"error","error.kind":"*errors.errorString","error.message":"wrapped: fail"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := slog.New(datadog.NewHandler(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})))
+			tt.f(logger)
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHandler_errorStack(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(datadog.NewHandler(slog.NewTextHandler(buf, nil)))
+
+	logger.Error("test", slogutils.ErrWithStack(errors.New("fail")))
+
+	if !strings.Contains(buf.String(), "error.stack=") || !strings.Contains(buf.String(), "TestHandler_errorStack") {
+		t.Fatalf("expected stack trace in output, got %s", buf.String())
+	}
+}
@@ -0,0 +1,136 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+)
+
+// DuplicateKeys is a strategy for attributes with the same key in the same group, e.g. if a key was added with
+// WithAttrs and again in the record.
+type DuplicateKeys int
+
+const (
+	// DuplicateKeysAllow keeps all attributes, so a key can appear multiple times (the default of slog handlers).
+	DuplicateKeysAllow DuplicateKeys = iota
+	// DuplicateKeysLast keeps only the last attribute with a key, at the position of the last attribute.
+	DuplicateKeysLast
+	// DuplicateKeysSuffix keeps all attributes, but adds a suffix with a counter to repeated keys ("key#2", "key#3").
+	DuplicateKeysSuffix
+)
+
+// DedupAttrs applies the strategy to attributes with the same key in attrs and (recursively) in groups.
+// Groups without a key are inlined. The given slice is not modified.
+func DedupAttrs(attrs []slog.Attr, strategy DuplicateKeys) []slog.Attr {
+	if strategy == DuplicateKeysAllow {
+		return attrs
+	}
+
+	attrs = inlineGroups(attrs)
+	result := make([]slog.Attr, 0, len(attrs))
+	switch strategy {
+	case DuplicateKeysLast:
+		last := make(map[string]int, len(attrs))
+		for i, a := range attrs {
+			last[a.Key] = i
+		}
+		for i, a := range attrs {
+			if last[a.Key] == i {
+				result = append(result, dedupGroup(a, strategy))
+			}
+		}
+	case DuplicateKeysSuffix:
+		seen := make(map[string]int, len(attrs))
+		for _, a := range attrs {
+			seen[a.Key]++
+			if n := seen[a.Key]; n > 1 {
+				a.Key += "#" + strconv.Itoa(n)
+			}
+			result = append(result, dedupGroup(a, strategy))
+		}
+	}
+	return result
+}
+
+func dedupGroup(a slog.Attr, strategy DuplicateKeys) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup {
+		a.Value = slog.GroupValue(DedupAttrs(a.Value.Group(), strategy)...)
+	}
+	return a
+}
+
+// inlineGroups replaces groups without a key by their attributes, as handlers do.
+func inlineGroups(attrs []slog.Attr) []slog.Attr {
+	for _, a := range attrs {
+		if a.Key == "" && a.Value.Kind() == slog.KindGroup {
+			inlined := make([]slog.Attr, 0, len(attrs))
+			for _, a := range attrs {
+				if a.Key == "" && a.Value.Kind() == slog.KindGroup {
+					inlined = append(inlined, inlineGroups(a.Value.Group())...)
+				} else {
+					inlined = append(inlined, a)
+				}
+			}
+			return inlined
+		}
+	}
+	return attrs
+}
+
+// DedupHandler applies a strategy for duplicate keys (see DuplicateKeys) to attributes of the handler and the record.
+// Attributes and groups of the handler are collected and passed to the wrapped handler as part of the record, since
+// attributes added with WithAttrs cannot be removed later.
+type DedupHandler struct {
+	next     slog.Handler
+	strategy DuplicateKeys
+	goas     []GroupOrAttrs
+}
+
+var (
+	_ slog.Handler = (*DedupHandler)(nil)
+	_ Wrapper      = (*DedupHandler)(nil)
+)
+
+// NewDedupHandler creates a new DedupHandler wrapping the given handler.
+func NewDedupHandler(next slog.Handler, strategy DuplicateKeys) *DedupHandler {
+	return &DedupHandler{next: next, strategy: strategy}
+}
+
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *DedupHandler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := NestAttrs(h.goas, RecordAttrs(r))
+
+	r2 := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r2.AddAttrs(DedupAttrs(attrs, h.strategy)...)
+	return h.next.Handle(ctx, r2)
+}
+
+func (h *DedupHandler) withGroupOrAttrs(goa GroupOrAttrs) *DedupHandler {
+	h2 := *h // Copy handler
+	h2.goas = make([]GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Attrs: attrs})
+}
+
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Group: name})
+}
@@ -0,0 +1,76 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestDedupHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy slogutils.DuplicateKeys
+		f        func(l *slog.Logger)
+		want     string
+	}{
+		{
+			name:     "allow keeps all attributes",
+			strategy: slogutils.DuplicateKeysAllow,
+			f: func(l *slog.Logger) {
+				l.With("user", "a").Info("test", "user", "b")
+			},
+			want: `level=INFO msg=test user=a user=b`,
+		},
+		{
+			name:     "last keeps the last attribute",
+			strategy: slogutils.DuplicateKeysLast,
+			f: func(l *slog.Logger) {
+				l.With("user", "a", "id", 1).Info("test", "user", "b")
+			},
+			want: `level=INFO msg=test id=1 user=b`,
+		},
+		{
+			name:     "suffix adds counter to repeated keys",
+			strategy: slogutils.DuplicateKeysSuffix,
+			f: func(l *slog.Logger) {
+				l.With("user", "a").Info("test", "user", "b", "user", "c")
+			},
+			want: `level=INFO msg=test user=a user#2=b user#3=c`,
+		},
+		{
+			name:     "keys are deduplicated in groups",
+			strategy: slogutils.DuplicateKeysLast,
+			f: func(l *slog.Logger) {
+				l.With("user", "a").WithGroup("g").With("user", "b").Info("test", "user", "c", slog.Group("", "user", "d"))
+			},
+			want: `level=INFO msg=test user=a g.user=d`,
+		},
+		{
+			name:     "empty groups are omitted",
+			strategy: slogutils.DuplicateKeysLast,
+			f: func(l *slog.Logger) {
+				l.With("user", "a").WithGroup("g").Info("test")
+			},
+			want: `level=INFO msg=test user=a`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := slog.New(slogutils.NewDedupHandler(slog.NewTextHandler(buf, &slog.HandlerOptions{
+				ReplaceAttr: drop(slog.TimeKey),
+			}), tt.strategy))
+
+			tt.f(logger)
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
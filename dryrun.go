@@ -0,0 +1,32 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+)
+
+// DryRunKey is the key for the dry run attribute of loggers returned by DryRun.
+const DryRunKey = "dry_run"
+
+type dryRunContextKey struct{}
+
+// DryRun returns a context for simulated (dry run) operations. The logger of the context is tagged with
+// dry_run=true, which the CLIHandler renders with a distinct prefix and color.
+// Tools can check IsDryRun to skip destructive actions.
+func DryRun(ctx context.Context) context.Context {
+	if IsDryRun(ctx) {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, dryRunContextKey{}, true)
+	return WithLogger(ctx, FromContext(ctx).With(slog.Bool(DryRunKey, true)))
+}
+
+// IsDryRun reports whether the context was returned by DryRun.
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunContextKey{}).(bool)
+	return dryRun
+}
+
+func isDryRunAttr(a slog.Attr) bool {
+	return a.Key == DryRunKey && a.Value.Kind() == slog.KindBool && a.Value.Bool()
+}
@@ -0,0 +1,38 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestDryRun(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slogutils.NewCLIHandler(buf, nil))
+	ctx := slogutils.WithLogger(context.Background(), logger)
+
+	if slogutils.IsDryRun(ctx) {
+		t.Fatal("context should not be a dry run")
+	}
+
+	dryCtx := slogutils.DryRun(ctx)
+	if !slogutils.IsDryRun(dryCtx) {
+		t.Fatal("context should be a dry run")
+	}
+
+	slogutils.FromContext(dryCtx).Info("Deleting bucket", "bucket", "assets")
+	slogutils.FromContext(ctx).Info("Deleting bucket", "bucket", "assets")
+
+	want := strings.Join([]string{
+		`  • [dry run] Deleting bucket bucket=assets`,
+		`  • Deleting bucket           bucket=assets`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
@@ -0,0 +1,122 @@
+// Package ecs maps records to the Elastic Common Schema (ECS), so JSON output is directly ingestible by
+// Elastic or Filebeat without pipeline processors.
+package ecs
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Version is the ECS version added to records by NewHandler.
+const Version = "8.11"
+
+// Keys of ECS fields.
+const (
+	TimestampKey   = "@timestamp"
+	LogLevelKey    = "log.level"
+	MessageKey     = "message"
+	LogOriginKey   = "log.origin"
+	ErrorKey       = "error"
+	TraceIDKey     = "trace.id"
+	SpanIDKey      = "span.id"
+	ECSVersionKey  = "ecs.version"
+	ServiceNameKey = "service.name"
+)
+
+const (
+	traceIDAttrKey  = "trace_id"
+	spanIDAttrKey   = "span_id"
+	serviceAttrKey  = "service"
+	errorMessageKey = "message"
+	errorTypeKey    = "type"
+)
+
+// ReplaceAttr renames standard fields to ECS fields.
+// It can be used as slog.HandlerOptions.ReplaceAttr of a slog.JSONHandler:
+//
+//   - time becomes @timestamp
+//   - level becomes log.level with a lowercase value (including "trace" for slogutils.LevelTrace)
+//   - msg becomes message
+//   - source becomes log.origin with file.name, file.line and function
+//   - err (see slogutils.Err) becomes error with message and type
+//   - trace_id, span_id and service become trace.id, span.id and service.name
+//
+// Only attributes at the top level are renamed.
+func ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.TimeKey:
+		a.Key = TimestampKey
+	case slog.LevelKey:
+		a.Key = LogLevelKey
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(levelName(level))
+		}
+	case slog.MessageKey:
+		a.Key = MessageKey
+	case slog.SourceKey:
+		if src, ok := a.Value.Any().(*slog.Source); ok {
+			return slog.Group(LogOriginKey,
+				slog.Group("file", slog.String("name", src.File), slog.Int("line", src.Line)),
+				slog.String("function", src.Function),
+			)
+		}
+		a.Key = LogOriginKey
+	case slogutils.ErrorKey:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.Group(ErrorKey,
+				slog.String(errorMessageKey, err.Error()),
+				slog.String(errorTypeKey, fmt.Sprintf("%T", err)),
+			)
+		}
+		a.Key = ErrorKey
+	case traceIDAttrKey:
+		a.Key = TraceIDKey
+	case spanIDAttrKey:
+		a.Key = SpanIDKey
+	case serviceAttrKey:
+		a.Key = ServiceNameKey
+	}
+	return a
+}
+
+func levelName(level slog.Level) string {
+	if level <= slogutils.LevelTrace {
+		return "trace"
+	}
+	// Levels between the standard levels are rendered like "INFO+2"
+	return strings.ToLower(level.String())
+}
+
+// NewHandler creates a slog.JSONHandler writing ECS compatible records with the ecs.version field.
+// A ReplaceAttr function of the options is called before the mapping to ECS fields, control attributes (see
+// slogutils.Channel) are dropped.
+func NewHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	var o slog.HandlerOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	replaceAttr := o.ReplaceAttr
+	o.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		if slogutils.IsControlAttr(a) {
+			return slog.Attr{}
+		}
+		if replaceAttr != nil {
+			a = replaceAttr(groups, a)
+			if a.Equal(slog.Attr{}) {
+				return a
+			}
+		}
+		return ReplaceAttr(groups, a)
+	}
+
+	return slog.NewJSONHandler(w, &o).WithAttrs([]slog.Attr{slog.String(ECSVersionKey, Version)})
+}
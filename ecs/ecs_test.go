@@ -0,0 +1,78 @@
+package ecs_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/ecs"
+)
+
+func TestNewHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "standard fields",
+			f: func(l *slog.Logger) {
+				l.Info("test", "key", "val")
+			},
+			want: `{"log.level":"info","message":"test","ecs.version":"8.11","key":"val"}`,
+		},
+		{
+			name: "trace level",
+			f: func(l *slog.Logger) {
+				l.Log(context.Background(), slogutils.LevelTrace, "test")
+			},
+			want: `{"log.level":"trace","message":"test","ecs.version":"8.11"}`,
+		},
+		{
+			name: "error",
+			f: func(l *slog.Logger) {
+				l.Error("test", slogutils.Err(errors.New("fail")))
+			},
+			want: `{"log.level":"error","message":"test","ecs.version":"8.11","error":{"message":"fail","type":"*errors.errorString"}}`,
+		},
+		{
+			name: "trace and service",
+			f: func(l *slog.Logger) {
+				l.With("service", "api").Info("test", "trace_id", "t1", "span_id", "s1")
+			},
+			want: `{"log.level":"info","message":"test","ecs.version":"8.11","service.name":"api","trace.id":"t1","span.id":"s1"}`,
+		},
+		{
+			name: "grouped attributes are not renamed",
+			f: func(l *slog.Logger) {
+				l.WithGroup("g").Info("test", "trace_id", "t1")
+			},
+			want: `{"log.level":"info","message":"test","ecs.version":"8.11","g":{"trace_id":"t1"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := slog.New(ecs.NewHandler(buf, &slog.HandlerOptions{
+				Level: slogutils.LevelTrace,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			tt.f(logger)
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
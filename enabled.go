@@ -0,0 +1,47 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// AttrsEnabler is implemented by handlers that can decide whether a record would be handled based on its level
+// and attributes, e.g. handlers filtering by a component attribute.
+// Wrapping handlers should implement it by delegating to EnabledForAttrs with their wrapped handler.
+type AttrsEnabler interface {
+	EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool
+}
+
+// EnabledForAttrs reports whether the handler would handle a record with the given level and attributes.
+// It falls back to slog.Handler.Enabled if the handler does not implement AttrsEnabler.
+func EnabledForAttrs(ctx context.Context, h slog.Handler, level slog.Level, attrs []slog.Attr) bool {
+	if !h.Enabled(ctx, level) {
+		return false
+	}
+	if ae, ok := h.(AttrsEnabler); ok {
+		return ae.EnabledForAttrs(ctx, level, attrs)
+	}
+	return true
+}
+
+// LogLazy logs a record with the given attributes and additional attributes that are only constructed
+// if the handler of the logger would handle the record, as reported by EnabledForAttrs.
+// The attributes passed directly should be cheap (e.g. a component), expensive ones should be returned by lazy.
+func LogLazy(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, attrs []slog.Attr, lazy func() []slog.Attr) {
+	h := logger.Handler()
+	if !EnabledForAttrs(ctx, h, level, attrs) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers and LogLazy
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(attrs...)
+	if lazy != nil {
+		r.AddAttrs(lazy()...)
+	}
+	_ = h.Handle(ctx, r)
+}
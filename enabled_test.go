@@ -0,0 +1,58 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestLogLazy(t *testing.T) {
+	buf := new(bytes.Buffer)
+	var h slog.Handler = slog.NewTextHandler(buf, &slog.HandlerOptions{ReplaceAttr: drop(slog.TimeKey)})
+	h = &componentFilter{Handler: h, denied: "db"}
+	// Wrapping handlers must pass the check to the wrapped handler
+	h = slogutils.NewTimeHandler(h, nil)
+	logger := slog.New(h)
+
+	called := false
+	lazy := func() []slog.Attr {
+		called = true
+		return []slog.Attr{slog.String("expensive", "value")}
+	}
+
+	slogutils.LogLazy(context.Background(), logger, slog.LevelInfo, "test", []slog.Attr{slog.String("component", "db")}, lazy)
+	if called {
+		t.Fatal("lazy attrs should not be constructed for filtered component")
+	}
+
+	slogutils.LogLazy(context.Background(), logger, slog.LevelDebug, "test", []slog.Attr{slog.String("component", "http")}, lazy)
+	if called {
+		t.Fatal("lazy attrs should not be constructed for disabled level")
+	}
+
+	slogutils.LogLazy(context.Background(), logger, slog.LevelInfo, "test", []slog.Attr{slog.String("component", "http")}, lazy)
+	if !called {
+		t.Fatal("lazy attrs should be constructed")
+	}
+	if buf.String() != "level=INFO msg=test component=http expensive=value\n" {
+		t.Fatalf("unexpected log output: %s", buf.String())
+	}
+}
+
+// componentFilter is a handler that filters records by a component attribute.
+type componentFilter struct {
+	slog.Handler
+	denied string
+}
+
+func (h *componentFilter) EnabledForAttrs(_ context.Context, _ slog.Level, attrs []slog.Attr) bool {
+	for _, a := range attrs {
+		if a.Key == "component" && a.Value.String() == h.denied {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ErrorAttrsHandler adds attributes carried by logged errors (see WrapErr) to every record.
+// Errors are taken from top-level attributes of the record, the attributes are added at the top level
+// as well, before attributes and groups of the handler.
+type ErrorAttrsHandler struct {
+	// base is the wrapped handler without groups and attributes of this handler
+	base slog.Handler
+	// next is the wrapped handler with groups and attributes of this handler applied
+	next slog.Handler
+	goas []GroupOrAttrs
+	// prefix caches the handler with error attributes before goas
+	prefix *PrefixCache
+}
+
+var (
+	_ slog.Handler = (*ErrorAttrsHandler)(nil)
+	_ Wrapper      = (*ErrorAttrsHandler)(nil)
+)
+
+// NewErrorAttrsHandler creates a new ErrorAttrsHandler wrapping the given handler.
+func NewErrorAttrsHandler(next slog.Handler) *ErrorAttrsHandler {
+	return &ErrorAttrsHandler{base: next, next: next}
+}
+
+func (h *ErrorAttrsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *ErrorAttrsHandler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *ErrorAttrsHandler) Handle(ctx context.Context, r slog.Record) error {
+	var attrs []slog.Attr
+	r.Attrs(func(a slog.Attr) bool {
+		if err, ok := a.Value.Any().(error); ok && a.Value.Kind() == slog.KindAny {
+			attrs = append(attrs, ErrorAttrs(err)...)
+		}
+		return true
+	})
+	if len(attrs) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	if len(h.goas) == 0 {
+		return h.next.Handle(ctx, CloneRecordWithAttrs(r, attrs...))
+	}
+
+	return h.prefix.Handler(attrs).Handle(ctx, r)
+}
+
+func (h *ErrorAttrsHandler) withGroupOrAttrs(goa GroupOrAttrs, next slog.Handler) *ErrorAttrsHandler {
+	h2 := *h // Copy handler
+	h2.next = next
+	h2.goas = make([]GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	h2.prefix = NewPrefixCache(h.base, h2.goas)
+	return &h2
+}
+
+func (h *ErrorAttrsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Attrs: attrs}, h.next.WithAttrs(attrs))
+}
+
+func (h *ErrorAttrsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Group: name}, h.next.WithGroup(name))
+}
@@ -0,0 +1,93 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestErrorAttrsHandler(t *testing.T) {
+	errNotFound := errors.New("not found")
+	errQuery := slogutils.WrapErr(errNotFound, slog.String("table", "users"))
+	errHandler := slogutils.WrapErr(fmt.Errorf("loading user: %w", errQuery), slog.Int("user_id", 42))
+
+	tests := []struct {
+		name string
+		f    func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "no error",
+			f: func(l *slog.Logger) {
+				l.Info("test", "key", "val")
+			},
+			want: `level=INFO msg=test key=val`,
+		},
+		{
+			name: "error without attributes",
+			f: func(l *slog.Logger) {
+				l.Error("test", slogutils.Err(errNotFound))
+			},
+			want: `level=ERROR msg=test err="not found"`,
+		},
+		{
+			name: "attributes of wrapped errors are added",
+			f: func(l *slog.Logger) {
+				l.Error("test", slogutils.Err(errHandler))
+			},
+			want: `level=ERROR msg=test err="loading user: not found" user_id=42 table=users`,
+		},
+		{
+			name: "attributes of joined errors are added",
+			f: func(l *slog.Logger) {
+				l.Error("test", slogutils.Err(errors.Join(errQuery, slogutils.WrapErr(errNotFound, slog.String("table", "groups")))))
+			},
+			want: `level=ERROR msg=test err="not found\nnot found" table=users table=groups`,
+		},
+		{
+			name: "attributes are added before groups",
+			f: func(l *slog.Logger) {
+				l.With("a", 1).WithGroup("g").Error("test", slogutils.Err(errQuery))
+			},
+			want: `level=ERROR msg=test table=users a=1 g.err="not found"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := slog.New(slogutils.NewErrorAttrsHandler(slog.NewTextHandler(buf, &slog.HandlerOptions{
+				ReplaceAttr: drop(slog.TimeKey),
+			})))
+
+			tt.f(logger)
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWrapErr(t *testing.T) {
+	if slogutils.WrapErr(nil, slog.String("key", "val")) != nil {
+		t.Fatal("expected nil error")
+	}
+
+	errFail := errors.New("fail")
+	err := slogutils.WrapErr(errFail, slog.String("key", "val"))
+	if !errors.Is(err, errFail) || err.Error() != "fail" {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+
+	var attrsErr *slogutils.AttrsError
+	if !errors.As(err, &attrsErr) || len(attrsErr.Attrs()) != 1 {
+		t.Fatal("expected AttrsError with attributes")
+	}
+}
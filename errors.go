@@ -0,0 +1,172 @@
+package slogutils
+
+import (
+	"errors"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const maxStackDepth = 32
+
+// StackError is an error with the stack trace of the place where it was wrapped by ErrWithStack.
+type StackError struct {
+	err   error
+	stack []uintptr
+}
+
+func (e *StackError) Error() string {
+	return e.err.Error()
+}
+
+func (e *StackError) Unwrap() error {
+	return e.err
+}
+
+// StackTrace returns the program counters of the stack trace.
+func (e *StackError) StackTrace() []uintptr {
+	return e.stack
+}
+
+// ErrWithStack returns an error attribute like Err, but captures the stack trace of the caller.
+// Handlers that support stack traces (e.g. the Sentry adapter) can extract it with errors.As and StackError.
+func ErrWithStack(err error) slog.Attr {
+	if err == nil {
+		return Err(nil)
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers and ErrWithStack
+	n := runtime.Callers(2, pcs)
+	return Err(&StackError{err: err, stack: pcs[:n]})
+}
+
+// Errs returns an error attribute for multiple errors. Nil errors are ignored.
+// The errors are joined with errors.Join and logged as a structured value (see ErrorValue), e.g. with the
+// sub-attributes err.0 and err.1 instead of a single string with all messages.
+func Errs(errs ...error) slog.Attr {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return Err(nil)
+	case 1:
+		return slog.Attr{Key: ErrorKey, Value: slog.AnyValue(structuredError{nonNil[0]})}
+	default:
+		return slog.Attr{Key: ErrorKey, Value: slog.AnyValue(structuredError{errors.Join(nonNil...)})}
+	}
+}
+
+// structuredError is an error that is logged as the structured value of its chain.
+type structuredError struct {
+	error
+}
+
+func (e structuredError) Unwrap() error {
+	return e.error
+}
+
+func (e structuredError) LogValue() slog.Value {
+	return ErrorValue(e.error)
+}
+
+// ErrorValue returns the value of an error with joined errors (see errors.Join) in its chain as a group.
+// Joined errors are unwrapped to sub-attributes by their index ("0", "1", ...). An error wrapping joined errors
+// results in a group with its own message ("msg") and the joined errors ("cause").
+// Other errors result in a string value of the error message.
+func ErrorValue(err error) slog.Value {
+	switch e := err.(type) {
+	case nil:
+		return slog.AnyValue(nil)
+	case interface{ Unwrap() []error }:
+		errs := e.Unwrap()
+		if len(errs) == 1 {
+			return ErrorValue(errs[0])
+		}
+		attrs := make([]slog.Attr, 0, len(errs))
+		for i, err := range errs {
+			attrs = append(attrs, slog.Attr{Key: strconv.Itoa(i), Value: ErrorValue(err)})
+		}
+		return slog.GroupValue(attrs...)
+	case interface{ Unwrap() error }:
+		cause := e.Unwrap()
+		causeValue := ErrorValue(cause)
+		if causeValue.Kind() != slog.KindGroup {
+			return slog.StringValue(err.Error())
+		}
+		// The message of the cause is usually included at the end (e.g. with fmt.Errorf and %w)
+		msg := strings.TrimSuffix(strings.TrimSuffix(err.Error(), cause.Error()), ": ")
+		if msg == "" {
+			return causeValue
+		}
+		return slog.GroupValue(slog.String("msg", msg), slog.Attr{Key: "cause", Value: causeValue})
+	default:
+		return slog.StringValue(err.Error())
+	}
+}
+
+// errorChain returns the structured value of an error value (see ErrorValue) if it has joined errors in its chain.
+func errorChain(v slog.Value) (slog.Value, bool) {
+	if v.Kind() != slog.KindAny && v.Kind() != slog.KindLogValuer {
+		return slog.Value{}, false
+	}
+	err, ok := v.Any().(error)
+	if !ok {
+		return slog.Value{}, false
+	}
+	chain := ErrorValue(err)
+	return chain, chain.Kind() == slog.KindGroup
+}
+
+// AttrsError is an error carrying attributes with structured context of the place where it was wrapped by WrapErr.
+type AttrsError struct {
+	err   error
+	attrs []slog.Attr
+}
+
+func (e *AttrsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *AttrsError) Unwrap() error {
+	return e.err
+}
+
+// Attrs returns the attributes carried by the error.
+func (e *AttrsError) Attrs() []slog.Attr {
+	return e.attrs
+}
+
+// WrapErr returns an error carrying the attributes as structured context without changing the error message.
+// An ErrorAttrsHandler adds the attributes of a logged error as top-level attributes. WrapErr returns nil if err is nil.
+func WrapErr(err error, attrs ...slog.Attr) error {
+	if err == nil {
+		return nil
+	}
+	return &AttrsError{err: err, attrs: attrs}
+}
+
+// ErrorAttrs returns the attributes of all errors in the chain of err that were wrapped by WrapErr.
+// Attributes of outer errors come first.
+func ErrorAttrs(err error) []slog.Attr {
+	var attrs []slog.Attr
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *AttrsError:
+		attrs = append(attrs, e.attrs...)
+		attrs = append(attrs, ErrorAttrs(e.err)...)
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			attrs = append(attrs, ErrorAttrs(err)...)
+		}
+	case interface{ Unwrap() error }:
+		attrs = ErrorAttrs(e.Unwrap())
+	}
+	return attrs
+}
@@ -0,0 +1,76 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestErrWithStack(t *testing.T) {
+	errFail := errors.New("fail")
+	attr := slogutils.ErrWithStack(errFail)
+
+	err, ok := attr.Value.Any().(error)
+	if !ok {
+		t.Fatalf("expected error value, got %T", attr.Value.Any())
+	}
+	if !errors.Is(err, errFail) || err.Error() != "fail" {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+
+	var stackErr *slogutils.StackError
+	if !errors.As(err, &stackErr) {
+		t.Fatal("expected StackError")
+	}
+	frame, _ := runtime.CallersFrames(stackErr.StackTrace()).Next()
+	if !strings.HasSuffix(frame.Function, "TestErrWithStack") {
+		t.Fatalf("expected stack to start at caller, got %s", frame.Function)
+	}
+}
+
+func TestErrs(t *testing.T) {
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want string
+	}{
+		{
+			name: "no errors",
+			attr: slogutils.Errs(nil, nil),
+			want: `{"msg":"test","err":null}`,
+		},
+		{
+			name: "single error",
+			attr: slogutils.Errs(nil, errors.New("fail")),
+			want: `{"msg":"test","err":"fail"}`,
+		},
+		{
+			name: "multiple errors",
+			attr: slogutils.Errs(errors.New("fail"), errors.New("other")),
+			want: `{"msg":"test","err":{"0":"fail","1":"other"}}`,
+		},
+		{
+			name: "wrapped joined errors",
+			attr: slogutils.Errs(fmt.Errorf("closing: %w", errors.Join(errors.New("fail"), errors.New("other")))),
+			want: `{"msg":"test","err":{"msg":"closing","cause":{"0":"fail","1":"other"}}}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: drop(slog.TimeKey, slog.LevelKey)}))
+			logger.Info("test", test.attr)
+
+			got := strings.TrimSpace(buf.String())
+			if test.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", test.want, got)
+			}
+		})
+	}
+}
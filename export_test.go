@@ -0,0 +1,23 @@
+package slogutils
+
+import "time"
+
+// SetHeartbeatClock replaces the function returning the current time and the ticks of heartbeats.
+func SetHeartbeatClock(now func() time.Time, ticks <-chan time.Time) (restore func()) {
+	prevNow, prevTicker := heartbeatNow, heartbeatTicker
+	heartbeatNow = now
+	heartbeatTicker = func(time.Duration) (<-chan time.Time, func()) {
+		return ticks, func() {}
+	}
+	return func() {
+		heartbeatNow, heartbeatTicker = prevNow, prevTicker
+	}
+}
+
+// OnceKeys returns the number of keys remembered by Once and OnceEvery.
+func OnceKeys() int {
+	defaultOnceRegistry.mu.Lock()
+	defer defaultOnceRegistry.mu.Unlock()
+
+	return len(defaultOnceRegistry.seen)
+}
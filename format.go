@@ -0,0 +1,140 @@
+package slogutils
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ValueFormatter formats a value for humans. It returns false if it does not handle the value.
+// Value formatters can be used by CLIHandler (see CLIHandlerOptions.Formatters) or any other handler with
+// ReplaceValues.
+type ValueFormatter func(v slog.Value) (string, bool)
+
+// ByteSize is an integer that is tagged as a size in bytes, so it can be formatted by FormatByteSize.
+// Without a formatter it is rendered as a plain integer.
+type ByteSize int64
+
+// Bytes returns an attribute for a size in bytes that is formatted by FormatByteSize (e.g. as "12.4 MiB").
+func Bytes(key string, n int64) slog.Attr {
+	return slog.Any(key, ByteSize(n))
+}
+
+// FormatDuration formats durations with a precision suitable for humans (e.g. "1.2s", "497ms" or "2m30s").
+func FormatDuration(v slog.Value) (string, bool) {
+	if v.Kind() != slog.KindDuration {
+		return "", false
+	}
+	return formatDuration(v.Duration()), true
+}
+
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + formatDuration(-d)
+	}
+	switch {
+	case d < time.Microsecond:
+		return strconv.FormatInt(int64(d), 10) + "ns"
+	case d < time.Millisecond:
+		return formatDecimal(float64(d)/float64(time.Microsecond)) + "µs"
+	case d < time.Second:
+		return formatDecimal(float64(d)/float64(time.Millisecond)) + "ms"
+	case d < time.Minute:
+		return formatDecimal(float64(d)/float64(time.Second)) + "s"
+	case d < time.Hour:
+		d = d.Round(time.Second)
+		return strconv.Itoa(int(d/time.Minute)) + "m" + strconv.Itoa(int(d%time.Minute/time.Second)) + "s"
+	default:
+		d = d.Round(time.Minute)
+		return strconv.Itoa(int(d/time.Hour)) + "h" + strconv.Itoa(int(d%time.Hour/time.Minute)) + "m"
+	}
+}
+
+var byteSizeUnits = []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// FormatByteSize formats values of type ByteSize with binary units (e.g. "512 B" or "12.4 MiB").
+func FormatByteSize(v slog.Value) (string, bool) {
+	if v.Kind() != slog.KindAny {
+		return "", false
+	}
+	n, ok := v.Any().(ByteSize)
+	if !ok {
+		return "", false
+	}
+	return formatByteSize(n), true
+}
+
+func formatByteSize(n ByteSize) string {
+	if n < 0 {
+		return "-" + formatByteSize(-n)
+	}
+	if n < 1024 {
+		return strconv.FormatInt(int64(n), 10) + " B"
+	}
+	size := float64(n) / 1024
+	unit := 0
+	for size >= 1024 && unit < len(byteSizeUnits)-1 {
+		size /= 1024
+		unit++
+	}
+	return formatDecimal(size) + " " + byteSizeUnits[unit]
+}
+
+// RelativeTime returns a formatter that formats times relative to now (e.g. "3m ago" or "in 2h").
+// If now is nil, time.Now is used.
+func RelativeTime(now func() time.Time) ValueFormatter {
+	if now == nil {
+		now = time.Now
+	}
+	return func(v slog.Value) (string, bool) {
+		if v.Kind() != slog.KindTime {
+			return "", false
+		}
+		d := now().Sub(v.Time())
+		suffix, prefix := " ago", ""
+		if d < 0 {
+			d = -d
+			suffix, prefix = "", "in "
+		}
+		switch {
+		case d < time.Second:
+			return "just now", true
+		case d < time.Minute:
+			return prefix + strconv.Itoa(int(d/time.Second)) + "s" + suffix, true
+		case d < time.Hour:
+			return prefix + strconv.Itoa(int(d/time.Minute)) + "m" + suffix, true
+		case d < 24*time.Hour:
+			return prefix + strconv.Itoa(int(d/time.Hour)) + "h" + suffix, true
+		default:
+			return prefix + strconv.Itoa(int(d/(24*time.Hour))) + "d" + suffix, true
+		}
+	}
+}
+
+// formatDecimal formats f with at most one decimal place.
+func formatDecimal(f float64) string {
+	return strconv.FormatFloat(math.Round(f*10)/10, 'f', -1, 64)
+}
+
+// formatValue formats v with the first formatter that handles it.
+func formatValue(formatters []ValueFormatter, v slog.Value) (string, bool) {
+	for _, f := range formatters {
+		if s, ok := f(v); ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// ReplaceValues returns a function for slog.HandlerOptions.ReplaceAttr that replaces values handled by one of the
+// formatters with the formatted string. Groups are not descended into, since ReplaceAttr is called for the
+// attributes of groups by the handler.
+func ReplaceValues(formatters ...ValueFormatter) func(groups []string, a slog.Attr) slog.Attr {
+	return func(_ []string, a slog.Attr) slog.Attr {
+		if s, ok := formatValue(formatters, a.Value.Resolve()); ok {
+			a.Value = slog.StringValue(s)
+		}
+		return a
+	}
+}
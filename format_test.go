@@ -0,0 +1,100 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 850 * time.Nanosecond, want: "850ns"},
+		{d: 12340 * time.Nanosecond, want: "12.3µs"},
+		{d: 497 * time.Millisecond, want: "497ms"},
+		{d: 1234 * time.Millisecond, want: "1.2s"},
+		{d: 150500 * time.Millisecond, want: "2m31s"},
+		{d: 65 * time.Minute, want: "1h5m"},
+		{d: -2 * time.Second, want: "-2s"},
+	}
+	for _, tt := range tests {
+		got, ok := slogutils.FormatDuration(slog.DurationValue(tt.d))
+		if !ok || got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, %v; want %q", tt.d, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := slogutils.FormatDuration(slog.IntValue(1)); ok {
+		t.Errorf("expected int value not to be handled")
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{n: 512, want: "512 B"},
+		{n: 1024, want: "1 KiB"},
+		{n: 13002342, want: "12.4 MiB"},
+		{n: 5 << 30, want: "5 GiB"},
+	}
+	for _, tt := range tests {
+		got, ok := slogutils.FormatByteSize(slogutils.Bytes("size", tt.n).Value)
+		if !ok || got != tt.want {
+			t.Errorf("FormatByteSize(%d) = %q, %v; want %q", tt.n, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := slogutils.FormatByteSize(slog.Int64Value(512)); ok {
+		t.Errorf("expected untagged int value not to be handled")
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	format := slogutils.RelativeTime(func() time.Time { return now })
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{t: now, want: "just now"},
+		{t: now.Add(-30 * time.Second), want: "30s ago"},
+		{t: now.Add(-3 * time.Minute), want: "3m ago"},
+		{t: now.Add(2 * time.Hour), want: "in 2h"},
+		{t: now.Add(-50 * time.Hour), want: "2d ago"},
+	}
+	for _, tt := range tests {
+		got, ok := format(slog.TimeValue(tt.t))
+		if !ok || got != tt.want {
+			t.Errorf("RelativeTime(%v) = %q, %v; want %q", tt.t, got, ok, tt.want)
+		}
+	}
+}
+
+func TestReplaceValues(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return slogutils.ReplaceValues(slogutils.FormatDuration, slogutils.FormatByteSize)(groups, a)
+		},
+	}))
+
+	logger.Info("Done", "duration", 1234*time.Millisecond, slog.Group("file", slogutils.Bytes("size", 2048)), "count", 3)
+
+	want := `level=INFO msg=Done duration=1.2s file.size="2 KiB" count=3`
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
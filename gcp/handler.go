@@ -0,0 +1,235 @@
+// Package gcp provides a handler writing structured JSON logs as expected by Google Cloud Logging
+// (e.g. on Cloud Run, Cloud Functions or GKE).
+package gcp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Keys of special fields of Cloud Logging.
+const (
+	SeverityKey       = "severity"
+	MessageKey        = "message"
+	SourceLocationKey = "logging.googleapis.com/sourceLocation"
+	TraceKey          = "logging.googleapis.com/trace"
+	SpanIDKey         = "logging.googleapis.com/spanId"
+	TraceSampledKey   = "logging.googleapis.com/trace_sampled"
+)
+
+// Options are options for a Handler.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Level reports the minimum record level that will be logged.
+	// If Level is nil, the handler assumes slog.LevelInfo.
+	Level slog.Leveler
+
+	// AddSource adds the source location of records as sourceLocation.
+	AddSource bool
+
+	// ReplaceAttr is called to rewrite attributes before they are mapped to Cloud Logging fields.
+	// See slog.HandlerOptions.ReplaceAttr.
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+
+	// ProjectID is used to qualify trace IDs ("projects/PROJECT_ID/traces/TRACE_ID"),
+	// defaults to the environment variable GOOGLE_CLOUD_PROJECT.
+	ProjectID string
+
+	// TraceFromContext returns the trace of a record from the context.
+	// If TraceFromContext is nil, the trace stored by ContextWithTraceHeader is used.
+	TraceFromContext func(ctx context.Context) (trace Trace, ok bool)
+}
+
+// Trace identifies the trace and span of a record.
+type Trace struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+}
+
+type traceContextKey struct{}
+
+// ContextWithTrace returns a new context with the trace, which is added to records logged with the context.
+func ContextWithTrace(ctx context.Context, trace Trace) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, trace)
+}
+
+// ContextWithTraceHeader parses the X-Cloud-Trace-Context header ("TRACE_ID/SPAN_ID;o=OPTIONS") of an incoming
+// request and returns a new context with the trace. The context is returned unchanged if the header is invalid.
+func ContextWithTraceHeader(ctx context.Context, header string) context.Context {
+	traceID, rest, _ := strings.Cut(header, "/")
+	if traceID == "" {
+		return ctx
+	}
+	spanID, options, _ := strings.Cut(rest, ";")
+	return ContextWithTrace(ctx, Trace{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: options == "o=1",
+	})
+}
+
+// TraceFromContext returns the trace stored by ContextWithTrace or ContextWithTraceHeader.
+func TraceFromContext(ctx context.Context) (Trace, bool) {
+	trace, ok := ctx.Value(traceContextKey{}).(Trace)
+	return trace, ok
+}
+
+// Handler writes records as JSON with the special fields of Cloud Logging: severity, message, sourceLocation
+// and trace. A group named "httpRequest" (see HTTPRequest) is recognized by Cloud Logging as request metadata.
+type Handler struct {
+	// base is the JSON handler without groups and attributes of this handler
+	base slog.Handler
+	// next is the JSON handler with groups and attributes of this handler applied
+	next slog.Handler
+	goas []slogutils.GroupOrAttrs
+	// prefix caches the handler with trace fields before goas
+	prefix *slogutils.PrefixCache
+
+	projectID        string
+	traceFromContext func(ctx context.Context) (Trace, bool)
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler creates a handler writing JSON records to w.
+func NewHandler(w io.Writer, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	projectID := opts.ProjectID
+	if projectID == "" {
+		projectID = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	traceFromContext := opts.TraceFromContext
+	if traceFromContext == nil {
+		traceFromContext = TraceFromContext
+	}
+
+	replaceAttr := opts.ReplaceAttr
+	jh := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:     opts.Level,
+		AddSource: opts.AddSource,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if replaceAttr != nil {
+				a = replaceAttr(groups, a)
+				if a.Equal(slog.Attr{}) {
+					return a
+				}
+			}
+			return mapAttr(groups, a)
+		},
+	})
+
+	return &Handler{
+		base:             jh,
+		next:             jh,
+		projectID:        projectID,
+		traceFromContext: traceFromContext,
+	}
+}
+
+func mapAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.LevelKey:
+		a.Key = SeverityKey
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(Severity(level))
+		}
+	case slog.MessageKey:
+		a.Key = MessageKey
+	case slog.SourceKey:
+		if src, ok := a.Value.Any().(*slog.Source); ok {
+			return slog.Group(SourceLocationKey,
+				slog.String("file", src.File),
+				// The line is an int64 in the API, which is encoded as a string in JSON
+				slog.String("line", strconv.Itoa(src.Line)),
+				slog.String("function", src.Function),
+			)
+		}
+	}
+	return a
+}
+
+// Severity maps a level to a Cloud Logging severity.
+// Levels below info (like slogutils.LevelTrace) are mapped to DEBUG, levels above error to CRITICAL.
+func Severity(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError+4:
+		return "CRITICAL"
+	case level >= slog.LevelError:
+		return "ERROR"
+	case level >= slog.LevelWarn:
+		return "WARNING"
+	case level >= slog.LevelInfo:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	r, _ = slogutils.ExtractControls(r)
+
+	trace, ok := h.traceFromContext(ctx)
+	if !ok || trace.TraceID == "" {
+		return h.next.Handle(ctx, r)
+	}
+
+	traceAttrs := make([]slog.Attr, 0, 3)
+	traceID := trace.TraceID
+	if h.projectID != "" {
+		traceID = "projects/" + h.projectID + "/traces/" + traceID
+	}
+	traceAttrs = append(traceAttrs, slog.String(TraceKey, traceID))
+	if trace.SpanID != "" {
+		traceAttrs = append(traceAttrs, slog.String(SpanIDKey, trace.SpanID))
+	}
+	traceAttrs = append(traceAttrs, slog.Bool(TraceSampledKey, trace.Sampled))
+
+	if len(h.goas) == 0 {
+		return h.next.Handle(ctx, slogutils.CloneRecordWithAttrs(r, traceAttrs...))
+	}
+
+	return h.prefix.Handler(traceAttrs).Handle(ctx, r)
+}
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs, next slog.Handler) *Handler {
+	h2 := *h // Copy handler
+	h2.next = next
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	h2.prefix = slogutils.NewPrefixCache(h.base, h2.goas)
+	return &h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs, _ = slogutils.StripControlAttrs(attrs)
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs}, h.next.WithAttrs(attrs))
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name}, h.next.WithGroup(name))
+}
@@ -0,0 +1,76 @@
+package gcp_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/gcp"
+)
+
+func TestHandler(t *testing.T) {
+	traceCtx := gcp.ContextWithTraceHeader(context.Background(), "105445aa7843bc8bf206b12000100000/1;o=1")
+
+	tests := []struct {
+		name string
+		f    func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "severity and message",
+			f: func(l *slog.Logger) {
+				l.Warn("test", "key", "val")
+			},
+			want: `{"severity":"WARNING","message":"test","key":"val"}`,
+		},
+		{
+			name: "trace level is debug",
+			f: func(l *slog.Logger) {
+				l.Log(context.Background(), slogutils.LevelTrace, "test")
+			},
+			want: `{"severity":"DEBUG","message":"test"}`,
+		},
+		{
+			name: "trace from context",
+			f: func(l *slog.Logger) {
+				l.With("a", 1).WithGroup("g").InfoContext(traceCtx, "test", "key", "val")
+			},
+			want: `{"severity":"INFO","message":"test","logging.googleapis.com/trace":"projects/my-project/traces/105445aa7843bc8bf206b12000100000","logging.googleapis.com/spanId":"1","logging.googleapis.com/trace_sampled":true,"a":1,"g":{"key":"val"}}`,
+		},
+		{
+			name: "http request",
+			f: func(l *slog.Logger) {
+				r := httptest.NewRequest("GET", "/users", nil)
+				l.Info("Request", gcp.HTTPRequest(r, 200, 512, 1500*time.Millisecond))
+			},
+			want: `{"severity":"INFO","message":"Request","httpRequest":{"requestMethod":"GET","requestUrl":"/users","status":200,"responseSize":"512","userAgent":"","remoteIp":"192.0.2.1:1234","protocol":"HTTP/1.1","latency":"1.5s"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := slog.New(gcp.NewHandler(buf, &gcp.Options{
+				Level:     slogutils.LevelTrace,
+				ProjectID: "my-project",
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			tt.f(logger)
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package gcp
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPRequestKey is the key of the group with request metadata recognized by Cloud Logging.
+const HTTPRequestKey = "httpRequest"
+
+// HTTPRequest returns a group attribute with metadata of a completed request in the format of the HttpRequest
+// type of Cloud Logging. It must be added at the top level of a record to be recognized.
+func HTTPRequest(r *http.Request, status int, responseSize int64, latency time.Duration) slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("requestMethod", r.Method),
+		slog.String("requestUrl", r.URL.String()),
+		slog.Int("status", status),
+		slog.String("responseSize", strconv.FormatInt(responseSize, 10)),
+		slog.String("userAgent", r.UserAgent()),
+		slog.String("remoteIp", r.RemoteAddr),
+		slog.String("protocol", r.Proto),
+		// Latency is a duration in seconds with up to nine fractional digits and a suffix "s"
+		slog.String("latency", strconv.FormatFloat(latency.Seconds(), 'f', -1, 64)+"s"),
+	}
+	if referer := r.Referer(); referer != "" {
+		attrs = append(attrs, slog.String("referer", referer))
+	}
+	if r.ContentLength > 0 {
+		attrs = append(attrs, slog.String("requestSize", strconv.FormatInt(r.ContentLength, 10)))
+	}
+	return slog.Attr{Key: HTTPRequestKey, Value: slog.GroupValue(attrs...)}
+}
@@ -0,0 +1,39 @@
+package slogutils
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+)
+
+// GoldenTime is the time that time attributes are replaced with by GoldenCLIHandlerOptions.
+var GoldenTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// GoldenCLIHandlerOptions returns options for deterministic output of a CLIHandler, e.g. for golden-file tests of
+// CLI output: colors are disabled, attributes are sorted by key, time attributes are replaced with GoldenTime,
+// durations with zero and paddings are fixed. All levels down to LevelTrace are logged.
+func GoldenCLIHandlerOptions() *CLIHandlerOptions {
+	return &CLIHandlerOptions{
+		Level:          LevelTrace,
+		MessagePadding: cliDefaultMessagePadding,
+		NoColor:        true,
+		SortAttrs:      true,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			switch a.Value.Kind() {
+			case slog.KindTime:
+				a.Value = slog.TimeValue(GoldenTime)
+			case slog.KindDuration:
+				a.Value = slog.DurationValue(0)
+			}
+			return a
+		},
+	}
+}
+
+// ansiPattern matches ANSI escape sequences (CSI sequences like colors and cursor movements).
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// StripANSI removes ANSI escape sequences (e.g. colors) from s.
+func StripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
@@ -0,0 +1,47 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestGoldenCLIHandlerOptions(t *testing.T) {
+	// Force colors to check that they are disabled by the options
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	buf := new(bytes.Buffer)
+	logger := slog.New(slogutils.NewCLIHandler(buf, slogutils.GoldenCLIHandlerOptions()))
+
+	logger.With("z", 1).Info("Done", "duration", 1234*time.Millisecond, "at", time.Now(), slog.Group("a", "b", true))
+	logger.Log(context.Background(), slogutils.LevelTrace, "Details")
+
+	want := strings.Join([]string{
+		`  • Done                      a.b=true at="2000-01-01 00:00:00 +0000 UTC" duration=0s z=1`,
+		`  - Details                  `,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	colored := color.New(color.FgRed, color.Bold).Sprint("fail") + " \x1b[2Kdone"
+	if got := slogutils.StripANSI(colored); got != "fail done" {
+		t.Fatalf("expected stripped string, got %q", got)
+	}
+}
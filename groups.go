@@ -0,0 +1,117 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+)
+
+// GroupKeys is a strategy for rendering attributes in groups, since log pipelines differ in the key shapes they
+// accept.
+type GroupKeys int
+
+const (
+	// GroupKeysDot flattens groups to keys joined by a dot ("group.key"), the default of handlers writing flat keys.
+	GroupKeysDot GroupKeys = iota
+	// GroupKeysUnderscore flattens groups to keys joined by an underscore ("group_key").
+	GroupKeysUnderscore
+	// GroupKeysNested preserves groups (e.g. as nested objects in JSON). Handlers writing flat keys use a dot.
+	GroupKeysNested
+)
+
+// Separator returns the separator of group keys for handlers writing flat keys.
+func (g GroupKeys) Separator() string {
+	if g == GroupKeysUnderscore {
+		return "_"
+	}
+	return "."
+}
+
+// FlattenAttrs replaces groups in attrs by their attributes with keys qualified by the group keys according to the
+// strategy. Values are resolved and groups without a key are inlined. Attrs are returned as is for GroupKeysNested.
+func FlattenAttrs(attrs []slog.Attr, strategy GroupKeys) []slog.Attr {
+	if strategy == GroupKeysNested {
+		return attrs
+	}
+
+	result := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		result = appendFlattened(result, ResolveAttr(a, 0), "", strategy.Separator())
+	}
+	return result
+}
+
+func appendFlattened(attrs []slog.Attr, a slog.Attr, prefix, sep string) []slog.Attr {
+	if a.Value.Kind() != slog.KindGroup {
+		if a.Equal(slog.Attr{}) {
+			return attrs
+		}
+		a.Key = prefix + a.Key
+		return append(attrs, a)
+	}
+
+	if a.Key != "" {
+		prefix += a.Key + sep
+	}
+	for _, ga := range a.Value.Group() {
+		attrs = appendFlattened(attrs, ga, prefix, sep)
+	}
+	return attrs
+}
+
+// GroupKeysHandler applies a strategy for group keys (see GroupKeys) to attributes of the handler and the record.
+// Attributes and groups of the handler are collected and passed to the wrapped handler as part of the record, so
+// flattened keys do not depend on how the wrapped handler renders groups.
+type GroupKeysHandler struct {
+	next     slog.Handler
+	strategy GroupKeys
+	goas     []GroupOrAttrs
+}
+
+var (
+	_ slog.Handler = (*GroupKeysHandler)(nil)
+	_ Wrapper      = (*GroupKeysHandler)(nil)
+)
+
+// NewGroupKeysHandler creates a new GroupKeysHandler wrapping the given handler.
+func NewGroupKeysHandler(next slog.Handler, strategy GroupKeys) *GroupKeysHandler {
+	return &GroupKeysHandler{next: next, strategy: strategy}
+}
+
+func (h *GroupKeysHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *GroupKeysHandler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *GroupKeysHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := NestAttrs(h.goas, RecordAttrs(r))
+
+	r2 := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r2.AddAttrs(FlattenAttrs(attrs, h.strategy)...)
+	return h.next.Handle(ctx, r2)
+}
+
+func (h *GroupKeysHandler) withGroupOrAttrs(goa GroupOrAttrs) *GroupKeysHandler {
+	h2 := *h // Copy handler
+	h2.goas = make([]GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *GroupKeysHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Attrs: attrs})
+}
+
+func (h *GroupKeysHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Group: name})
+}
@@ -0,0 +1,49 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestGroupKeysHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy slogutils.GroupKeys
+		want     string
+	}{
+		{
+			name:     "dot joins keys",
+			strategy: slogutils.GroupKeysDot,
+			want:     `{"level":"INFO","msg":"test","app":"api","req.id":1,"req.user.name":"alice","req.size":2}`,
+		},
+		{
+			name:     "underscore joins keys",
+			strategy: slogutils.GroupKeysUnderscore,
+			want:     `{"level":"INFO","msg":"test","app":"api","req_id":1,"req_user_name":"alice","req_size":2}`,
+		},
+		{
+			name:     "nested preserves groups",
+			strategy: slogutils.GroupKeysNested,
+			want:     `{"level":"INFO","msg":"test","app":"api","req":{"id":1,"user":{"name":"alice"},"size":2}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := slog.New(slogutils.NewGroupKeysHandler(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+				ReplaceAttr: drop(slog.TimeKey),
+			}), tt.strategy))
+
+			logger.With("app", "api").WithGroup("req").With("id", 1).Info("test", slog.Group("user", "name", "alice"), slog.Group("", "size", 2))
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package slogutils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// HashKey is the default key of the content hash attribute added by a HashHandler.
+const HashKey = "hash"
+
+// HashOptions control how the content hash of a record is computed.
+type HashOptions struct {
+	// Key is the key of the hash attribute, defaults to HashKey.
+	Key string
+
+	// IncludeTime adds the record time to the hash.
+	// By default the time is excluded, so a record that is logged again after a retry gets the same hash.
+	IncludeTime bool
+
+	// Ignore are keys of attributes that are excluded from the hash (e.g. a retry attempt).
+	// Keys of attributes in groups are qualified by the group names separated by dots ("request.attempt").
+	Ignore []string
+}
+
+func (o *HashOptions) key() string {
+	if o == nil || o.Key == "" {
+		return HashKey
+	}
+	return o.Key
+}
+
+func (o *HashOptions) ignored(key string) bool {
+	if o == nil {
+		return false
+	}
+	for _, k := range o.Ignore {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordHash computes a stable content hash of a record and the groups and attributes of the handler that received it.
+// The hash covers level, message and all resolved attributes (in order), so downstream systems (queues, webhooks) can
+// deduplicate records that are emitted again after retries or buffer re-emission.
+// The hash is returned as 32 hex characters.
+func RecordHash(r slog.Record, goas []GroupOrAttrs, opts *HashOptions) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(r.Level.String()))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(r.Message))
+	_, _ = h.Write([]byte{0})
+	if opts != nil && opts.IncludeTime {
+		_, _ = h.Write([]byte(r.Time.UTC().Format(time.RFC3339Nano)))
+	}
+	_, _ = h.Write([]byte{0})
+
+	var groups []string
+	for _, goa := range goas {
+		if goa.Group != "" {
+			groups = append(groups, goa.Group)
+			continue
+		}
+		for _, a := range goa.Attrs {
+			hashAttr(h, groups, a, opts)
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		hashAttr(h, groups, a, opts)
+		return true
+	})
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func hashAttr(h hash.Hash, groups []string, a slog.Attr, opts *HashOptions) {
+	a = ResolveAttr(a, 0)
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if opts.key() == a.Key && len(groups) == 0 {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		groupAttrs := a.Value.Group()
+		if a.Key == "" {
+			for _, ga := range groupAttrs {
+				hashAttr(h, groups, ga, opts)
+			}
+			return
+		}
+		// Copy groups to not share the backing array with other attributes
+		subGroups := append(groups[:len(groups):len(groups)], a.Key)
+		for _, ga := range groupAttrs {
+			hashAttr(h, subGroups, ga, opts)
+		}
+		return
+	}
+
+	key := a.Key
+	if len(groups) > 0 {
+		key = strings.Join(groups, ".") + "." + a.Key
+	}
+	if opts.ignored(key) {
+		return
+	}
+
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte{'='})
+	_, _ = h.Write([]byte(a.Value.Kind().String()))
+	_, _ = h.Write([]byte{':'})
+	if a.Value.Kind() == slog.KindTime {
+		_, _ = h.Write([]byte(a.Value.Time().UTC().Format(time.RFC3339Nano)))
+	} else {
+		_, _ = h.Write([]byte(a.Value.String()))
+	}
+	_, _ = h.Write([]byte{0})
+}
+
+// HashHandler adds a content hash attribute (see RecordHash) to every record before delegating to another handler.
+// The attribute is added at the top level, before attributes and groups of the handler.
+type HashHandler struct {
+	// base is the wrapped handler without groups and attributes of this handler
+	base slog.Handler
+	// next is the wrapped handler with groups and attributes of this handler applied
+	next slog.Handler
+	goas []GroupOrAttrs
+	opts *HashOptions
+	// prefix caches the handler with the hash attribute before goas
+	prefix *PrefixCache
+}
+
+var (
+	_ slog.Handler = (*HashHandler)(nil)
+	_ AttrsEnabler = (*HashHandler)(nil)
+	_ Wrapper      = (*HashHandler)(nil)
+)
+
+// NewHashHandler creates a new HashHandler wrapping the given handler. Options can be nil.
+func NewHashHandler(next slog.Handler, opts *HashOptions) *HashHandler {
+	return &HashHandler{base: next, next: next, opts: opts}
+}
+
+func (h *HashHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *HashHandler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *HashHandler) EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+	return EnabledForAttrs(ctx, h.next, level, attrs)
+}
+
+func (h *HashHandler) Handle(ctx context.Context, r slog.Record) error {
+	hashAttr := slog.String(h.opts.key(), RecordHash(r, h.goas, h.opts))
+
+	if len(h.goas) == 0 {
+		return h.next.Handle(ctx, CloneRecordWithAttrs(r, hashAttr))
+	}
+
+	return h.prefix.Handler([]slog.Attr{hashAttr}).Handle(ctx, r)
+}
+
+func (h *HashHandler) withGroupOrAttrs(goa GroupOrAttrs, next slog.Handler) *HashHandler {
+	h2 := *h // Copy handler
+	h2.next = next
+	h2.goas = make([]GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	h2.prefix = NewPrefixCache(h.base, h2.goas)
+	return &h2
+}
+
+func (h *HashHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Attrs: attrs}, h.next.WithAttrs(attrs))
+}
+
+func (h *HashHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Group: name}, h.next.WithGroup(name))
+}
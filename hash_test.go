@@ -0,0 +1,87 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestRecordHash(t *testing.T) {
+	newRecord := func(t time.Time, attrs ...slog.Attr) slog.Record {
+		r := slog.NewRecord(t, slog.LevelInfo, "test", 0)
+		r.AddAttrs(attrs...)
+		return r
+	}
+	t1 := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+
+	base := slogutils.RecordHash(newRecord(t1, slog.String("key", "val")), nil, nil)
+	if len(base) != 32 {
+		t.Fatalf("expected hash of 32 characters, got %q", base)
+	}
+
+	tests := []struct {
+		name  string
+		hash  string
+		equal bool
+	}{
+		{
+			name:  "time is ignored by default",
+			hash:  slogutils.RecordHash(newRecord(t2, slog.String("key", "val")), nil, nil),
+			equal: true,
+		},
+		{
+			name: "time is included",
+			hash: slogutils.RecordHash(newRecord(t2, slog.String("key", "val")), nil, &slogutils.HashOptions{IncludeTime: true}),
+		},
+		{
+			name: "attribute value differs",
+			hash: slogutils.RecordHash(newRecord(t1, slog.String("key", "other")), nil, nil),
+		},
+		{
+			name: "attribute kind differs",
+			hash: slogutils.RecordHash(newRecord(t1, slog.Any("key", []byte("val"))), nil, nil),
+		},
+		{
+			name: "attribute is grouped",
+			hash: slogutils.RecordHash(newRecord(t1, slog.String("key", "val")), []slogutils.GroupOrAttrs{{Group: "g"}}, nil),
+		},
+		{
+			name:  "handler attributes are equal to record attributes",
+			hash:  slogutils.RecordHash(newRecord(t1), []slogutils.GroupOrAttrs{{Attrs: []slog.Attr{slog.String("key", "val")}}}, nil),
+			equal: true,
+		},
+		{
+			name:  "ignored attribute",
+			hash:  slogutils.RecordHash(newRecord(t1, slog.String("key", "val"), slog.Int("attempt", 2)), nil, &slogutils.HashOptions{Ignore: []string{"attempt"}}),
+			equal: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if equal := tt.hash == base; equal != tt.equal {
+				t.Fatalf("expected hash equal %v, got %s and %s", tt.equal, base, tt.hash)
+			}
+		})
+	}
+}
+
+func TestHashHandler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slogutils.NewHashHandler(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: drop(slog.TimeKey),
+	}), nil))
+
+	logger.With("a", 1).WithGroup("g").InfoContext(context.Background(), "test", "key", "val")
+
+	got := strings.TrimRight(buf.String(), "\n")
+	if !strings.HasPrefix(got, `level=INFO msg=test hash=`) || !strings.HasSuffix(got, ` a=1 g.key=val`) {
+		t.Fatalf("unexpected output: %s", got)
+	}
+}
@@ -0,0 +1,94 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	heartbeatDefaultInterval = time.Minute
+	heartbeatDefaultMessage  = "Heartbeat"
+)
+
+// heartbeatNow returns the current time and heartbeatTicker the ticks of a heartbeat and a function to stop them,
+// they are replaced in tests.
+var (
+	heartbeatNow    = time.Now
+	heartbeatTicker = func(d time.Duration) (<-chan time.Time, func()) {
+		t := time.NewTicker(d)
+		return t.C, t.Stop
+	}
+)
+
+// HeartbeatOptions are options for StartHeartbeat.
+// A zero HeartbeatOptions consists entirely of default values.
+type HeartbeatOptions struct {
+	// Interval between heartbeat records, defaults to one minute.
+	Interval time.Duration
+
+	// Level of heartbeat records. If Level is nil, slog.LevelInfo is used.
+	Level slog.Leveler
+
+	// Message of heartbeat records, defaults to "Heartbeat".
+	Message string
+
+	// Attrs is called for every heartbeat to add attributes, e.g. counters of processed items.
+	Attrs func() []slog.Attr
+}
+
+// StartHeartbeat periodically logs a heartbeat record with the uptime and attributes supplied by opts.Attrs
+// with the logger from the context. It stops when the context is done or the returned function is called.
+func StartHeartbeat(ctx context.Context, opts *HeartbeatOptions) (stop func()) {
+	if opts == nil {
+		opts = &HeartbeatOptions{}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = heartbeatDefaultInterval
+	}
+	var level slog.Leveler = slog.LevelInfo
+	if opts.Level != nil {
+		level = opts.Level
+	}
+	msg := opts.Message
+	if msg == "" {
+		msg = heartbeatDefaultMessage
+	}
+
+	logger := FromContext(ctx)
+	start := heartbeatNow()
+	ticks, stopTicker := heartbeatTicker(interval)
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		defer stopTicker()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticks:
+				lvl := level.Level()
+				if !logger.Enabled(ctx, lvl) {
+					continue
+				}
+				attrs := []slog.Attr{slog.Duration("uptime", heartbeatNow().Sub(start).Round(time.Second))}
+				if opts.Attrs != nil {
+					attrs = append(attrs, opts.Attrs()...)
+				}
+				logger.LogAttrs(ctx, lvl, msg, attrs...)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
@@ -0,0 +1,91 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestStartHeartbeat(t *testing.T) {
+	buf := &syncBuffer{}
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level:       slog.LevelDebug,
+		ReplaceAttr: drop(slog.TimeKey),
+	}))
+	ctx := slogutils.WithLogger(context.Background(), logger)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var nowMu sync.Mutex
+	ticks := make(chan time.Time)
+	restore := slogutils.SetHeartbeatClock(func() time.Time {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+		return now
+	}, ticks)
+	defer restore()
+	advance := func(d time.Duration) {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+		now = now.Add(d)
+	}
+
+	var processed atomic.Int64
+	stop := slogutils.StartHeartbeat(ctx, &slogutils.HeartbeatOptions{
+		Level: slog.LevelDebug,
+		Attrs: func() []slog.Attr {
+			return []slog.Attr{slog.Int64("processed", processed.Add(1))}
+		},
+	})
+
+	advance(time.Minute)
+	ticks <- time.Time{}
+	advance(time.Minute)
+	ticks <- time.Time{}
+	stop()
+
+	want := strings.Join([]string{
+		`level=DEBUG msg=Heartbeat uptime=1m0s processed=1`,
+		`level=DEBUG msg=Heartbeat uptime=2m0s processed=2`,
+	}, "\n")
+	if got := strings.TrimRight(buf.String(), "\n"); want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+
+	// No more heartbeats after stop
+	select {
+	case ticks <- time.Time{}:
+		t.Fatal("expected no heartbeats after stop")
+	default:
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *syncBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Len()
+}
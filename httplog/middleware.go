@@ -0,0 +1,182 @@
+package httplog
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+// MiddlewareOptions are options for a Middleware.
+// A zero MiddlewareOptions consists entirely of default values.
+type MiddlewareOptions struct {
+	// Level is the level for successful requests, defaults to info.
+	Level slog.Leveler
+	// ErrorLevel is the level for server errors (status 5xx), defaults to error.
+	ErrorLevel slog.Leveler
+	// SlowThreshold logs requests taking at least the duration with SlowLevel (defaults to warn).
+	SlowThreshold time.Duration
+	SlowLevel     slog.Leveler
+
+	// Attrs returns attributes for the logger of the request context (e.g. a request ID), which is used by handlers
+	// (see slogutils.FromContext) and for the request record.
+	Attrs func(r *http.Request) []slog.Attr
+
+	// Route returns the route template of a request (e.g. "/users/{id}"), it is called after the request was handled.
+	// Framework specific middleware (e.g. of adapter/chi) sets this to log the matched route.
+	Route func(r *http.Request) string
+}
+
+// Middleware logs requests to an http.Handler with the logger from the request context (see slogutils.FromContext).
+type Middleware struct {
+	level         slog.Leveler
+	errorLevel    slog.Leveler
+	slowThreshold time.Duration
+	slowLevel     slog.Leveler
+	attrs         func(r *http.Request) []slog.Attr
+	route         func(r *http.Request) string
+}
+
+// NewMiddleware creates a new logging middleware.
+func NewMiddleware(opts *MiddlewareOptions) *Middleware {
+	if opts == nil {
+		opts = &MiddlewareOptions{}
+	}
+	m := &Middleware{
+		level:         opts.Level,
+		errorLevel:    opts.ErrorLevel,
+		slowThreshold: opts.SlowThreshold,
+		slowLevel:     opts.SlowLevel,
+		attrs:         opts.Attrs,
+		route:         opts.Route,
+	}
+	if m.level == nil {
+		m.level = slog.LevelInfo
+	}
+	if m.errorLevel == nil {
+		m.errorLevel = slog.LevelError
+	}
+	if m.slowLevel == nil {
+		m.slowLevel = slog.LevelWarn
+	}
+	return m
+}
+
+// Handler wraps next with request logging.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		logger := slogutils.FromContext(ctx)
+		if m.attrs != nil {
+			if attrs := m.attrs(r); len(attrs) > 0 {
+				logger = slog.New(logger.Handler().WithAttrs(attrs))
+			}
+		}
+		info := &requestInfo{}
+		ctx = context.WithValue(slogutils.WithLogger(ctx, logger), requestInfoContextKey{}, info)
+		r = r.WithContext(ctx)
+
+		sw := &statusWriter{ResponseWriter: w}
+		timer := slogutils.StartTimer(ctx)
+		next.ServeHTTP(sw, r)
+		duration := timer.Stop()
+
+		status := sw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		level := m.toLevel(status, duration)
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+
+		route := info.route
+		if route == "" && m.route != nil {
+			route = m.route(r)
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+		}
+		if route != "" {
+			attrs = append(attrs, slog.String("route", route))
+		}
+		attrs = append(attrs, slog.Int("status", status), slog.Int64("bytes", sw.bytes))
+		if info.err != nil {
+			attrs = append(attrs, slogutils.Err(info.err))
+		}
+		attrs = append(attrs, timer.Attrs()...)
+
+		logger.LogAttrs(ctx, level, "HTTP request", attrs...)
+	})
+}
+
+func (m *Middleware) toLevel(status int, duration time.Duration) slog.Level {
+	if status >= http.StatusInternalServerError {
+		return m.errorLevel.Level()
+	}
+	if m.slowThreshold > 0 && duration >= m.slowThreshold {
+		return m.slowLevel.Level()
+	}
+	return m.level.Level()
+}
+
+type requestInfoContextKey struct{}
+
+// requestInfo is set by handlers or framework specific middleware to add information to the request record.
+type requestInfo struct {
+	route string
+	err   error
+}
+
+// SetRoute sets the route template of the request for the record of a Middleware.
+func SetRoute(ctx context.Context, route string) {
+	if info, ok := ctx.Value(requestInfoContextKey{}).(*requestInfo); ok {
+		info.route = route
+	}
+}
+
+// SetError sets the error that occurred while handling the request for the record of a Middleware.
+func SetError(ctx context.Context, err error) {
+	if info, ok := ctx.Value(requestInfoContextKey{}).(*requestInfo); ok {
+		info.err = err
+	}
+}
+
+// statusWriter records the status code and number of bytes written to a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher if the wrapped writer supports it.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
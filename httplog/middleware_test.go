@@ -0,0 +1,108 @@
+package httplog_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/httplog"
+)
+
+func TestMiddleware(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ctx":
+			slogutils.FromContext(r.Context()).Debug("Handling request")
+		case "/users/42":
+			httplog.SetRoute(r.Context(), "/users/{id}")
+			_, _ = w.Write([]byte("user"))
+		case "/fail":
+			httplog.SetError(r.Context(), errors.New("database unavailable"))
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/slow":
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	tests := []struct {
+		name string
+		opts *httplog.MiddlewareOptions
+		path string
+		want string
+	}{
+		{
+			name: "successful request is logged as info",
+			path: "/",
+			want: `level=INFO msg="HTTP request" method=GET path=/ status=200 bytes=0`,
+		},
+		{
+			name: "route is logged",
+			path: "/users/42",
+			want: `level=INFO msg="HTTP request" method=GET path=/users/42 route=/users/{id} status=200 bytes=4`,
+		},
+		{
+			name: "route function is used",
+			opts: &httplog.MiddlewareOptions{
+				Route: func(r *http.Request) string { return "/other" },
+			},
+			path: "/other",
+			want: `level=INFO msg="HTTP request" method=GET path=/other route=/other status=200 bytes=0`,
+		},
+		{
+			name: "server error is logged as error with error",
+			path: "/fail",
+			want: `level=ERROR msg="HTTP request" method=GET path=/fail status=503 bytes=0 err="database unavailable"`,
+		},
+		{
+			name: "slow request is logged with slow level",
+			opts: &httplog.MiddlewareOptions{
+				SlowThreshold: 10 * time.Millisecond,
+			},
+			path: "/slow",
+			want: `level=WARN msg="HTTP request" method=GET path=/slow status=200 bytes=0`,
+		},
+		{
+			name: "attributes are added to the context logger",
+			opts: &httplog.MiddlewareOptions{
+				Level: slog.LevelDebug,
+				Attrs: func(r *http.Request) []slog.Attr {
+					return []slog.Attr{slog.String("request_id", r.Header.Get("X-Request-Id"))}
+				},
+			},
+			path: "/ctx",
+			want: "level=DEBUG msg=\"Handling request\" request_id=abc\n" +
+				`level=DEBUG msg="HTTP request" request_id=abc method=GET path=/ctx status=200 bytes=0`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				Level: slog.LevelDebug,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					switch a.Key {
+					case slog.TimeKey, slogutils.StartKey, slogutils.DurationKey:
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			req.Header.Set("X-Request-Id", "abc")
+			req = req.WithContext(slogutils.WithLogger(req.Context(), logger))
+			httplog.NewMiddleware(test.opts).Handler(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if test.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", test.want, got)
+			}
+		})
+	}
+}
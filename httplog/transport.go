@@ -0,0 +1,157 @@
+package httplog
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+const redactedValue = "[REDACTED]"
+
+var defaultRedactHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization", "Set-Cookie"}
+
+// LoggingTransport is an http.RoundTripper that logs outbound requests and responses
+// with the logger from the request context (see slogutils.FromContext).
+type LoggingTransport struct {
+	next          http.RoundTripper
+	level         slog.Level
+	errorLevel    slog.Level
+	slowLevel     slog.Level
+	slowThreshold time.Duration
+	headers       []string
+	redactHeaders []string
+}
+
+var _ http.RoundTripper = (*LoggingTransport)(nil)
+
+// NewLoggingTransport wraps the given transport (or http.DefaultTransport if nil) with logging
+func NewLoggingTransport(next http.RoundTripper, opts ...TransportOpt) *LoggingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &LoggingTransport{
+		next:          next,
+		level:         slog.LevelDebug,
+		errorLevel:    slog.LevelError,
+		slowLevel:     slog.LevelWarn,
+		redactHeaders: defaultRedactHeaders,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// RoundTrip executes the request with the wrapped transport and logs the outcome, implements http.RoundTripper
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	timer := slogutils.StartTimer(ctx)
+	resp, err := t.next.RoundTrip(req)
+	duration := timer.Stop()
+
+	logger := slogutils.FromContext(ctx)
+
+	level := t.toLevel(resp, err, duration)
+	if !logger.Enabled(ctx, level) {
+		return resp, err
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.Redacted()),
+	}
+	if err != nil {
+		attrs = append(attrs, slogutils.Err(err))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	attrs = append(attrs, timer.Attrs()...)
+	if len(t.headers) > 0 {
+		attrs = append(attrs, t.headerAttrs("req_headers", req.Header))
+		if resp != nil {
+			attrs = append(attrs, t.headerAttrs("resp_headers", resp.Header))
+		}
+	}
+
+	logger.LogAttrs(ctx, level, "HTTP request", attrs...)
+
+	return resp, err
+}
+
+func (t *LoggingTransport) toLevel(resp *http.Response, err error, duration time.Duration) slog.Level {
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		return t.errorLevel
+	}
+	if t.slowThreshold > 0 && duration >= t.slowThreshold {
+		return t.slowLevel
+	}
+	return t.level
+}
+
+func (t *LoggingTransport) headerAttrs(key string, header http.Header) slog.Attr {
+	var attrs []any
+	for _, name := range t.headers {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		value := values[0]
+		if t.isRedacted(name) {
+			value = redactedValue
+		}
+		attrs = append(attrs, slog.String(http.CanonicalHeaderKey(name), value))
+	}
+	return slog.Group(key, attrs...)
+}
+
+func (t *LoggingTransport) isRedacted(name string) bool {
+	for _, redact := range t.redactHeaders {
+		if http.CanonicalHeaderKey(redact) == http.CanonicalHeaderKey(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// TransportOpt sets options for the logging transport
+type TransportOpt func(*LoggingTransport)
+
+// WithLevel sets the level for successful requests (defaults to debug)
+func WithLevel(level slog.Level) TransportOpt {
+	return func(t *LoggingTransport) {
+		t.level = level
+	}
+}
+
+// WithErrorLevel sets the level for failed requests and server errors (defaults to error)
+func WithErrorLevel(level slog.Level) TransportOpt {
+	return func(t *LoggingTransport) {
+		t.errorLevel = level
+	}
+}
+
+// WithSlowThreshold logs requests taking at least the given duration with the given level
+func WithSlowThreshold(threshold time.Duration, level slog.Level) TransportOpt {
+	return func(t *LoggingTransport) {
+		t.slowThreshold = threshold
+		t.slowLevel = level
+	}
+}
+
+// WithHeaders sets the request and response headers that should be logged.
+// Values of sensitive headers (Authorization, Cookie, Proxy-Authorization, Set-Cookie by default) are redacted.
+func WithHeaders(names ...string) TransportOpt {
+	return func(t *LoggingTransport) {
+		t.headers = names
+	}
+}
+
+// WithRedactHeaders replaces the list of headers whose values are redacted when logged
+func WithRedactHeaders(names ...string) TransportOpt {
+	return func(t *LoggingTransport) {
+		t.redactHeaders = names
+	}
+}
@@ -0,0 +1,113 @@
+package httplog_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/httplog"
+)
+
+func TestLoggingTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("X-Request-Id", "abc")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		opts []httplog.TransportOpt
+		path string
+		auth string
+		want string
+	}{
+		{
+			name: "successful request is logged as debug",
+			path: "/",
+			want: `level=DEBUG msg="HTTP request" method=GET url=URL/ status=200`,
+		},
+		{
+			name: "server error is logged as error",
+			path: "/fail",
+			want: `level=ERROR msg="HTTP request" method=GET url=URL/fail status=502`,
+		},
+		{
+			name: "slow request is logged with slow level",
+			opts: []httplog.TransportOpt{
+				httplog.WithSlowThreshold(10*time.Millisecond, slog.LevelWarn),
+			},
+			path: "/slow",
+			want: `level=WARN msg="HTTP request" method=GET url=URL/slow status=200`,
+		},
+		{
+			name: "level can be changed",
+			opts: []httplog.TransportOpt{
+				httplog.WithLevel(slog.LevelInfo),
+			},
+			path: "/",
+			want: `level=INFO msg="HTTP request" method=GET url=URL/ status=200`,
+		},
+		{
+			name: "headers are logged and redacted",
+			opts: []httplog.TransportOpt{
+				httplog.WithHeaders("Authorization", "X-Request-Id"),
+			},
+			path: "/",
+			auth: "Bearer secret",
+			want: `level=DEBUG msg="HTTP request" method=GET url=URL/ status=200 req_headers.Authorization=[REDACTED] resp_headers.X-Request-Id=abc`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+				Level:       slog.LevelDebug,
+				ReplaceAttr: drop(slog.TimeKey, slogutils.StartKey, slogutils.DurationKey),
+			}))
+			ctx := slogutils.WithLogger(context.Background(), logger)
+
+			client := &http.Client{Transport: httplog.NewLoggingTransport(nil, tt.opts...)}
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			got := strings.ReplaceAll(strings.TrimRight(buf.String(), "\n"), srv.URL, "URL")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// drop returns a ReplaceAttr that drops the given keys.
+func drop(keys ...string) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		for _, key := range keys {
+			if a.Key == key {
+				a = slog.Attr{}
+			}
+		}
+		return a
+	}
+}
@@ -0,0 +1,125 @@
+// Package joblog logs the execution of background jobs with a per-job logger.
+//
+// Workers of job queues wrap the execution of a job with Run, handlers get the job logger with
+// slogutils.FromContext:
+//
+//	err := joblog.Run(ctx, joblog.Job{ID: id, Kind: "send_email", Attempt: attempt}, nil, func(ctx context.Context) error {
+//		slogutils.FromContext(ctx).Info("Sending email")
+//		return send(ctx)
+//	})
+package joblog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Keys for job attributes.
+const (
+	JobIDKey      = "job_id"
+	JobKindKey    = "job_kind"
+	JobAttemptKey = "job_attempt"
+	JobQueueKey   = "job_queue"
+)
+
+// Job identifies the execution of a job.
+type Job struct {
+	ID      string
+	Kind    string
+	Attempt int
+	// Queue is optional and only added if not empty.
+	Queue string
+}
+
+// Attrs returns the attributes of the job for its logger.
+func (j Job) Attrs() []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String(JobIDKey, j.ID),
+		slog.String(JobKindKey, j.Kind),
+		slog.Int(JobAttemptKey, j.Attempt),
+	}
+	if j.Queue != "" {
+		attrs = append(attrs, slog.String(JobQueueKey, j.Queue))
+	}
+	return attrs
+}
+
+// Options are options for Run.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// StartLevel is the level for the record of a started job, defaults to debug.
+	StartLevel slog.Leveler
+	// Level is the level for the record of a completed job, defaults to info.
+	Level slog.Leveler
+	// ErrorLevel is the level for the record of a failed job, defaults to error.
+	ErrorLevel slog.Leveler
+
+	// RePanic panics again after logging a panic of the job instead of returning it as a *PanicError.
+	RePanic bool
+}
+
+// PanicError is returned by Run if the job panicked.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panic.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("job panicked: %v", e.Value)
+}
+
+// Run executes fn with a context carrying a logger with the job attributes (see slogutils.FromContext) and logs the
+// start, completion or failure of the job with its duration. A panic of fn is logged with the stack trace and returned
+// as a *PanicError. The logger of ctx is used as the parent of the job logger.
+func Run(ctx context.Context, job Job, opts *Options, fn func(ctx context.Context) error) (err error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	o := *opts
+	if o.StartLevel == nil {
+		o.StartLevel = slog.LevelDebug
+	}
+	if o.Level == nil {
+		o.Level = slog.LevelInfo
+	}
+	if o.ErrorLevel == nil {
+		o.ErrorLevel = slog.LevelError
+	}
+
+	logger := slogutils.FromContext(ctx)
+	logger = slog.New(logger.Handler().WithAttrs(job.Attrs()))
+	ctx = slogutils.WithLogger(ctx, logger)
+
+	logger.Log(ctx, o.StartLevel.Level(), "Job started")
+	timer := slogutils.StartTimer(ctx)
+
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := &PanicError{Value: r, Stack: debug.Stack()}
+			timer.Stop()
+			logger.LogAttrs(ctx, o.ErrorLevel.Level(), "Job panicked", append([]slog.Attr{
+				slogutils.Err(panicErr),
+				slog.String("stack", string(panicErr.Stack)),
+			}, timer.Attrs()...)...)
+			if o.RePanic {
+				panic(r)
+			}
+			err = panicErr
+		}
+	}()
+
+	err = fn(ctx)
+	timer.Stop()
+	if err != nil {
+		logger.LogAttrs(ctx, o.ErrorLevel.Level(), "Job failed", append([]slog.Attr{slogutils.Err(err)}, timer.Attrs()...)...)
+		return err
+	}
+	logger.LogAttrs(ctx, o.Level.Level(), "Job completed", timer.Attrs()...)
+	return nil
+}
@@ -0,0 +1,98 @@
+package joblog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/joblog"
+)
+
+func TestRun(t *testing.T) {
+	errFail := errors.New("smtp unavailable")
+
+	tests := []struct {
+		name    string
+		fn      func(ctx context.Context) error
+		wantErr bool
+		want    []string
+	}{
+		{
+			name: "completed job",
+			fn: func(ctx context.Context) error {
+				slogutils.FromContext(ctx).Info("Sending email")
+				return nil
+			},
+			want: []string{
+				`level=DEBUG msg="Job started" job_id=1 job_kind=send_email job_attempt=2 job_queue=mails`,
+				`level=INFO msg="Sending email" job_id=1 job_kind=send_email job_attempt=2 job_queue=mails`,
+				`level=INFO msg="Job completed" job_id=1 job_kind=send_email job_attempt=2 job_queue=mails`,
+			},
+		},
+		{
+			name: "failed job",
+			fn: func(ctx context.Context) error {
+				return errFail
+			},
+			wantErr: true,
+			want: []string{
+				`level=DEBUG msg="Job started" job_id=1 job_kind=send_email job_attempt=2 job_queue=mails`,
+				`level=ERROR msg="Job failed" job_id=1 job_kind=send_email job_attempt=2 job_queue=mails err="smtp unavailable"`,
+			},
+		},
+		{
+			name: "panicked job",
+			fn: func(ctx context.Context) error {
+				panic("nil map")
+			},
+			wantErr: true,
+			want: []string{
+				`level=DEBUG msg="Job started" job_id=1 job_kind=send_email job_attempt=2 job_queue=mails`,
+				`level=ERROR msg="Job panicked" job_id=1 job_kind=send_email job_attempt=2 job_queue=mails err="job panicked: nil map"`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				Level: slog.LevelDebug,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					switch a.Key {
+					case slog.TimeKey, slogutils.StartKey, slogutils.DurationKey, "stack":
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			ctx := slogutils.WithLogger(context.Background(), logger)
+
+			err := joblog.Run(ctx, joblog.Job{ID: "1", Kind: "send_email", Attempt: 2, Queue: "mails"}, nil, test.fn)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := strings.Join(test.want, "\n")
+			got := strings.TrimRight(buf.String(), "\n")
+			if want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+			}
+		})
+	}
+}
+
+func TestRun_PanicError(t *testing.T) {
+	ctx := slogutils.WithLogger(context.Background(), slog.New(slog.NewTextHandler(new(bytes.Buffer), nil)))
+	err := joblog.Run(ctx, joblog.Job{ID: "1", Kind: "test"}, nil, func(ctx context.Context) error {
+		panic("boom")
+	})
+
+	var panicErr *joblog.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+		t.Fatalf("expected panic error with stack, got %v", err)
+	}
+}
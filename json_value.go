@@ -0,0 +1,114 @@
+package slogutils
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"unicode/utf8"
+)
+
+const (
+	jsonDefaultMaxDepth = 4
+	jsonDefaultMaxSize  = 1024
+)
+
+// JSONOptions are options for rendering values of kind slog.KindAny as compact JSON.
+// A zero JSONOptions consists entirely of default values.
+type JSONOptions struct {
+	// MaxDepth limits the nesting depth of objects and arrays, deeper values are rendered as "…".
+	// A default of 4 is used if this is 0.
+	MaxDepth int
+	// MaxSize limits the size of the rendered JSON in bytes, longer JSON is truncated with "…".
+	// A default of 1024 is used if this is 0.
+	MaxSize int
+}
+
+// value renders maps, slices, arrays and structs (or pointers to them) as compact JSON.
+// It returns false for other values and values with a text or string representation (e.g. errors).
+func (o *JSONOptions) value(v slog.Value) (string, bool) {
+	if o == nil || v.Kind() != slog.KindAny {
+		return "", false
+	}
+
+	value := v.Any()
+	switch value.(type) {
+	case json.Marshaler:
+	case error, encoding.TextMarshaler, fmt.Stringer, []byte:
+		return "", false
+	default:
+		rv := reflect.ValueOf(value)
+		for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		switch rv.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		default:
+			return "", false
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+
+	maxDepth := o.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = jsonDefaultMaxDepth
+	}
+	// Decode the JSON to limit the depth, so struct tags and custom marshalers are respected
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return "", false
+	}
+	if limited, truncated := limitJSONDepth(decoded, maxDepth); truncated {
+		if data, err = json.Marshal(limited); err != nil {
+			return "", false
+		}
+	}
+
+	maxSize := o.MaxSize
+	if maxSize == 0 {
+		maxSize = jsonDefaultMaxSize
+	}
+	if len(data) > maxSize {
+		cut := maxSize
+		for cut > 0 && !utf8.RuneStart(data[cut]) {
+			cut--
+		}
+		return string(data[:cut]) + "…", true
+	}
+	return string(data), true
+}
+
+// limitJSONDepth replaces objects and arrays deeper than depth with "…".
+func limitJSONDepth(v any, depth int) (any, bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		if depth == 0 {
+			return "…", true
+		}
+		truncated := false
+		for key, child := range v {
+			var t bool
+			v[key], t = limitJSONDepth(child, depth-1)
+			truncated = truncated || t
+		}
+		return v, truncated
+	case []any:
+		if depth == 0 {
+			return "…", true
+		}
+		truncated := false
+		for i, child := range v {
+			var t bool
+			v[i], t = limitJSONDepth(child, depth-1)
+			truncated = truncated || t
+		}
+		return v, truncated
+	default:
+		return v, false
+	}
+}
@@ -0,0 +1,77 @@
+package lambda
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Units of metrics in the embedded metric format.
+const (
+	UnitNone         = "None"
+	UnitCount        = "Count"
+	UnitSeconds      = "Seconds"
+	UnitMilliseconds = "Milliseconds"
+	UnitMicroseconds = "Microseconds"
+	UnitBytes        = "Bytes"
+	UnitPercent      = "Percent"
+)
+
+// Metric is a metric value for EMF.
+type Metric struct {
+	Name string
+	// Unit of the value, defaults to UnitNone.
+	Unit  string
+	Value float64
+}
+
+type emfMetadata struct {
+	Timestamp         int64          `json:"Timestamp"`
+	CloudWatchMetrics []emfDirective `json:"CloudWatchMetrics"`
+}
+
+type emfDirective struct {
+	Namespace  string          `json:"Namespace"`
+	Dimensions [][]string      `json:"Dimensions"`
+	Metrics    []emfDefinition `json:"Metrics"`
+}
+
+type emfDefinition struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+// EMF returns an attribute with metrics in the CloudWatch embedded metric format, so CloudWatch extracts the metrics
+// from the log record:
+//
+//	logger.Info("Order processed", lambda.EMF("Shop", []slog.Attr{slog.String("Service", "orders")},
+//		lambda.Metric{Name: "Latency", Unit: lambda.UnitMilliseconds, Value: 12},
+//	))
+//
+// Dimensions and metric values must be top-level fields of the record, so the attribute (a group without a key that
+// is inlined) must not be logged with a logger that has a group.
+func EMF(namespace string, dimensions []slog.Attr, metrics ...Metric) slog.Attr {
+	directive := emfDirective{
+		Namespace:  namespace,
+		Dimensions: [][]string{make([]string, 0, len(dimensions))},
+		Metrics:    make([]emfDefinition, 0, len(metrics)),
+	}
+	attrs := make([]slog.Attr, 1, 1+len(dimensions)+len(metrics))
+	for _, d := range dimensions {
+		directive.Dimensions[0] = append(directive.Dimensions[0], d.Key)
+		attrs = append(attrs, slog.String(d.Key, d.Value.String()))
+	}
+	for _, m := range metrics {
+		unit := m.Unit
+		if unit == "" {
+			unit = UnitNone
+		}
+		directive.Metrics = append(directive.Metrics, emfDefinition{Name: m.Name, Unit: unit})
+		attrs = append(attrs, slog.Float64(m.Name, m.Value))
+	}
+	attrs[0] = slog.Any("_aws", emfMetadata{
+		Timestamp:         time.Now().UnixMilli(),
+		CloudWatchMetrics: []emfDirective{directive},
+	})
+
+	return slog.Attr{Value: slog.GroupValue(attrs...)}
+}
@@ -0,0 +1,210 @@
+// Package lambda provides a handler writing single-line JSON logs for AWS Lambda and CloudWatch Logs with the
+// request ID of the invocation, a cold start marker and support for the embedded metric format (see EMF).
+//
+// The request ID is taken from the context. To use the Lambda context of github.com/aws/aws-lambda-go without a
+// dependency of this package, set RequestIDFromContext:
+//
+//	h := lambda.NewHandler(os.Stdout, &lambda.Options{
+//		RequestIDFromContext: func(ctx context.Context) (string, bool) {
+//			lc, ok := lambdacontext.FromContext(ctx)
+//			if !ok {
+//				return "", false
+//			}
+//			return lc.AwsRequestID, true
+//		},
+//	})
+//	slog.SetDefault(slog.New(h))
+//	// Log with the context of the invocation
+//	slog.InfoContext(ctx, "Order processed", "order", id)
+package lambda
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Keys of the fields written by the handler, as used by the JSON log format of Lambda.
+const (
+	TimestampKey = "timestamp"
+	LevelKey     = "level"
+	MessageKey   = "message"
+	RequestIDKey = "requestId"
+	ColdStartKey = "coldStart"
+)
+
+// Options are options for a Handler.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Level reports the minimum record level that will be logged.
+	// If Level is nil, the handler assumes slog.LevelInfo.
+	Level slog.Leveler
+
+	// AddSource adds the source location of records.
+	AddSource bool
+
+	// ReplaceAttr is called to rewrite attributes before the built-in keys are mapped to Lambda fields.
+	// See slog.HandlerOptions.ReplaceAttr.
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+
+	// RequestIDFromContext returns the request ID of the invocation from the context.
+	// If RequestIDFromContext is nil, the request ID stored by ContextWithRequestID is used.
+	RequestIDFromContext func(ctx context.Context) (requestID string, ok bool)
+}
+
+type requestIDContextKey struct{}
+
+// ContextWithRequestID returns a new context with the request ID of an invocation, which is added to records logged
+// with the context.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored by ContextWithRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDContextKey{}).(string)
+	return requestID, ok
+}
+
+// Handler writes records as single-line JSON with the fields timestamp, level, message and requestId.
+// Records of the first invocation of the process (and of the init phase before) have coldStart set to true.
+type Handler struct {
+	// base is the JSON handler without groups and attributes of this handler
+	base slog.Handler
+	// next is the JSON handler with groups and attributes of this handler applied
+	next slog.Handler
+	goas []slogutils.GroupOrAttrs
+	// prefix caches the handler with Lambda fields before goas
+	prefix *slogutils.PrefixCache
+
+	requestIDFromContext func(ctx context.Context) (string, bool)
+	coldStart            *coldStart
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// coldStart tracks the first invocation and is shared by all handlers derived with WithAttrs and WithGroup.
+type coldStart struct {
+	mu        sync.Mutex
+	requestID string
+}
+
+// is reports whether the request ID belongs to the first invocation.
+func (c *coldStart) is(requestID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if requestID == "" {
+		// Records without a request ID are a cold start if they are logged in the init phase
+		return c.requestID == ""
+	}
+	if c.requestID == "" {
+		c.requestID = requestID
+	}
+	return c.requestID == requestID
+}
+
+// NewHandler creates a handler writing JSON records to w (e.g. os.Stdout).
+func NewHandler(w io.Writer, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	requestIDFromContext := opts.RequestIDFromContext
+	if requestIDFromContext == nil {
+		requestIDFromContext = RequestIDFromContext
+	}
+
+	replaceAttr := opts.ReplaceAttr
+	jh := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:     opts.Level,
+		AddSource: opts.AddSource,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if replaceAttr != nil {
+				a = replaceAttr(groups, a)
+				if a.Equal(slog.Attr{}) {
+					return a
+				}
+			}
+			return mapAttr(groups, a)
+		},
+	})
+
+	return &Handler{
+		base:                 jh,
+		next:                 jh,
+		requestIDFromContext: requestIDFromContext,
+		coldStart:            &coldStart{},
+	}
+}
+
+func mapAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.TimeKey:
+		a.Key = TimestampKey
+	case slog.LevelKey:
+		a.Key = LevelKey
+	case slog.MessageKey:
+		a.Key = MessageKey
+	}
+	return a
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	r, _ = slogutils.ExtractControls(r)
+
+	requestID, _ := h.requestIDFromContext(ctx)
+
+	attrs := make([]slog.Attr, 0, 2)
+	if requestID != "" {
+		attrs = append(attrs, slog.String(RequestIDKey, requestID))
+	}
+	if h.coldStart.is(requestID) {
+		attrs = append(attrs, slog.Bool(ColdStartKey, true))
+	}
+	if len(attrs) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	if len(h.goas) == 0 {
+		return h.next.Handle(ctx, slogutils.CloneRecordWithAttrs(r, attrs...))
+	}
+
+	return h.prefix.Handler(attrs).Handle(ctx, r)
+}
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs, next slog.Handler) *Handler {
+	h2 := *h // Copy handler
+	h2.next = next
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	h2.prefix = slogutils.NewPrefixCache(h.base, h2.goas)
+	return &h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs, _ = slogutils.StripControlAttrs(attrs)
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs}, h.next.WithAttrs(attrs))
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name}, h.next.WithGroup(name))
+}
@@ -0,0 +1,81 @@
+package lambda_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils/lambda"
+)
+
+func TestHandler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(lambda.NewHandler(buf, &lambda.Options{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	logger.Info("Init")
+	ctx := lambda.ContextWithRequestID(context.Background(), "req-1")
+	logger.WithGroup("order").InfoContext(ctx, "Order processed", "id", 42)
+	ctx = lambda.ContextWithRequestID(context.Background(), "req-2")
+	logger.WarnContext(ctx, "Order failed")
+
+	want := strings.Join([]string{
+		`{"level":"INFO","message":"Init","coldStart":true}`,
+		`{"level":"INFO","message":"Order processed","requestId":"req-1","coldStart":true,"order":{"id":42}}`,
+		`{"level":"WARN","message":"Order failed","requestId":"req-2"}`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+func TestEMF(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(lambda.NewHandler(buf, nil))
+
+	logger.Info("Order processed", lambda.EMF("Shop", []slog.Attr{slog.String("Service", "orders")},
+		lambda.Metric{Name: "Latency", Unit: lambda.UnitMilliseconds, Value: 12},
+		lambda.Metric{Name: "Items", Value: 3},
+	))
+
+	var entry struct {
+		AWS struct {
+			Timestamp         int64
+			CloudWatchMetrics []struct {
+				Namespace  string
+				Dimensions [][]string
+				Metrics    []struct{ Name, Unit string }
+			}
+		} `json:"_aws"`
+		Service string
+		Latency float64
+		Items   float64
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+
+	if entry.AWS.Timestamp == 0 || len(entry.AWS.CloudWatchMetrics) != 1 {
+		t.Fatalf("unexpected metadata: %s", buf.String())
+	}
+	directive := entry.AWS.CloudWatchMetrics[0]
+	if directive.Namespace != "Shop" || len(directive.Dimensions) != 1 || strings.Join(directive.Dimensions[0], ",") != "Service" {
+		t.Errorf("unexpected directive: %s", buf.String())
+	}
+	if len(directive.Metrics) != 2 || directive.Metrics[0].Unit != "Milliseconds" || directive.Metrics[1].Unit != "None" {
+		t.Errorf("unexpected metric definitions: %s", buf.String())
+	}
+	if entry.Service != "orders" || entry.Latency != 12 || entry.Items != 3 {
+		t.Errorf("unexpected values: %s", buf.String())
+	}
+}
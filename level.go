@@ -0,0 +1,33 @@
+package slogutils
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// ParseLevel parses a level name like "debug", "INFO", "warn+2" or "trace" (for LevelTrace).
+// Names are case-insensitive, "warning" is accepted as an alias of "warn".
+func ParseLevel(s string) (slog.Level, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	switch name {
+	case "trace":
+		return LevelTrace, nil
+	case "warning":
+		return slog.LevelWarn, nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("invalid level %q", s)
+	}
+	return level, nil
+}
+
+// LevelName returns the lowercase name of a level that can be parsed by ParseLevel, e.g. "info" or "trace".
+func LevelName(level slog.Level) string {
+	if level == LevelTrace {
+		return "trace"
+	}
+	return strings.ToLower(level.String())
+}
@@ -0,0 +1,88 @@
+package slogutils
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type levelPayload struct {
+	Level string `json:"level"`
+}
+
+type levelErrorPayload struct {
+	Error string `json:"error"`
+}
+
+// LevelHandler returns an HTTP handler to get and change the level of a LevelVar at runtime.
+// It is compatible with the /log/level endpoint of zap:
+//
+//   - GET returns the current level as JSON: {"level":"info"}
+//   - PUT sets the level from a JSON body {"level":"debug"} or a form value "level" and returns the new level
+func LevelHandler(levelVar *slog.LevelVar) http.Handler {
+	return ScopedLevelHandler(levelVar, nil)
+}
+
+// ScopedLevelHandler is like LevelHandler, but additionally gets and changes the level of named scopes, which are
+// selected with the query parameter "scope" (e.g. PUT /log/level?scope=db). The scopes function returns the LevelVar
+// of a scope or nil if the scope is unknown, which is answered with 404 Not Found. Use a lookup that does not create
+// scopes for unknown names (e.g. Registry.LookupLevelVar), since the scope is taken from the request.
+func ScopedLevelHandler(levelVar *slog.LevelVar, scopes func(scope string) *slog.LevelVar) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lv := levelVar
+		if scope := r.URL.Query().Get("scope"); scope != "" {
+			// The global level is only used without a scope, unknown scopes must not change it
+			lv = nil
+			if scopes != nil {
+				lv = scopes(scope)
+			}
+			if lv == nil {
+				writeLevelJSON(w, http.StatusNotFound, levelErrorPayload{Error: "unknown scope " + scope})
+				return
+			}
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeLevelJSON(w, http.StatusOK, levelPayload{Level: LevelName(lv.Level())})
+		case http.MethodPut:
+			name, err := requestedLevel(r)
+			if err != nil {
+				writeLevelJSON(w, http.StatusBadRequest, levelErrorPayload{Error: err.Error()})
+				return
+			}
+			level, err := ParseLevel(name)
+			if err != nil {
+				writeLevelJSON(w, http.StatusBadRequest, levelErrorPayload{Error: err.Error()})
+				return
+			}
+			lv.Set(level)
+			writeLevelJSON(w, http.StatusOK, levelPayload{Level: LevelName(level)})
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeLevelJSON(w, http.StatusMethodNotAllowed, levelErrorPayload{Error: "only GET and PUT are supported"})
+		}
+	})
+}
+
+func requestedLevel(r *http.Request) (string, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			return "", err
+		}
+		return r.Form.Get("level"), nil
+	}
+
+	var payload levelPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return "", err
+	}
+	return payload.Level, nil
+}
+
+func writeLevelJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,139 @@
+package slogutils_test
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestParseLevel(t *testing.T) {
+	for s, want := range map[string]slog.Level{
+		"trace":   slogutils.LevelTrace,
+		"DEBUG":   slog.LevelDebug,
+		"info":    slog.LevelInfo,
+		"warning": slog.LevelWarn,
+		"error+2": slog.LevelError + 2,
+	} {
+		got, err := slogutils.ParseLevel(s)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", s, err)
+		}
+		if got != want {
+			t.Errorf("expected %v for %q, got %v", want, s, got)
+		}
+	}
+
+	if _, err := slogutils.ParseLevel("verbose"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	levelVar := new(slog.LevelVar)
+	dbLevelVar := new(slog.LevelVar)
+	dbLevelVar.Set(slog.LevelWarn)
+
+	h := slogutils.ScopedLevelHandler(levelVar, func(scope string) *slog.LevelVar {
+		if scope == "db" {
+			return dbLevelVar
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:       "get level",
+			method:     http.MethodGet,
+			target:     "/log/level",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"level":"info"}`,
+		},
+		{
+			name:       "put level as JSON",
+			method:     http.MethodPut,
+			target:     "/log/level",
+			body:       `{"level":"debug"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"level":"debug"}`,
+		},
+		{
+			name:        "put level as form",
+			method:      http.MethodPut,
+			target:      "/log/level?scope=db",
+			contentType: "application/x-www-form-urlencoded",
+			body:        `level=trace`,
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"level":"trace"}`,
+		},
+		{
+			name:       "invalid level",
+			method:     http.MethodPut,
+			target:     "/log/level",
+			body:       `{"level":"verbose"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"invalid level \"verbose\""}`,
+		},
+		{
+			name:       "unknown scope",
+			method:     http.MethodGet,
+			target:     "/log/level?scope=http",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"error":"unknown scope http"}`,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			target:     "/log/level",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   `{"error":"only GET and PUT are supported"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, got)
+			}
+		})
+	}
+
+	t.Run("scope without scopes", func(t *testing.T) {
+		levelVar := new(slog.LevelVar)
+		req := httptest.NewRequest(http.MethodPut, "/log/level?scope=db", strings.NewReader(`{"level":"debug"}`))
+		rec := httptest.NewRecorder()
+		slogutils.LevelHandler(levelVar).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+		}
+		if levelVar.Level() != slog.LevelInfo {
+			t.Errorf("expected global level to be unchanged, got %v", levelVar.Level())
+		}
+	})
+
+	if levelVar.Level() != slog.LevelDebug || dbLevelVar.Level() != slogutils.LevelTrace {
+		t.Fatalf("unexpected levels %v and %v", levelVar.Level(), dbLevelVar.Level())
+	}
+}
@@ -0,0 +1,146 @@
+package logfile
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Codec compresses log files. Codecs for other formats (e.g. zstd with github.com/klauspost/compress/zstd)
+// can be plugged in by implementing this interface.
+type Codec interface {
+	// Ext is the file extension of compressed files including the dot (e.g. ".gz").
+	Ext() string
+	// NewWriter returns a writer compressing to w. Close must flush all data but not close w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	// NewReader returns a reader decompressing r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// GzipCodec compresses log files with gzip.
+type GzipCodec struct {
+	// Level is the compression level, defaults to gzip.DefaultCompression.
+	Level int
+}
+
+var _ Codec = GzipCodec{}
+
+// Gzip is a gzip codec with the default compression level.
+var Gzip = GzipCodec{Level: gzip.DefaultCompression}
+
+func (c GzipCodec) Ext() string {
+	return ".gz"
+}
+
+func (c GzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+func (c GzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// DefaultCodecs are the codecs used by OpenReader if no codecs are given.
+var DefaultCodecs = []Codec{Gzip}
+
+// OpenReader opens a log file for reading. If the extension of the path matches a codec, the content is
+// transparently decompressed, so tools can read rotated and active files alike.
+func OpenReader(path string, codecs ...Codec) (io.ReadCloser, error) {
+	if len(codecs) == 0 {
+		codecs = DefaultCodecs
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening log file: %w", err)
+	}
+	for _, c := range codecs {
+		if !strings.HasSuffix(path, c.Ext()) {
+			continue
+		}
+		r, err := c.NewReader(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("decompressing log file: %w", err)
+		}
+		return &decompressReader{ReadCloser: r, f: f}, nil
+	}
+	return f, nil
+}
+
+type decompressReader struct {
+	io.ReadCloser
+	f *os.File
+}
+
+// Read returns io.EOF at the end of an incomplete compressed stream, since the active file of a Writer with Stream
+// enabled ends with a flushed but unfinished stream.
+func (r *decompressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (r *decompressReader) Close() error {
+	err := r.ReadCloser.Close()
+	if fErr := r.f.Close(); err == nil {
+		err = fErr
+	}
+	return err
+}
+
+// CompressFile compresses the file at path to path with the codec extension and removes the original file,
+// e.g. to compress a rotated file in the background. The compressed file is created with perm, defaults to 0644.
+func CompressFile(path string, codec Codec, perm os.FileMode) error {
+	if perm == 0 {
+		perm = defaultPerm
+	}
+	return compressFile(path, codec, perm)
+}
+
+// compressFile compresses the file at path to path with the codec extension and removes the original file.
+func compressFile(path string, codec Codec, perm os.FileMode) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening rotated log file: %w", err)
+	}
+	defer src.Close()
+
+	dstPath := path + codec.Ext()
+	dst, err := os.OpenFile(dstPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("creating compressed log file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = dst.Close()
+			_ = os.Remove(dstPath)
+		}
+	}()
+
+	cw, err := codec.NewWriter(dst)
+	if err != nil {
+		return fmt.Errorf("compressing log file: %w", err)
+	}
+	if _, err = io.Copy(cw, src); err != nil {
+		return fmt.Errorf("compressing log file: %w", err)
+	}
+	if err = cw.Close(); err != nil {
+		return fmt.Errorf("compressing log file: %w", err)
+	}
+	if err = dst.Close(); err != nil {
+		return fmt.Errorf("compressing log file: %w", err)
+	}
+
+	_ = src.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package logfile
+
+import "os"
+
+// Advisory locks are not supported on this platform, writes rely on O_APPEND only.
+
+func lockFile(*os.File) error {
+	return nil
+}
+
+func unlockFile(*os.File) error {
+	return nil
+}
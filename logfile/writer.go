@@ -0,0 +1,285 @@
+// Package logfile provides a writer for log files that can be shared by multiple processes.
+package logfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPerm = 0o644
+
+	// rotationCheckInterval is the minimum interval between checks for a rotation by another process without Lock
+	rotationCheckInterval = time.Second
+)
+
+// now returns the current time, it is replaced in tests.
+var now = time.Now
+
+// Options are options for a Writer.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Lock enables coordination of writes between multiple processes with an advisory lock (flock) on the file.
+	// Writes are always appended atomically with O_APPEND, the lock additionally ensures that a rotation by another
+	// process is noticed before writing. On platforms without flock, only O_APPEND is used.
+	// Without Lock, a rotation by another process is checked at most once per second.
+	Lock bool
+
+	// Perm is the permission of a newly created file, defaults to 0644.
+	Perm fs.FileMode
+
+	// Compress compresses rotated files with the codec (e.g. Gzip). The rotated file is written to the new path
+	// with the codec extension appended (e.g. "app.log.1.gz"), the uncompressed file is removed.
+	Compress Codec
+
+	// Stream writes the active file compressed with the Compress codec, so the file at the path contains
+	// compressed data and rotated files are not compressed again. Writes are buffered by the codec until
+	// Sync or Close is called. Stream cannot be combined with Lock.
+	Stream bool
+}
+
+// Writer appends to a log file. It notices if the file was rotated (renamed or removed) by another process
+// and reopens the file at its path.
+type Writer struct {
+	path string
+	perm fs.FileMode
+	lock bool
+
+	compress Codec
+	stream   bool
+
+	mu sync.Mutex
+	f  *os.File
+	// cw compresses writes to f if stream is enabled
+	cw io.WriteCloser
+	// checkedAt is the time of the last check for a rotation without lock
+	checkedAt time.Time
+}
+
+var _ io.WriteCloser = (*Writer)(nil)
+
+// Open opens or creates the log file at path for appending.
+func Open(path string, opts *Options) (*Writer, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	perm := opts.Perm
+	if perm == 0 {
+		perm = defaultPerm
+	}
+	if opts.Stream && opts.Compress == nil {
+		return nil, errors.New("stream compression requires a codec")
+	}
+	if opts.Stream && opts.Lock {
+		return nil, errors.New("stream compression cannot be combined with lock")
+	}
+
+	w := &Writer{
+		path:     path,
+		perm:     perm,
+		lock:     opts.Lock,
+		compress: opts.Compress,
+		stream:   opts.Stream,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, w.perm)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	w.f = f
+	if w.stream {
+		cw, err := w.compress.NewWriter(f)
+		if err != nil {
+			_ = f.Close()
+			w.f = nil
+			return fmt.Errorf("compressing log file: %w", err)
+		}
+		w.cw = cw
+	}
+	return nil
+}
+
+// closeFile closes the open file and finishes the compressed stream.
+func (w *Writer) closeFile() error {
+	var err error
+	if w.cw != nil {
+		err = w.cw.Close()
+		w.cw = nil
+	}
+	if fErr := w.f.Close(); err == nil {
+		err = fErr
+	}
+	w.f = nil
+	return err
+}
+
+func (w *Writer) reopen() error {
+	_ = w.closeFile()
+	return w.open()
+}
+
+// Path returns the path of the log file.
+func (w *Writer) Path() string {
+	return w.path
+}
+
+// Write appends p to the log file in a single write.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+
+	unlock, err := w.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if w.cw != nil {
+		return w.cw.Write(p)
+	}
+	return w.f.Write(p)
+}
+
+// Rotate renames the log file to newPath and continues writing to a new file at the original path.
+// Other processes writing to the same path with Lock enabled will notice the rotation and reopen the file.
+// If Compress is set (without Stream), the rotated file is compressed to newPath with the codec extension.
+func (w *Writer) Rotate(newPath string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return os.ErrClosed
+	}
+
+	unlock, err := w.acquire()
+	if err != nil {
+		return err
+	}
+	if w.cw != nil {
+		// Finish the compressed stream, so the rotated file is complete
+		err = w.cw.Close()
+		w.cw = nil
+		if err != nil {
+			unlock()
+			return fmt.Errorf("compressing log file: %w", err)
+		}
+	}
+	err = os.Rename(w.path, newPath)
+	unlock()
+	if err != nil {
+		return errors.Join(fmt.Errorf("renaming log file: %w", err), w.reopen())
+	}
+
+	if err := w.reopen(); err != nil {
+		return err
+	}
+
+	// Writers of other processes reopen the file after the rename while holding the lock,
+	// so the rotated file is not written to anymore
+	if w.compress != nil && !w.stream {
+		return compressFile(newPath, w.compress, w.perm)
+	}
+	return nil
+}
+
+// acquire locks the current file (if enabled) and makes sure it is still the file at the path.
+// The returned function releases the lock.
+func (w *Writer) acquire() (unlock func(), err error) {
+	if !w.lock {
+		// Checking needs two stat calls, so it is rate limited to not slow down every write
+		if t := now(); t.Sub(w.checkedAt) >= rotationCheckInterval {
+			w.checkedAt = t
+			if err := w.reopenIfRotated(); err != nil {
+				return nil, err
+			}
+		}
+		return func() {}, nil
+	}
+
+	for {
+		if err := lockFile(w.f); err != nil {
+			return nil, fmt.Errorf("locking log file: %w", err)
+		}
+
+		rotated, err := w.rotated()
+		if err != nil {
+			_ = unlockFile(w.f)
+			return nil, err
+		}
+		if !rotated {
+			f := w.f
+			return func() { _ = unlockFile(f) }, nil
+		}
+
+		_ = unlockFile(w.f)
+		if err := w.reopen(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (w *Writer) reopenIfRotated() error {
+	rotated, err := w.rotated()
+	if err != nil || !rotated {
+		return err
+	}
+	return w.reopen()
+}
+
+// rotated checks if the open file is not the file at the path anymore.
+func (w *Writer) rotated() (bool, error) {
+	pathInfo, err := os.Stat(w.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("checking log file: %w", err)
+	}
+	fileInfo, err := w.f.Stat()
+	if err != nil {
+		return false, fmt.Errorf("checking log file: %w", err)
+	}
+	return !os.SameFile(pathInfo, fileInfo), nil
+}
+
+// Sync commits the contents of the log file to stable storage.
+func (w *Writer) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return os.ErrClosed
+	}
+	if f, ok := w.cw.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			return err
+		}
+	}
+	return w.f.Sync()
+}
+
+// Close closes the log file.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return nil
+	}
+	return w.closeFile()
+}
@@ -0,0 +1,207 @@
+package logfile_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils/logfile"
+)
+
+func TestWriter_concurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		w, err := logfile.Open(path, &logfile.Options{Lock: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer w.Close()
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_, _ = fmt.Fprintf(w, "writer=%d line=%d %s\n", i, j, strings.Repeat("x", 100))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	lines := readLines(t, path)
+	if len(lines) != 200 {
+		t.Fatalf("expected 200 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.HasSuffix(line, strings.Repeat("x", 100)) {
+			t.Fatalf("interleaved line: %q", line)
+		}
+	}
+}
+
+func TestWriter_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w1, err := logfile.Open(path, &logfile.Options{Lock: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w1.Close()
+	w2, err := logfile.Open(path, &logfile.Options{Lock: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w2.Close()
+
+	_, _ = w1.Write([]byte("before 1\n"))
+	_, _ = w2.Write([]byte("before 2\n"))
+
+	if err := w1.Rotate(filepath.Join(dir, "app.log.1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, _ = w2.Write([]byte("after 2\n"))
+	_, _ = w1.Write([]byte("after 1\n"))
+
+	if got := strings.Join(readLines(t, filepath.Join(dir, "app.log.1")), ","); got != "before 1,before 2" {
+		t.Fatalf("unexpected rotated file content: %s", got)
+	}
+	if got := strings.Join(readLines(t, path), ","); got != "after 1,after 2" {
+		t.Fatalf("unexpected file content: %s", got)
+	}
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	sort.Strings(lines)
+	return lines
+}
+
+func TestWriter_compressOnRotate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := logfile.Open(path, &logfile.Options{Compress: logfile.Gzip})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	_, _ = fmt.Fprintln(w, "before rotation")
+	if err := w.Rotate(path + ".1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = fmt.Fprintln(w, "after rotation")
+
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Fatalf("expected uncompressed rotated file to be removed, got %v", err)
+	}
+	if got := readAll(t, path+".1.gz"); got != "before rotation\n" {
+		t.Fatalf("unexpected content of rotated file: %q", got)
+	}
+	if got := readAll(t, path); got != "after rotation\n" {
+		t.Fatalf("unexpected content of active file: %q", got)
+	}
+}
+
+func TestWriter_stream(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log.gz")
+
+	w, err := logfile.Open(path, &logfile.Options{Compress: logfile.Gzip, Stream: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, _ = fmt.Fprintln(w, "line 1")
+	if err := w.Sync(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := readAll(t, path); got != "line 1\n" {
+		t.Fatalf("unexpected content after sync: %q", got)
+	}
+
+	_, _ = fmt.Fprintln(w, "line 2")
+	if err := w.Rotate(filepath.Join(dir, "app.log.1.gz")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = fmt.Fprintln(w, "line 3")
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Reopening appends a new compressed stream
+	w, err = logfile.Open(path, &logfile.Options{Compress: logfile.Gzip, Stream: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = fmt.Fprintln(w, "line 4")
+	_ = w.Close()
+
+	if got := readAll(t, filepath.Join(dir, "app.log.1.gz")); got != "line 1\nline 2\n" {
+		t.Fatalf("unexpected content of rotated file: %q", got)
+	}
+	if got := readAll(t, path); got != "line 3\nline 4\n" {
+		t.Fatalf("unexpected content of active file: %q", got)
+	}
+}
+
+func TestWriter_rotatedWithoutLock(t *testing.T) {
+	clock := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	defer logfile.SetNow(func() time.Time {
+		return clock
+	})()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := logfile.Open(path, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	_, _ = fmt.Fprintln(w, "first")
+	// Rotation by another process
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = fmt.Fprintln(w, "second")
+	clock = clock.Add(time.Second)
+	_, _ = fmt.Fprintln(w, "third")
+
+	if got := readAll(t, path+".1"); got != "first\nsecond\n" {
+		t.Errorf("unexpected content of rotated file: %q", got)
+	}
+	if got := readAll(t, path); got != "third\n" {
+		t.Errorf("expected rotation to be noticed after the check interval, got %q", got)
+	}
+}
+
+func readAll(t *testing.T, path string) string {
+	t.Helper()
+
+	r, err := logfile.OpenReader(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(content)
+}
@@ -0,0 +1,594 @@
+// Package loki provides a handler pushing records in batches to the HTTP push API of Grafana Loki.
+package loki
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Format is the format of log lines.
+type Format int
+
+const (
+	// FormatLogfmt renders the message and attributes as logfmt (like slog.TextHandler).
+	FormatLogfmt Format = iota
+	// FormatJSON renders the message and attributes as a JSON object (like slog.JSONHandler).
+	FormatJSON
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultBatchWait    = time.Second
+	defaultMaxRetries   = 5
+	defaultMinBackoff   = 500 * time.Millisecond
+	defaultMaxBackoff   = 30 * time.Second
+	defaultMaxBuffered  = 10000
+	defaultTimeout      = 10 * time.Second
+	defaultFlushTimeout = 5 * time.Second
+)
+
+// Names of stream labels added by the handler.
+const (
+	// LevelLabel is the name of the stream label for the level of records.
+	LevelLabel = "level"
+	// RetainLabel is the name of the stream label for the retention of records declared with slogutils.Retain.
+	// It can be selected by the retention_stream configuration of Loki.
+	RetainLabel = "retain"
+)
+
+// Options are options for a Handler.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Level reports the minimum record level that will be logged.
+	// If Level is nil, the handler assumes slog.LevelInfo.
+	Level slog.Leveler
+
+	// Labels are keys of attributes that are extracted as stream labels (e.g. "app", "env") instead of being
+	// rendered in the line. Keys of attributes in groups are qualified by the group names (see GroupKeys).
+	// Only use attributes with a low cardinality as labels. The level is always added as LevelLabel and the retention
+	// of records declared with slogutils.Retain as RetainLabel.
+	Labels []string
+
+	// StaticLabels are added to every stream.
+	StaticLabels map[string]string
+
+	// Format of the log lines, defaults to FormatLogfmt.
+	Format Format
+
+	// GroupKeys controls how keys of attributes in groups are joined, defaults to a dot.
+	// Attributes are always flattened, since labels are extracted by their qualified keys.
+	GroupKeys slogutils.GroupKeys
+
+	// BatchSize is the number of records that triggers a push, defaults to 100.
+	BatchSize int
+
+	// BatchWait is the maximum time records are buffered before they are pushed, defaults to one second.
+	BatchWait time.Duration
+
+	// MaxRetries is the number of retries of a failed push, defaults to 5.
+	// Pushes are retried on network errors, 429 and 5xx responses.
+	MaxRetries int
+
+	// MinBackoff is the backoff before the first retry, defaults to 500ms. It is doubled for every retry.
+	MinBackoff time.Duration
+
+	// MaxBackoff limits the backoff between retries, defaults to 30s.
+	MaxBackoff time.Duration
+
+	// MaxBuffered is the maximum number of buffered records, defaults to 10000.
+	// Records are dropped while the buffer is full (e.g. Loki is not reachable), see Handler.Dropped.
+	MaxBuffered int
+
+	// Client is the HTTP client for pushes, defaults to a client with a timeout of 10 seconds.
+	Client *http.Client
+
+	// FlushTimeout is the maximum duration Flush and Close wait for buffered records to be pushed, defaults to
+	// 5 seconds. Close aborts a pending push when the timeout is exceeded.
+	FlushTimeout time.Duration
+
+	// Header is added to every push request, e.g. for authentication or the tenant ID ("X-Scope-OrgID").
+	Header http.Header
+
+	// OnError is called with the number of records and the error if a batch could not be pushed after all retries.
+	// Unlike OnError of handlers writing single records (e.g. syslog.Options.OnError), records are pushed in
+	// batches, so only their number is passed. Errors of Flush and Close are returned instead.
+	// If OnError is nil, the error is written to os.Stderr.
+	OnError func(records int, err error)
+}
+
+// Handler buffers records and pushes them in batches to Loki.
+// Close must be called to push remaining records before the program exits.
+type Handler struct {
+	opts     *Options
+	pusher   *pusher
+	goas     []slogutils.GroupOrAttrs
+	controls slogutils.Controls
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler creates a handler pushing records to the push API at url (e.g. "http://localhost:3100/loki/api/v1/push").
+func NewHandler(url string, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	o := *opts
+	if o.Level == nil {
+		o.Level = slog.LevelInfo
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = defaultBatchSize
+	}
+	if o.BatchWait <= 0 {
+		o.BatchWait = defaultBatchWait
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = defaultMaxRetries
+	}
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = defaultMinBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = defaultMaxBackoff
+	}
+	if o.MaxBuffered <= 0 {
+		o.MaxBuffered = defaultMaxBuffered
+	}
+	if o.Client == nil {
+		o.Client = &http.Client{Timeout: defaultTimeout}
+	}
+	if o.FlushTimeout <= 0 {
+		o.FlushTimeout = defaultFlushTimeout
+	}
+	if o.OnError == nil {
+		o.OnError = func(records int, err error) {
+			_, _ = fmt.Fprintf(os.Stderr, "loki: dropped %d records: %v\n", records, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &pusher{
+		url:     url,
+		opts:    &o,
+		ctx:     ctx,
+		cancel:  cancel,
+		fullCh:  make(chan struct{}, 1),
+		flushCh: make(chan chan error),
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go p.run()
+
+	return &Handler{opts: &o, pusher: p}
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	r, controls := slogutils.ExtractControls(r)
+	controls = slogutils.ControlsFromContext(ctx).Merge(h.controls).Merge(controls)
+
+	labels := make(map[string]string, len(h.opts.StaticLabels)+len(h.opts.Labels)+2)
+	for k, v := range h.opts.StaticLabels {
+		labels[k] = v
+	}
+	labels[LevelLabel] = strings.ToLower(r.Level.String())
+	if controls.Retain != "" {
+		labels[RetainLabel] = controls.Retain
+	}
+
+	// Attributes are flattened to qualified keys, so labels can be extracted regardless of groups
+	var attrs []slog.Attr
+	var prefix string
+	sep := h.opts.GroupKeys.Separator()
+	collect := func(a slog.Attr) {
+		for _, fa := range flatten(prefix, sep, slogutils.ResolveAttr(a, 0)) {
+			if h.isLabel(fa.Key) {
+				labels[labelName(fa.Key)] = fa.Value.String()
+				continue
+			}
+			attrs = append(attrs, fa)
+		}
+	}
+	for _, goa := range h.goas {
+		if goa.Group != "" {
+			prefix += goa.Group + sep
+			continue
+		}
+		for _, a := range goa.Attrs {
+			collect(a)
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		collect(a)
+		return true
+	})
+
+	line, err := h.renderLine(r, attrs)
+	if err != nil {
+		return err
+	}
+
+	h.pusher.add(labels, entry{time: r.Time, line: line})
+	return nil
+}
+
+func (h *Handler) isLabel(key string) bool {
+	for _, l := range h.opts.Labels {
+		if l == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Handler) renderLine(r slog.Record, attrs []slog.Attr) (string, error) {
+	buf := new(bytes.Buffer)
+	handlerOpts := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// Time and level are part of the entry and stream labels
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}
+	var lh slog.Handler
+	if h.opts.Format == FormatJSON {
+		lh = slog.NewJSONHandler(buf, handlerOpts)
+	} else {
+		lh = slog.NewTextHandler(buf, handlerOpts)
+	}
+
+	r2 := slog.NewRecord(time.Time{}, r.Level, r.Message, r.PC)
+	r2.AddAttrs(attrs...)
+	if err := lh.Handle(context.Background(), r2); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// flatten returns the non-group attributes of a with keys qualified by prefix and group names joined by sep.
+func flatten(prefix, sep string, a slog.Attr) []slog.Attr {
+	if a.Equal(slog.Attr{}) {
+		return nil
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		a.Key = prefix + a.Key
+		return []slog.Attr{a}
+	}
+
+	groupPrefix := prefix
+	if a.Key != "" {
+		groupPrefix += a.Key + sep
+	}
+	var attrs []slog.Attr
+	for _, ga := range a.Value.Group() {
+		attrs = append(attrs, flatten(groupPrefix, sep, ga)...)
+	}
+	return attrs
+}
+
+// labelName returns a valid Loki label name matching [a-zA-Z_][a-zA-Z0-9_]*.
+func labelName(key string) string {
+	var sb strings.Builder
+	for i, c := range key {
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9') {
+			sb.WriteRune(c)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs) *Handler {
+	h2 := *h // Copy handler
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs, controls := slogutils.StripControlAttrs(attrs)
+	if controls != (slogutils.Controls{}) {
+		h2 := *h // Copy handler
+		h2.controls = h.controls.Merge(controls)
+		h = &h2
+	}
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs})
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name})
+}
+
+// Flush pushes all buffered records and returns the error of the push.
+// It waits at most for the configured flush timeout, the push is continued in the background after a timeout.
+func (h *Handler) Flush() error {
+	timer := time.NewTimer(h.opts.FlushTimeout)
+	defer timer.Stop()
+
+	errCh := make(chan error, 1)
+	select {
+	case h.pusher.flushCh <- errCh:
+	case <-h.pusher.doneCh:
+		return nil
+	case <-timer.C:
+		return errors.New("flushing timed out")
+	}
+	select {
+	case err := <-errCh:
+		return err
+	case <-timer.C:
+		return errors.New("flushing timed out")
+	}
+}
+
+// Close pushes all buffered records and stops the handler (and all handlers derived with WithAttrs and WithGroup).
+// Failed pushes are not retried after Close. If the remaining records cannot be pushed within the configured
+// flush timeout, the push is aborted and they are dropped. Records handled after Close are dropped.
+func (h *Handler) Close() error {
+	p := h.pusher
+	p.closeOnce.Do(func() {
+		close(p.closeCh)
+	})
+
+	timer := time.NewTimer(h.opts.FlushTimeout)
+	defer timer.Stop()
+	select {
+	case <-p.doneCh:
+		return p.closeErr
+	case <-timer.C:
+		p.cancel()
+		<-p.doneCh
+		if p.closeErr != nil {
+			return fmt.Errorf("closing timed out: %w", p.closeErr)
+		}
+		return nil
+	}
+}
+
+// Dropped returns the number of records dropped because the buffer was full.
+func (h *Handler) Dropped() int64 {
+	return h.pusher.dropped.Load()
+}
+
+type entry struct {
+	time time.Time
+	line string
+}
+
+type stream struct {
+	labels  map[string]string
+	entries []entry
+}
+
+// pusher buffers entries by stream and is shared by all handlers derived with WithAttrs and WithGroup.
+type pusher struct {
+	url  string
+	opts *Options
+	// ctx is cancelled to abort pending pushes when Close times out
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	streams map[string]*stream
+	size    int
+	closed  bool
+	dropped atomic.Int64
+
+	// fullCh signals that the batch size was reached
+	fullCh    chan struct{}
+	flushCh   chan chan error
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	doneCh    chan struct{}
+	closeErr  error
+}
+
+func (p *pusher) add(labels map[string]string, e entry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+	if p.size >= p.opts.MaxBuffered {
+		p.dropped.Add(1)
+		return
+	}
+	if p.streams == nil {
+		p.streams = make(map[string]*stream)
+	}
+
+	key := streamKey(labels)
+	s, ok := p.streams[key]
+	if !ok {
+		s = &stream{labels: labels}
+		p.streams[key] = s
+	}
+	s.entries = append(s.entries, e)
+	p.size++
+
+	if p.size >= p.opts.BatchSize {
+		select {
+		case p.fullCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (p *pusher) run() {
+	defer close(p.doneCh)
+	defer p.cancel()
+
+	ticker := time.NewTicker(p.opts.BatchWait)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if n, err := p.push(); err != nil {
+				p.opts.OnError(n, err)
+			}
+		case <-p.fullCh:
+			if n, err := p.push(); err != nil {
+				p.opts.OnError(n, err)
+			}
+		case errCh := <-p.flushCh:
+			_, err := p.push()
+			errCh <- err
+		case <-p.closeCh:
+			p.mu.Lock()
+			p.closed = true
+			p.mu.Unlock()
+			_, p.closeErr = p.push()
+			return
+		}
+	}
+}
+
+func (p *pusher) take() []*stream {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	streams := make([]*stream, 0, len(p.streams))
+	for _, s := range p.streams {
+		streams = append(streams, s)
+	}
+	p.streams = nil
+	p.size = 0
+	return streams
+}
+
+type pushRequest struct {
+	Streams []pushStream `json:"streams"`
+}
+
+type pushStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// push pushes all buffered records and returns the number of records and the error of a failed push.
+func (p *pusher) push() (int, error) {
+	streams := p.take()
+	if len(streams) == 0 {
+		return 0, nil
+	}
+
+	records := 0
+	req := pushRequest{Streams: make([]pushStream, len(streams))}
+	for i, s := range streams {
+		records += len(s.entries)
+		values := make([][2]string, len(s.entries))
+		for j, e := range s.entries {
+			values[j] = [2]string{strconv.FormatInt(e.time.UnixNano(), 10), e.line}
+		}
+		req.Streams[i] = pushStream{Stream: s.labels, Values: values}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return records, fmt.Errorf("encoding push request: %w", err)
+	}
+
+	backoff := p.opts.MinBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := p.send(body)
+		if err == nil {
+			return records, nil
+		}
+		if !retry || attempt >= p.opts.MaxRetries || p.closing() {
+			return records, fmt.Errorf("pushing %d streams: %w", len(streams), err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-p.closeCh:
+			// Retry a last time without waiting, Close should not be delayed by the backoff
+			timer.Stop()
+		}
+		backoff = min(backoff*2, p.opts.MaxBackoff)
+	}
+}
+
+func (p *pusher) closing() bool {
+	select {
+	case <-p.closeCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// send sends a push request and reports whether a failed request should be retried.
+func (p *pusher) send(body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for k, v := range p.opts.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.opts.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = errors.New(resp.Status + ": " + strings.TrimSpace(string(msg)))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode/100 == 5, err
+}
+
+// streamKey returns a unique key of a label set.
+func streamKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(strconv.Quote(labels[k]))
+		sb.WriteByte(',')
+	}
+	return sb.String()
+}
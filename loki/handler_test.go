@@ -0,0 +1,257 @@
+package loki_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/loki"
+)
+
+type pushRequest struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+}
+
+type server struct {
+	mu       sync.Mutex
+	requests []pushRequest
+	failures int
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req pushRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.requests = append(s.requests, req)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestHandler(t *testing.T) {
+	srv := &server{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	h := loki.NewHandler(ts.URL, &loki.Options{
+		Labels:       []string{"app", "env"},
+		StaticLabels: map[string]string{"job": "test"},
+		BatchWait:    time.Hour,
+	})
+	logger := slog.New(h).With("app", "api")
+
+	logger.Info("Started", "env", "prod", "port", 8080)
+	logger.WithGroup("req").Warn("Slow request", "path", "/users")
+	logger.Debug("Not logged")
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(srv.requests) != 1 {
+		t.Fatalf("expected 1 push request, got %d", len(srv.requests))
+	}
+	lines := make(map[string]string)
+	for _, s := range srv.requests[0].Streams {
+		if s.Stream["job"] != "test" || s.Stream["app"] != "api" {
+			t.Errorf("unexpected labels: %v", s.Stream)
+		}
+		for _, v := range s.Values {
+			lines[s.Stream["level"]+"/"+s.Stream["env"]] = v[1]
+		}
+	}
+
+	want := map[string]string{
+		"info/prod": `msg=Started port=8080`,
+		"warn/":     `msg="Slow request" req.path=/users`,
+	}
+	for k, line := range want {
+		if lines[k] != line {
+			t.Errorf("expected line %q for stream %s, got %q", line, k, lines[k])
+		}
+	}
+	if len(lines) != len(want) {
+		t.Errorf("expected %d streams, got %v", len(want), lines)
+	}
+}
+
+func TestHandler_JSON(t *testing.T) {
+	srv := &server{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	h := loki.NewHandler(ts.URL, &loki.Options{Format: loki.FormatJSON, BatchWait: time.Hour})
+	slog.New(h).Info("test", slog.Group("g", "key", "val"))
+	if err := h.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := srv.requests[0].Streams[0].Values[0][1]
+	if want := `{"msg":"test","g.key":"val"}`; got != want {
+		t.Fatalf("expected line %s, got %s", want, got)
+	}
+}
+
+func TestHandler_Retain(t *testing.T) {
+	srv := &server{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	h := loki.NewHandler(ts.URL, &loki.Options{BatchWait: time.Hour})
+	slog.New(h).With(slogutils.Retain("30d")).Info("test", slogutils.Channel("audit"), "key", "val")
+	if err := h.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := srv.requests[0].Streams[0]
+	if got := s.Stream[loki.RetainLabel]; got != "30d" {
+		t.Errorf("expected retain label 30d, got %q", got)
+	}
+	if want, got := `msg=test key=val`, s.Values[0][1]; got != want {
+		t.Fatalf("expected line %s, got %s", want, got)
+	}
+}
+
+func TestHandler_BatchSize(t *testing.T) {
+	srv := &server{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	h := loki.NewHandler(ts.URL, &loki.Options{BatchSize: 2, BatchWait: time.Hour})
+	defer h.Close()
+
+	logger := slog.New(h)
+	logger.Info("one")
+	logger.Info("two")
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		srv.mu.Lock()
+		n := len(srv.requests)
+		srv.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected batch to be pushed after reaching batch size")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHandler_Retry(t *testing.T) {
+	srv := &server{failures: 2}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	h := loki.NewHandler(ts.URL, &loki.Options{BatchWait: time.Hour, MinBackoff: time.Millisecond})
+	defer h.Close()
+
+	slog.New(h).InfoContext(context.Background(), "test")
+	if err := h.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(srv.requests) != 1 {
+		t.Fatalf("expected push after retries, got %d requests", len(srv.requests))
+	}
+
+	srv.failures = 10
+	slog.New(h).Info("test")
+	if err := h.Flush(); err == nil {
+		t.Fatal("expected error after max retries")
+	}
+}
+
+func TestHandler_MaxBuffered(t *testing.T) {
+	srv := &server{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	h := loki.NewHandler(ts.URL, &loki.Options{BatchWait: time.Hour, MaxBuffered: 2})
+	logger := slog.New(h)
+	for i := 0; i < 5; i++ {
+		logger.Info("test", "i", i)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(srv.requests[0].Streams[0].Values); got != 2 {
+		t.Errorf("expected 2 pushed records, got %d", got)
+	}
+	if got := h.Dropped(); got != 3 {
+		t.Errorf("expected 3 dropped records, got %d", got)
+	}
+}
+
+func TestHandler_CloseTimeout(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	h := loki.NewHandler(ts.URL, &loki.Options{BatchWait: time.Hour, FlushTimeout: 50 * time.Millisecond})
+	slog.New(h).Info("test")
+
+	start := time.Now()
+	if err := h.Close(); err == nil {
+		t.Fatal("expected error after timeout")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("expected Close to return after flush timeout, took %s", d)
+	}
+}
+
+func TestHandler_OnError(t *testing.T) {
+	srv := &server{failures: 100}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	dropped := make(chan int, 1)
+	h := loki.NewHandler(ts.URL, &loki.Options{
+		BatchSize:  2,
+		BatchWait:  time.Hour,
+		MaxRetries: 1,
+		MinBackoff: time.Millisecond,
+		OnError: func(records int, err error) {
+			dropped <- records
+		},
+	})
+	defer h.Close()
+
+	logger := slog.New(h)
+	logger.Info("one")
+	logger.Info("two")
+
+	select {
+	case n := <-dropped:
+		if n != 2 {
+			t.Fatalf("expected 2 records of failed push, got %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected OnError to be called")
+	}
+}
@@ -0,0 +1,164 @@
+// Package metrics provides a handler counting log records per level and component.
+// The counts can be exposed with a metrics library, e.g. as a Prometheus collector (see adapter/prometheus).
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Options are options for a Handler.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// ComponentKey is the key of a top-level attribute (e.g. "component") that is used to count records per component.
+	// If ComponentKey is empty, records are only counted per level.
+	ComponentKey string
+}
+
+// Count is the number of records for a level and component.
+type Count struct {
+	Level slog.Level
+	// Component is the value of the component attribute, empty if the record has no component
+	Component string
+	Value     uint64
+}
+
+type countKey struct {
+	level     slog.Level
+	component string
+}
+
+// counters are shared by all handlers derived with WithAttrs and WithGroup.
+type counters struct {
+	m sync.Map // map[countKey]*atomic.Uint64
+}
+
+func (c *counters) inc(key countKey) {
+	v, ok := c.m.Load(key)
+	if !ok {
+		v, _ = c.m.LoadOrStore(key, new(atomic.Uint64))
+	}
+	v.(*atomic.Uint64).Add(1)
+}
+
+// Handler counts records per level (and component) and delegates to another handler.
+// Only records that are enabled by the wrapped handler are counted.
+type Handler struct {
+	next         slog.Handler
+	componentKey string
+	counters     *counters
+
+	// component is the component from attributes of the handler
+	component string
+	// grouped is true if a group was opened, so attributes are not at the top level anymore
+	grouped bool
+}
+
+var (
+	_ slog.Handler           = (*Handler)(nil)
+	_ slogutils.AttrsEnabler = (*Handler)(nil)
+	_ slogutils.Wrapper      = (*Handler)(nil)
+)
+
+// NewHandler creates a new Handler wrapping the given handler. Options can be nil.
+func NewHandler(next slog.Handler, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &Handler{
+		next:         next,
+		componentKey: opts.ComponentKey,
+		counters:     &counters{},
+	}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *Handler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *Handler) EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+	return slogutils.EnabledForAttrs(ctx, h.next, level, attrs)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	component := h.component
+	if h.componentKey != "" && !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == h.componentKey {
+				component = a.Value.Resolve().String()
+				return false
+			}
+			return true
+		})
+	}
+	h.counters.inc(countKey{level: r.Level, component: component})
+
+	return h.next.Handle(ctx, r)
+}
+
+// ComponentKey returns the key of the component attribute.
+func (h *Handler) ComponentKey() string {
+	return h.componentKey
+}
+
+// Counts returns the current counts sorted by level and component.
+func (h *Handler) Counts() []Count {
+	var counts []Count
+	h.counters.m.Range(func(k, v any) bool {
+		key := k.(countKey)
+		counts = append(counts, Count{
+			Level:     key.level,
+			Component: key.component,
+			Value:     v.(*atomic.Uint64).Load(),
+		})
+		return true
+	})
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Level != counts[j].Level {
+			return counts[i].Level < counts[j].Level
+		}
+		return counts[i].Component < counts[j].Component
+	})
+	return counts
+}
+
+// LevelName returns a lowercase name of a level for metric labels, e.g. "info" or "trace" for slogutils.LevelTrace.
+func LevelName(level slog.Level) string {
+	return slogutils.LevelName(level)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h // Copy handler
+	h2.next = h.next.WithAttrs(attrs)
+	if h.componentKey != "" && !h.grouped {
+		for _, a := range attrs {
+			if a.Key == h.componentKey {
+				h2.component = a.Value.Resolve().String()
+			}
+		}
+	}
+	return &h2
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h // Copy handler
+	h2.next = h.next.WithGroup(name)
+	h2.grouped = true
+	return &h2
+}
@@ -0,0 +1,46 @@
+package metrics_test
+
+import (
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/metrics"
+)
+
+func TestHandler(t *testing.T) {
+	h := metrics.NewHandler(slog.NewTextHandler(io.Discard, nil), &metrics.Options{ComponentKey: "component"})
+	logger := slog.New(h)
+
+	logger.Info("test")
+	logger.Debug("not counted")
+	logger.Error("test", "component", "db")
+	db := logger.With("component", "db")
+	db.Error("test")
+	db.WithGroup("g").Error("test", "component", "ignored")
+	logger.Warn("test", "component", "api")
+
+	want := []metrics.Count{
+		{Level: slog.LevelInfo, Value: 1},
+		{Level: slog.LevelWarn, Component: "api", Value: 1},
+		{Level: slog.LevelError, Component: "db", Value: 3},
+	}
+	if got := h.Counts(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected counts %v, got %v", want, got)
+	}
+}
+
+func TestLevelName(t *testing.T) {
+	for level, want := range map[slog.Level]string{
+		slogutils.LevelTrace: "trace",
+		slog.LevelDebug:      "debug",
+		slog.LevelError:      "error",
+		slog.LevelError + 2:  "error+2",
+	} {
+		if got := metrics.LevelName(level); got != want {
+			t.Errorf("expected %s for level %d, got %s", want, level, got)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package slogutils
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LoggerKey is the key of the name attribute of loggers returned by Named.
+const LoggerKey = "logger"
+
+// defaultScope matches all names in a level spec.
+const defaultScope = "*"
+
+// ParseLevelSpec parses a comma separated list of scope levels like "db=debug,http=warn,*=info".
+// The scope "*" sets the default level, a level without a scope (e.g. "info") is an alias for "*=info".
+// Levels are parsed with ParseLevel.
+func ParseLevelSpec(spec string) (map[string]slog.Level, error) {
+	levels := make(map[string]slog.Level)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		scope, levelName, ok := strings.Cut(entry, "=")
+		if !ok {
+			scope, levelName = defaultScope, entry
+		}
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			return nil, fmt.Errorf("invalid level spec entry %q: missing scope", entry)
+		}
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid level spec entry %q: %w", entry, err)
+		}
+		levels[scope] = level
+	}
+	return levels, nil
+}
+
+// Registry hands out named loggers with a level per scope. Names are hierarchical with dots as separators,
+// so the level of scope "db" applies to "db.pool" unless "db.pool" has its own level.
+// The handler should be configured with the lowest level that any scope might use (e.g. LevelTrace),
+// since records are filtered by the scope level first.
+type Registry struct {
+	// handler is the base handler or nil to use the handler of slog.Default()
+	handler slog.Handler
+
+	mu           sync.Mutex
+	spec         map[string]slog.Level
+	defaultLevel slog.Level
+	levels       map[string]*slog.LevelVar
+}
+
+// NewRegistry creates a registry for loggers using the given handler.
+// If handler is nil, loggers use the handler of slog.Default() at the time of logging.
+func NewRegistry(handler slog.Handler) *Registry {
+	return &Registry{
+		handler:      handler,
+		defaultLevel: slog.LevelInfo,
+		levels:       make(map[string]*slog.LevelVar),
+	}
+}
+
+// DefaultRegistry is the registry used by Named and SetLevelSpec. It uses the handler of slog.Default().
+var DefaultRegistry = NewRegistry(nil)
+
+// Named returns a logger with the given name from the DefaultRegistry.
+func Named(name string) *slog.Logger {
+	return DefaultRegistry.Named(name)
+}
+
+// SetLevelSpec sets the levels of the DefaultRegistry from a spec (see ParseLevelSpec),
+// e.g. from an environment variable or flag.
+func SetLevelSpec(spec string) error {
+	return DefaultRegistry.SetLevelSpec(spec)
+}
+
+// Named returns a logger with the name added as LoggerKey attribute and the level of the scope of the name.
+func (r *Registry) Named(name string) *slog.Logger {
+	h := &namedHandler{
+		registry: r,
+		level:    r.LevelVar(name),
+		goas:     []GroupOrAttrs{{Attrs: []slog.Attr{slog.String(LoggerKey, name)}}},
+	}
+	return slog.New(h)
+}
+
+// LookupLevelVar returns the LevelVar of a name that was used by Named or LevelVar before, or nil otherwise.
+// It does not create entries for unknown names, so it can be used with untrusted input (e.g. for ScopedLevelHandler).
+func (r *Registry) LookupLevelVar(name string) *slog.LevelVar {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.levels[name]
+}
+
+// LevelVar returns the LevelVar of a name, which can be changed at runtime.
+// The LevelVar is created if the name is unknown. Changes are overwritten by SetLevelSpec.
+func (r *Registry) LevelVar(name string) *slog.LevelVar {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lv, ok := r.levels[name]
+	if !ok {
+		lv = new(slog.LevelVar)
+		lv.Set(r.levelFor(name))
+		r.levels[name] = lv
+	}
+	return lv
+}
+
+// SetLevelSpec sets the levels of all scopes from a spec (see ParseLevelSpec).
+// Scopes not mentioned in the spec get the default level of the spec (or slog.LevelInfo).
+func (r *Registry) SetLevelSpec(spec string) error {
+	levels, err := ParseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.defaultLevel = slog.LevelInfo
+	if level, ok := levels[defaultScope]; ok {
+		r.defaultLevel = level
+	}
+	r.spec = levels
+	for name, lv := range r.levels {
+		lv.Set(r.levelFor(name))
+	}
+	return nil
+}
+
+// levelFor resolves the level of a name from the spec, must be called with mu held.
+func (r *Registry) levelFor(name string) slog.Level {
+	for scope := name; scope != ""; {
+		if level, ok := r.spec[scope]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(scope, '.')
+		if i < 0 {
+			break
+		}
+		scope = scope[:i]
+	}
+	return r.defaultLevel
+}
+
+// namedHandler filters records by the level of a scope and delegates to the base handler of the registry.
+type namedHandler struct {
+	registry *Registry
+	level    *slog.LevelVar
+	goas     []GroupOrAttrs
+
+	// cached is the base handler with goas applied, it is updated if the default logger changes
+	cached atomic.Pointer[cachedHandler]
+}
+
+type cachedHandler struct {
+	// logger is the default logger the handler was built from or nil for the handler of the registry.
+	// Loggers are compared instead of handlers, since handlers are not necessarily comparable.
+	logger *slog.Logger
+	next   slog.Handler
+}
+
+var (
+	_ slog.Handler = (*namedHandler)(nil)
+	_ AttrsEnabler = (*namedHandler)(nil)
+	_ Wrapper      = (*namedHandler)(nil)
+)
+
+func (h *namedHandler) next() slog.Handler {
+	var logger *slog.Logger
+	base := h.registry.handler
+	if base == nil {
+		logger = slog.Default()
+	}
+	if c := h.cached.Load(); c != nil && c.logger == logger {
+		return c.next
+	}
+	if logger != nil {
+		base = logger.Handler()
+	}
+	next := ApplyGroupsAndAttrs(base, h.goas)
+	h.cached.Store(&cachedHandler{logger: logger, next: next})
+	return next
+}
+
+func (h *namedHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.next().Enabled(ctx, level)
+}
+
+func (h *namedHandler) EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+	return level >= h.level.Level() && EnabledForAttrs(ctx, h.next(), level, attrs)
+}
+
+// Unwrap returns the base handler with the name and attributes applied.
+func (h *namedHandler) Unwrap() slog.Handler {
+	return h.next()
+}
+
+func (h *namedHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next().Handle(ctx, r)
+}
+
+func (h *namedHandler) withGroupOrAttrs(goa GroupOrAttrs) *namedHandler {
+	h2 := &namedHandler{registry: h.registry, level: h.level}
+	h2.goas = make([]GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return h2
+}
+
+func (h *namedHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Attrs: attrs})
+}
+
+func (h *namedHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(GroupOrAttrs{Group: name})
+}
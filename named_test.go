@@ -0,0 +1,109 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestParseLevelSpec(t *testing.T) {
+	levels, err := slogutils.ParseLevelSpec("db=debug, http=warn,*=info")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if levels["db"] != slog.LevelDebug || levels["http"] != slog.LevelWarn || levels["*"] != slog.LevelInfo {
+		t.Fatalf("unexpected levels: %v", levels)
+	}
+
+	levels, err = slogutils.ParseLevelSpec("trace")
+	if err != nil || levels["*"] != slogutils.LevelTrace {
+		t.Fatalf("expected default level, got %v (%v)", levels, err)
+	}
+
+	for _, spec := range []string{"db=verbose", "=debug"} {
+		if _, err := slogutils.ParseLevelSpec(spec); err == nil {
+			t.Errorf("expected error for spec %q", spec)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	buf := new(bytes.Buffer)
+	r := slogutils.NewRegistry(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level:       slogutils.LevelTrace,
+		ReplaceAttr: drop(slog.TimeKey),
+	}))
+
+	db := r.Named("db")
+	pool := r.Named("db.pool")
+	httpLogger := r.Named("http")
+
+	if err := r.SetLevelSpec("db=debug,db.pool=trace,*=warn"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	db.Debug("query")
+	db.Log(context.Background(), slogutils.LevelTrace, "not logged")
+	pool.Log(context.Background(), slogutils.LevelTrace, "acquire")
+	httpLogger.Info("not logged")
+	httpLogger.WithGroup("req").Warn("slow", "path", "/")
+	r.Named("db.tx").Debug("begin")
+
+	want := strings.Join([]string{
+		`level=DEBUG msg=query logger=db`,
+		`level=DEBUG-4 msg=acquire logger=db.pool`,
+		`level=WARN msg=slow logger=http req.path=/`,
+		`level=DEBUG msg=begin logger=db.tx`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+
+	r.LookupLevelVar("http").Set(slog.LevelInfo)
+	if !httpLogger.Enabled(context.Background(), slog.LevelInfo) {
+		t.Fatal("expected level of scope to be changed at runtime")
+	}
+	if lv := r.LookupLevelVar("unknown"); lv != nil {
+		t.Fatalf("expected no LevelVar for unknown name, got %v", lv)
+	}
+}
+
+func TestNamed_defaultHandlerNotComparable(t *testing.T) {
+	prev := slog.Default()
+	defer slog.SetDefault(prev)
+
+	var records []string
+	slog.SetDefault(slog.New(recordingHandler{records: &records, attrs: []slog.Attr{}}))
+
+	logger := slogutils.NewRegistry(nil).Named("db")
+	logger.Info("first")
+	logger.Info("second")
+
+	if want, got := "first second", strings.Join(records, " "); want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+// recordingHandler is not comparable, since it holds a slice.
+type recordingHandler struct {
+	records *[]string
+	attrs   []slog.Attr
+}
+
+func (h recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	*h.records = append(*h.records, r.Message)
+	return nil
+}
+
+func (h recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return recordingHandler{records: h.records, attrs: append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)}
+}
+
+func (h recordingHandler) WithGroup(string) slog.Handler { return h }
@@ -0,0 +1,271 @@
+// Package observer provides an in-memory handler that records log records for assertions in tests.
+//
+//	handler, logs := observer.New(nil)
+//	logger := slog.New(handler)
+//	// ...
+//	if logs.FilterMessage("Connected").Len() != 1 {
+//		t.Error("expected connection to be logged")
+//	}
+package observer
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+// LoggedRecord is an observed record. The attributes of the record and the handler are resolved and extracted
+// to a list (nested in groups of the handler), so they can be compared directly.
+type LoggedRecord struct {
+	// Record has the time, level, message and PC of the record, but no attributes
+	Record slog.Record
+	Attrs  []slog.Attr
+}
+
+// AttrsMap returns the attributes as a map, groups are converted to nested maps.
+func (r LoggedRecord) AttrsMap() map[string]any {
+	return attrsMap(r.Attrs)
+}
+
+func attrsMap(attrs []slog.Attr) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		if a.Value.Kind() == slog.KindGroup {
+			m[a.Key] = attrsMap(a.Value.Group())
+			continue
+		}
+		m[a.Key] = a.Value.Any()
+	}
+	return m
+}
+
+// ObservedLogs is a concurrency-safe, ordered collection of observed records.
+type ObservedLogs struct {
+	mu      sync.RWMutex
+	maxLogs int
+	logs    []LoggedRecord
+}
+
+// Len returns the number of observed records.
+func (o *ObservedLogs) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return len(o.logs)
+}
+
+// All returns a copy of all observed records.
+func (o *ObservedLogs) All() []LoggedRecord {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	logs := make([]LoggedRecord, len(o.logs))
+	copy(logs, o.logs)
+	return logs
+}
+
+// TakeAll returns all observed records and removes them from the collection.
+func (o *ObservedLogs) TakeAll() []LoggedRecord {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	logs := o.logs
+	o.logs = nil
+	return logs
+}
+
+// AllUntimed returns a copy of all observed records with zero times for comparisons.
+func (o *ObservedLogs) AllUntimed() []LoggedRecord {
+	logs := o.All()
+	for i := range logs {
+		logs[i].Record.Time = time.Time{}
+	}
+	return logs
+}
+
+// Filter returns a collection with the records for which keep returns true.
+func (o *ObservedLogs) Filter(keep func(r LoggedRecord) bool) *ObservedLogs {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	var filtered []LoggedRecord
+	for _, r := range o.logs {
+		if keep(r) {
+			filtered = append(filtered, r)
+		}
+	}
+	return &ObservedLogs{logs: filtered}
+}
+
+// FilterLevel filters records with at least the given level.
+func (o *ObservedLogs) FilterLevel(level slog.Level) *ObservedLogs {
+	return o.Filter(func(r LoggedRecord) bool {
+		return r.Record.Level >= level
+	})
+}
+
+// FilterLevelExact filters records with exactly the given level.
+func (o *ObservedLogs) FilterLevelExact(level slog.Level) *ObservedLogs {
+	return o.Filter(func(r LoggedRecord) bool {
+		return r.Record.Level == level
+	})
+}
+
+// FilterMessage filters records with the given message.
+func (o *ObservedLogs) FilterMessage(msg string) *ObservedLogs {
+	return o.Filter(func(r LoggedRecord) bool {
+		return r.Record.Message == msg
+	})
+}
+
+// FilterMessageSnippet filters records with a message containing the snippet.
+func (o *ObservedLogs) FilterMessageSnippet(snippet string) *ObservedLogs {
+	return o.Filter(func(r LoggedRecord) bool {
+		return strings.Contains(r.Record.Message, snippet)
+	})
+}
+
+// FilterAttr filters records with an attribute equal to attr. The key of attr can be qualified by group names
+// separated by dots ("request.id") to match attributes in groups.
+func (o *ObservedLogs) FilterAttr(attr slog.Attr) *ObservedLogs {
+	return o.Filter(func(r LoggedRecord) bool {
+		a, ok := findAttr(r.Attrs, attr.Key)
+		return ok && attrValueEqual(a.Value, attr.Value)
+	})
+}
+
+// FilterAttrKey filters records with an attribute with the given key, which can be qualified by group names
+// like for FilterAttr.
+func (o *ObservedLogs) FilterAttrKey(key string) *ObservedLogs {
+	return o.Filter(func(r LoggedRecord) bool {
+		_, ok := findAttr(r.Attrs, key)
+		return ok
+	})
+}
+
+func findAttr(attrs []slog.Attr, key string) (slog.Attr, bool) {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a, true
+		}
+		if rest, ok := strings.CutPrefix(key, a.Key+"."); ok && a.Value.Kind() == slog.KindGroup {
+			if ga, ok := findAttr(a.Value.Group(), rest); ok {
+				return ga, true
+			}
+		}
+	}
+	return slog.Attr{}, false
+}
+
+func attrValueEqual(v1, v2 slog.Value) bool {
+	if v1.Kind() == slog.KindAny && v2.Kind() == slog.KindAny {
+		// Compare with reflect to not panic on uncomparable types
+		return reflect.DeepEqual(v1.Any(), v2.Any())
+	}
+	return v1.Equal(v2)
+}
+
+func (o *ObservedLogs) add(r LoggedRecord) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.logs = append(o.logs, r)
+	if o.maxLogs > 0 && len(o.logs) > o.maxLogs {
+		o.logs = o.logs[len(o.logs)-o.maxLogs:]
+	}
+}
+
+// HandlerOptions are options for a Handler.
+// A zero HandlerOptions consists entirely of default values.
+type HandlerOptions struct {
+	// Level reports the minimum record level that will be observed.
+	// If Level is nil, the handler assumes slog.LevelInfo.
+	Level slog.Leveler
+
+	// MaxLogs is the maximum number of observed records, the oldest records are dropped.
+	// If MaxLogs is zero, the number of records is not limited.
+	MaxLogs int
+}
+
+// Handler records log records in ObservedLogs.
+type Handler struct {
+	level slog.Leveler
+	logs  *ObservedLogs
+	goas  []slogutils.GroupOrAttrs
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// New creates a handler and the collection of observed records. Options can be nil.
+// Create a new handler per test, so tests running in parallel do not observe records of each other.
+func New(opts *HandlerOptions) (*Handler, *ObservedLogs) {
+	if opts == nil {
+		opts = &HandlerOptions{}
+	}
+	var level slog.Leveler = slog.LevelInfo
+	if opts.Level != nil {
+		level = opts.Level
+	}
+
+	logs := &ObservedLogs{maxLogs: opts.MaxLogs}
+	return &Handler{level: level, logs: logs}, logs
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	h.logs.add(LoggedRecord{
+		Record: slog.NewRecord(r.Time, r.Level, r.Message, r.PC),
+		Attrs:  buildAttrs(h.goas, slogutils.RecordAttrs(r)),
+	})
+	return nil
+}
+
+// buildAttrs nests the resolved record attributes in the groups of the handler, empty groups are omitted.
+func buildAttrs(goas []slogutils.GroupOrAttrs, recordAttrs []slog.Attr) []slog.Attr {
+	resolved := make([]slogutils.GroupOrAttrs, len(goas))
+	for i, goa := range goas {
+		resolved[i] = slogutils.GroupOrAttrs{Group: goa.Group, Attrs: appendResolved(nil, goa.Attrs)}
+	}
+	return slogutils.NestAttrs(resolved, appendResolved(nil, recordAttrs))
+}
+
+func appendResolved(attrs []slog.Attr, as []slog.Attr) []slog.Attr {
+	for _, a := range as {
+		a = slogutils.ResolveAttr(a, 0)
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs) *Handler {
+	h2 := *h // Copy handler
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs})
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name})
+}
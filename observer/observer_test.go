@@ -0,0 +1,82 @@
+package observer_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/observer"
+)
+
+type lazyValue string
+
+func (v lazyValue) LogValue() slog.Value {
+	return slog.StringValue(string(v))
+}
+
+func TestHandler(t *testing.T) {
+	handler, logs := observer.New(&observer.HandlerOptions{Level: slog.LevelDebug})
+	logger := slog.New(handler)
+
+	logger.Debug("debug", "key", lazyValue("val"))
+	logger.With("a", 1).WithGroup("g").With("b", 2).WithGroup("h").Info("info", "c", 3)
+	logger.WithGroup("empty").Warn("warn")
+	logger.Error("error", slogutils.Err(errors.New("fail")))
+	logger.Log(context.Background(), slogutils.LevelTrace, "not observed")
+
+	if logs.Len() != 4 {
+		t.Fatalf("expected 4 records, got %d", logs.Len())
+	}
+
+	info := logs.FilterMessage("info").All()[0]
+	want := map[string]any{"a": int64(1), "g": map[string]any{"b": int64(2), "h": map[string]any{"c": int64(3)}}}
+	if got := info.AttrsMap(); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected attrs %v, got %v", want, got)
+	}
+
+	if got := logs.FilterMessage("warn").All()[0].Attrs; len(got) != 0 {
+		t.Errorf("expected empty group to be omitted, got %v", got)
+	}
+
+	tests := []struct {
+		name string
+		logs *observer.ObservedLogs
+		want int
+	}{
+		{name: "level", logs: logs.FilterLevel(slog.LevelWarn), want: 2},
+		{name: "level exact", logs: logs.FilterLevelExact(slog.LevelDebug), want: 1},
+		{name: "message snippet", logs: logs.FilterMessageSnippet("o"), want: 2},
+		{name: "resolved attr", logs: logs.FilterAttr(slog.String("key", "val")), want: 1},
+		{name: "grouped attr", logs: logs.FilterAttr(slog.Int("g.h.c", 3)), want: 1},
+		{name: "any attr", logs: logs.FilterAttr(slogutils.Err(errors.New("fail"))), want: 1},
+		{name: "attr key", logs: logs.FilterAttrKey("g.b"), want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.logs.Len(); got != tt.want {
+				t.Errorf("expected %d records, got %d", tt.want, got)
+			}
+		})
+	}
+
+	if got := len(logs.TakeAll()); got != 4 || logs.Len() != 0 {
+		t.Fatalf("expected all records to be taken, got %d and %d remaining", got, logs.Len())
+	}
+}
+
+func TestHandler_MaxLogs(t *testing.T) {
+	handler, logs := observer.New(&observer.HandlerOptions{MaxLogs: 2})
+	logger := slog.New(handler)
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Info("three")
+
+	all := logs.AllUntimed()
+	if len(all) != 2 || all[0].Record.Message != "two" || !all[0].Record.Time.IsZero() {
+		t.Fatalf("expected the last 2 records without time, got %v", all)
+	}
+}
@@ -0,0 +1,115 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// DeprecatedKey is the key for the feature attribute of a deprecation record.
+const DeprecatedKey = "deprecated"
+
+// minPruneSize is the minimum number of keys before expired keys are removed from a registry.
+const minPruneSize = 64
+
+type onceRegistry struct {
+	mu sync.Mutex
+	// seen maps keys to the time until they are suppressed, the zero time suppresses a key forever
+	seen map[string]time.Time
+	// pruneAt is the number of keys at which expired keys are removed, so keys of OnceEvery do not accumulate
+	pruneAt int
+}
+
+func newOnceRegistry() *onceRegistry {
+	return &onceRegistry{seen: make(map[string]time.Time), pruneAt: minPruneSize}
+}
+
+var (
+	defaultOnceRegistry = newOnceRegistry()
+	deprecationRegistry = newOnceRegistry()
+)
+
+func (r *onceRegistry) allow(key string, interval time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if until, ok := r.seen[key]; ok && (until.IsZero() || now.Before(until)) {
+		return false
+	}
+
+	var until time.Time
+	if interval > 0 {
+		until = now.Add(interval)
+	}
+	r.seen[key] = until
+
+	if len(r.seen) >= r.pruneAt {
+		r.prune(now)
+	}
+	return true
+}
+
+// prune removes expired keys. The next pruning happens after the number of keys doubled, so it is amortized.
+func (r *onceRegistry) prune(now time.Time) {
+	for key, until := range r.seen {
+		if !until.IsZero() && !now.Before(until) {
+			delete(r.seen, key)
+		}
+	}
+	r.pruneAt = max(2*len(r.seen), minPruneSize)
+}
+
+func (r *onceRegistry) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seen = make(map[string]time.Time)
+	r.pruneAt = minPruneSize
+}
+
+// Once reports whether the given key is seen for the first time in this process.
+// It can be used to guard a log call that should be emitted at most once:
+//
+//	if slogutils.Once("config.legacy-format") {
+//		logger.Warn("Legacy config format detected")
+//	}
+//
+// Keys are remembered for the lifetime of the process, so they should not contain dynamic content.
+func Once(key string) bool {
+	return defaultOnceRegistry.allow(key, 0)
+}
+
+// OnceEvery reports whether the given key was not seen within the last interval.
+// It is like Once, but allows a record to be emitted again after the interval passed.
+// Keys are forgotten after the interval passed, so they can contain dynamic content like a user ID.
+func OnceEvery(key string, interval time.Duration) bool {
+	return defaultOnceRegistry.allow(key, interval)
+}
+
+// ResetOnce forgets all keys seen by Once, OnceEvery and Deprecation.
+// This is mostly useful in tests.
+func ResetOnce() {
+	defaultOnceRegistry.reset()
+	deprecationRegistry.reset()
+}
+
+// Deprecation logs a warning about the usage of a deprecated feature with the logger from the context.
+// The warning is emitted at most once per feature and process, so libraries can call it on every use.
+// Features are tracked separately from keys of Once and OnceEvery.
+func Deprecation(ctx context.Context, feature, msg string, args ...any) {
+	logger := FromContext(ctx)
+	if !logger.Enabled(ctx, slog.LevelWarn) || !deprecationRegistry.allow(feature, 0) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers and Deprecation
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, msg, pcs[0])
+	r.AddAttrs(slog.String(DeprecatedKey, feature))
+	r.Add(args...)
+	_ = logger.Handler().Handle(ctx, r)
+}
@@ -0,0 +1,93 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestOnce(t *testing.T) {
+	slogutils.ResetOnce()
+
+	if !slogutils.Once("test") {
+		t.Fatal("first call should return true")
+	}
+	if slogutils.Once("test") {
+		t.Fatal("second call should return false")
+	}
+	if !slogutils.Once("other") {
+		t.Fatal("first call with other key should return true")
+	}
+}
+
+func TestOnceEvery(t *testing.T) {
+	slogutils.ResetOnce()
+
+	if !slogutils.OnceEvery("test", 10*time.Millisecond) {
+		t.Fatal("first call should return true")
+	}
+	if slogutils.OnceEvery("test", 10*time.Millisecond) {
+		t.Fatal("second call within interval should return false")
+	}
+
+	time.Sleep(15 * time.Millisecond)
+
+	if !slogutils.OnceEvery("test", 10*time.Millisecond) {
+		t.Fatal("call after interval should return true")
+	}
+}
+
+func TestOnceEvery_expiredKeysAreRemoved(t *testing.T) {
+	slogutils.ResetOnce()
+
+	for i := 0; i < 1000; i++ {
+		slogutils.OnceEvery(fmt.Sprintf("user-%d", i), time.Nanosecond)
+	}
+	slogutils.Once("static")
+
+	if n := slogutils.OnceKeys(); n > 100 {
+		t.Fatalf("expected expired keys to be removed, got %d keys", n)
+	}
+	if slogutils.Once("static") {
+		t.Fatal("keys of Once should not expire")
+	}
+}
+
+func TestDeprecation(t *testing.T) {
+	slogutils.ResetOnce()
+
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		AddSource: true,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.TimeKey:
+				return slog.Attr{}
+			case slog.SourceKey:
+				src := a.Value.Any().(*slog.Source)
+				return slog.String(slog.SourceKey, fmt.Sprintf("%s:%d", filepath.Base(src.File), src.Line))
+			}
+			return a
+		},
+	}))
+	ctx := slogutils.WithLogger(context.Background(), logger)
+
+	// Keys of Once do not suppress deprecations of the same name
+	slogutils.Once("v1-api")
+
+	_, _, line, _ := runtime.Caller(0)
+	slogutils.Deprecation(ctx, "v1-api", "The v1 API is deprecated", "use", "v2")
+	slogutils.Deprecation(ctx, "v1-api", "The v1 API is deprecated", "use", "v2")
+
+	want := fmt.Sprintf("level=WARN source=once_test.go:%d msg=\"The v1 API is deprecated\" deprecated=v1-api use=v2\n", line+1)
+	if buf.String() != want {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, buf.String())
+	}
+}
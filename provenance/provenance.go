@@ -0,0 +1,231 @@
+// Package provenance helps to debug handler chains by tracking which handler added, rewrote or removed an attribute.
+//
+// Wrap each handler of interest with Stage and the final handler with Annotate:
+//
+//	var h slog.Handler = slogutils.NewCLIHandler(os.Stderr, nil)
+//	h = provenance.Annotate(h)
+//	h = provenance.Stage("redact", redact.NewHandler(h, redactOpts))
+//	h = provenance.Stage("enrich", enrichHandler(h))
+//
+// Every record that was changed by one of the stages gets an additional top-level "provenance" group that describes
+// the changes per attribute key, e.g. provenance.user.email="rewritten by redact".
+// This is meant as a debug mode, since it compares all attributes of every record between each stage.
+package provenance
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Key is the group key of the provenance annotation.
+const Key = "provenance"
+
+type contextKey int
+
+const (
+	stateKey contextKey = iota
+)
+
+// state is passed from stage to stage in the context of Handle.
+type state struct {
+	from     string
+	snapshot *snapshot
+	changes  *changes
+}
+
+type stage struct {
+	name string
+	// base is the wrapped handler without groups and attributes of this stage
+	base slog.Handler
+	// next is the wrapped handler with groups and attributes of this stage applied
+	next     slog.Handler
+	annotate bool
+	goas     []slogutils.GroupOrAttrs
+	// prefix caches the handler with the provenance group before goas, it is only used by Annotate
+	prefix *slogutils.PrefixCache
+}
+
+var (
+	_ slog.Handler           = (*stage)(nil)
+	_ slogutils.AttrsEnabler = (*stage)(nil)
+	_ slogutils.Wrapper      = (*stage)(nil)
+)
+
+// Stage wraps a handler and attributes all changes to attributes made by the handler (and the handlers it wraps,
+// up to the next stage) to the given name.
+func Stage(name string, next slog.Handler) slog.Handler {
+	return &stage{name: name, base: next, next: next}
+}
+
+// Annotate wraps the final handler of a chain and adds the provenance group to records that were changed.
+// The group is added at the top level, before attributes and groups of the handler, so keys are always complete.
+func Annotate(next slog.Handler) slog.Handler {
+	return &stage{base: next, next: next, annotate: true}
+}
+
+func (s *stage) Enabled(ctx context.Context, level slog.Level) bool {
+	return s.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (s *stage) Unwrap() slog.Handler {
+	return s.next
+}
+
+func (s *stage) EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+	return slogutils.EnabledForAttrs(ctx, s.next, level, attrs)
+}
+
+func (s *stage) Handle(ctx context.Context, r slog.Record) error {
+	snap := s.snapshot(r)
+
+	chg := &changes{}
+	if prev, ok := ctx.Value(stateKey).(*state); ok {
+		chg = prev.changes.clone()
+		chg.diff(prev.from, prev.snapshot, snap)
+	}
+
+	if s.annotate {
+		switch {
+		case len(chg.keys) == 0:
+			return s.next.Handle(ctx, r)
+		case len(s.goas) == 0:
+			return s.next.Handle(ctx, slogutils.CloneRecordWithAttrs(r, chg.group()))
+		}
+		return s.prefix.Handler([]slog.Attr{chg.group()}).Handle(ctx, r)
+	}
+
+	ctx = context.WithValue(ctx, stateKey, &state{from: s.name, snapshot: snap, changes: chg})
+	return s.next.Handle(ctx, r)
+}
+
+func (s *stage) withGroupOrAttrs(goa slogutils.GroupOrAttrs, next slog.Handler) *stage {
+	s2 := *s // Copy handler
+	s2.next = next
+	s2.goas = make([]slogutils.GroupOrAttrs, len(s.goas)+1)
+	copy(s2.goas, s.goas)
+	s2.goas[len(s2.goas)-1] = goa
+	if s.annotate {
+		s2.prefix = slogutils.NewPrefixCache(s.base, s2.goas)
+	}
+	return &s2
+}
+
+func (s *stage) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return s
+	}
+	return s.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs}, s.next.WithAttrs(attrs))
+}
+
+func (s *stage) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return s
+	}
+	return s.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name}, s.next.WithGroup(name))
+}
+
+// snapshot is the flattened list of attributes of a record including the attributes of the handler.
+type snapshot struct {
+	keys   []string
+	values map[string]slog.Value
+}
+
+func (s *stage) snapshot(r slog.Record) *snapshot {
+	snap := &snapshot{values: make(map[string]slog.Value)}
+
+	prefix := ""
+	for _, goa := range s.goas {
+		if goa.Group != "" {
+			prefix += goa.Group + "."
+			continue
+		}
+		for _, a := range goa.Attrs {
+			snap.add(prefix, slogutils.ResolveAttr(a, 0))
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		snap.add(prefix, slogutils.ResolveAttr(a, 0))
+		return true
+	})
+
+	return snap
+}
+
+func (snap *snapshot) add(prefix string, a slog.Attr) {
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	v := a.Value
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			snap.add(prefix, ga)
+		}
+		return
+	}
+
+	key := prefix + a.Key
+	if _, exists := snap.values[key]; !exists {
+		snap.keys = append(snap.keys, key)
+	}
+	snap.values[key] = v
+}
+
+// changes collects descriptions of changes per attribute key in order of appearance.
+type changes struct {
+	keys         []string
+	descriptions map[string][]string
+}
+
+func (c *changes) clone() *changes {
+	c2 := &changes{
+		keys:         append([]string(nil), c.keys...),
+		descriptions: make(map[string][]string, len(c.descriptions)),
+	}
+	for k, v := range c.descriptions {
+		c2.descriptions[k] = append([]string(nil), v...)
+	}
+	return c2
+}
+
+func (c *changes) add(key, description string) {
+	if c.descriptions == nil {
+		c.descriptions = make(map[string][]string)
+	}
+	if _, exists := c.descriptions[key]; !exists {
+		c.keys = append(c.keys, key)
+	}
+	c.descriptions[key] = append(c.descriptions[key], description)
+}
+
+func (c *changes) diff(name string, before, after *snapshot) {
+	for _, key := range after.keys {
+		prevValue, existed := before.values[key]
+		switch {
+		case !existed:
+			c.add(key, "added by "+name)
+		case !prevValue.Equal(after.values[key]):
+			c.add(key, "rewritten by "+name)
+		}
+	}
+	for _, key := range before.keys {
+		if _, exists := after.values[key]; !exists {
+			c.add(key, "removed by "+name)
+		}
+	}
+}
+
+func (c *changes) group() slog.Attr {
+	attrs := make([]any, 0, len(c.keys))
+	for _, key := range c.keys {
+		attrs = append(attrs, slog.String(key, strings.Join(c.descriptions[key], ", ")))
+	}
+	return slog.Group(Key, attrs...)
+}
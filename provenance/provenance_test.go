@@ -0,0 +1,132 @@
+package provenance_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils/provenance"
+)
+
+func TestStage(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "added attribute",
+			f: func(l *slog.Logger) {
+				l.Info("test", "key", "val")
+			},
+			want: `level=INFO msg=test key=val host=local provenance.host="added by enrich"`,
+		},
+		{
+			name: "rewritten and removed attributes",
+			f: func(l *slog.Logger) {
+				l.Info("test", "password", "secret", "token", "abc")
+			},
+			want: `level=INFO msg=test password=*** host=local provenance.host="added by enrich" provenance.password="rewritten by redact" provenance.token="removed by redact"`,
+		},
+		{
+			name: "attributes of handler and groups",
+			f: func(l *slog.Logger) {
+				l.With("password", "secret").WithGroup("g").Info("test", "password", "secret")
+			},
+			want: `level=INFO msg=test provenance.g.host="added by enrich" provenance.password="rewritten by redact" provenance.g.password="rewritten by redact" password=*** g.password=*** g.host=local`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			var h slog.Handler = slog.NewTextHandler(buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+			h = provenance.Annotate(h)
+			h = provenance.Stage("redact", &redactHandler{next: h})
+			h = provenance.Stage("enrich", &enrichHandler{next: h})
+
+			tt.f(slog.New(h))
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// enrichHandler adds a host attribute to every record.
+type enrichHandler struct {
+	next slog.Handler
+}
+
+func (h *enrichHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *enrichHandler) Handle(ctx context.Context, r slog.Record) error {
+	r = r.Clone()
+	r.AddAttrs(slog.String("host", "local"))
+	return h.next.Handle(ctx, r)
+}
+
+func (h *enrichHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &enrichHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *enrichHandler) WithGroup(name string) slog.Handler {
+	return &enrichHandler{next: h.next.WithGroup(name)}
+}
+
+// redactHandler masks password and drops token attributes.
+type redactHandler struct {
+	next slog.Handler
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	r2 := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		if a, ok := redact(a); ok {
+			r2.AddAttrs(a)
+		}
+		return true
+	})
+	return h.next.Handle(ctx, r2)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var redacted []slog.Attr
+	for _, a := range attrs {
+		if a, ok := redact(a); ok {
+			redacted = append(redacted, a)
+		}
+	}
+	return &redactHandler{next: h.next.WithAttrs(redacted)}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{next: h.next.WithGroup(name)}
+}
+
+func redact(a slog.Attr) (slog.Attr, bool) {
+	switch a.Key {
+	case "password":
+		return slog.String(a.Key, "***"), true
+	case "token":
+		return slog.Attr{}, false
+	}
+	return a, true
+}
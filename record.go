@@ -0,0 +1,146 @@
+package slogutils
+
+import (
+	"log/slog"
+	"reflect"
+	"sync"
+)
+
+// GroupOrAttrs holds either a group name or a list of attributes, as collected from calls to
+// slog.Handler.WithGroup and slog.Handler.WithAttrs.
+type GroupOrAttrs struct {
+	// Group is the group name if non-empty
+	Group string
+	// Attrs are the attributes if Group is empty
+	Attrs []slog.Attr
+}
+
+// ApplyGroupsAndAttrs applies groups and attributes to a handler in the given order.
+// This is needed to replay stored records to a handler with the same state as the handler that received them:
+// attributes added before a group must not be qualified by the group, so the order must be preserved.
+// Empty groups and attribute lists are skipped as slog.Logger does.
+func ApplyGroupsAndAttrs(h slog.Handler, goas []GroupOrAttrs) slog.Handler {
+	for _, goa := range goas {
+		if goa.Group != "" {
+			h = h.WithGroup(goa.Group)
+		} else if len(goa.Attrs) > 0 {
+			h = h.WithAttrs(goa.Attrs)
+		}
+	}
+	return h
+}
+
+// NestAttrs returns the attributes of a record nested in the groups and attributes of a handler, i.e. the attributes
+// of an equivalent record for a handler without groups and attributes. The attributes are built from the inside out,
+// so attributes of the handler are qualified by the groups added before them. Empty groups are omitted as slog does.
+func NestAttrs(goas []GroupOrAttrs, recordAttrs []slog.Attr) []slog.Attr {
+	attrs := recordAttrs
+	for i := len(goas) - 1; i >= 0; i-- {
+		goa := goas[i]
+		if goa.Group != "" {
+			if len(attrs) > 0 {
+				attrs = []slog.Attr{{Key: goa.Group, Value: slog.GroupValue(attrs...)}}
+			}
+			continue
+		}
+		// The attributes of the handler are shared, a full slice expression forces a copy on append
+		attrs = append(goa.Attrs[:len(goa.Attrs):len(goa.Attrs)], attrs...)
+	}
+	return attrs
+}
+
+// PrefixCache builds handlers with attributes added at the top level of a base handler, before groups and attributes
+// collected by a wrapping handler. Wrapping handlers adding attributes per record (e.g. from the context) must apply
+// their groups and attributes again after these attributes, since attributes of a record are always qualified by the
+// groups of the handler. A PrefixCache is kept per derived handler and caches the handler of the last attributes,
+// so the handler is only rebuilt if the attributes change (e.g. for every request).
+type PrefixCache struct {
+	base slog.Handler
+	goas []GroupOrAttrs
+
+	mu      sync.Mutex
+	attrs   []slog.Attr
+	handler slog.Handler
+}
+
+// NewPrefixCache creates a cache for handlers based on base with goas applied after the attributes.
+func NewPrefixCache(base slog.Handler, goas []GroupOrAttrs) *PrefixCache {
+	return &PrefixCache{base: base, goas: goas}
+}
+
+// Handler returns the base handler with attrs and the groups and attributes of the cache applied.
+func (c *PrefixCache) Handler(attrs []slog.Attr) slog.Handler {
+	c.mu.Lock()
+	if c.handler != nil && attrsEqual(c.attrs, attrs) {
+		h := c.handler
+		c.mu.Unlock()
+		return h
+	}
+	c.mu.Unlock()
+
+	// Build the handler without holding the lock, so concurrent records are not serialized on a miss
+	h := ApplyGroupsAndAttrs(c.base.WithAttrs(attrs), c.goas)
+
+	c.mu.Lock()
+	c.attrs = attrs
+	c.handler = h
+	c.mu.Unlock()
+	return h
+}
+
+// attrsEqual reports whether attributes are equal. Values of kind slog.KindAny are only equal if they are comparable,
+// values of kind slog.KindLogValuer are never equal, since they might resolve to a different value.
+func attrsEqual(a, b []slog.Attr) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Key != b[i].Key || !valuesEqual(a[i].Value, b[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func valuesEqual(a, b slog.Value) bool {
+	switch a.Kind() {
+	case slog.KindAny:
+		return b.Kind() == slog.KindAny && anyEqual(a.Any(), b.Any())
+	case slog.KindLogValuer:
+		return false
+	case slog.KindGroup:
+		return b.Kind() == slog.KindGroup && attrsEqual(a.Group(), b.Group())
+	}
+	return a.Equal(b)
+}
+
+// anyEqual compares values with == if they are of the same comparable type (including their dynamic values).
+// Pointers are never equal, since the value they point to might have changed.
+func anyEqual(a, b any) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !va.IsValid() || !vb.IsValid() {
+		return !va.IsValid() && !vb.IsValid()
+	}
+	if va.Type() != vb.Type() || va.Kind() == reflect.Pointer || va.Kind() == reflect.UnsafePointer {
+		return false
+	}
+	return va.Comparable() && vb.Comparable() && a == b
+}
+
+// CloneRecordWithAttrs returns a clone of the record with additional attributes.
+// The original record is not modified and can still be used.
+func CloneRecordWithAttrs(r slog.Record, attrs ...slog.Attr) slog.Record {
+	r2 := r.Clone()
+	r2.AddAttrs(attrs...)
+	return r2
+}
+
+// RecordAttrs returns the attributes of the record as a slice.
+func RecordAttrs(r slog.Record) []slog.Attr {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
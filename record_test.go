@@ -0,0 +1,125 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestApplyGroupsAndAttrs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := slog.NewTextHandler(buf, &slog.HandlerOptions{ReplaceAttr: drop(slog.TimeKey)})
+
+	h2 := slogutils.ApplyGroupsAndAttrs(h, []slogutils.GroupOrAttrs{
+		{Attrs: []slog.Attr{slog.String("a", "1")}},
+		{Group: "g"},
+		{Attrs: []slog.Attr{slog.String("b", "2")}},
+		{Group: "h"},
+	})
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+	r.AddAttrs(slog.String("c", "3"))
+	if err := h2.Handle(context.Background(), r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := "level=INFO msg=test a=1 g.b=2 g.h.c=3\n"; buf.String() != want {
+		t.Fatalf("unexpected log output: %s", buf.String())
+	}
+}
+
+func TestNestAttrs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := slog.NewTextHandler(buf, &slog.HandlerOptions{ReplaceAttr: drop(slog.TimeKey)})
+
+	attrs := slogutils.NestAttrs([]slogutils.GroupOrAttrs{
+		{Attrs: []slog.Attr{slog.String("a", "1")}},
+		{Group: "g"},
+		{Attrs: []slog.Attr{slog.String("b", "2")}},
+		{Group: "h"},
+		{Group: "empty"},
+	}, []slog.Attr{slog.String("c", "3")})
+	slog.New(h).LogAttrs(context.Background(), slog.LevelInfo, "test", attrs...)
+
+	if want := "level=INFO msg=test a=1 g.b=2 g.h.empty.c=3\n"; buf.String() != want {
+		t.Fatalf("unexpected log output: %s", buf.String())
+	}
+
+	buf.Reset()
+	attrs = slogutils.NestAttrs([]slogutils.GroupOrAttrs{{Group: "g"}, {Group: "h"}}, nil)
+	slog.New(h).LogAttrs(context.Background(), slog.LevelInfo, "test", attrs...)
+	if want := "level=INFO msg=test\n"; buf.String() != want {
+		t.Fatalf("expected empty groups to be omitted, got: %s", buf.String())
+	}
+}
+
+func TestPrefixCache(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := slog.NewTextHandler(buf, &slog.HandlerOptions{ReplaceAttr: drop(slog.TimeKey)})
+
+	c := slogutils.NewPrefixCache(h, []slogutils.GroupOrAttrs{
+		{Group: "g"},
+		{Attrs: []slog.Attr{slog.String("b", "2")}},
+	})
+
+	h1 := c.Handler([]slog.Attr{slog.String("req", "1")})
+	if h2 := c.Handler([]slog.Attr{slog.String("req", "1")}); h2 != h1 {
+		t.Fatal("expected cached handler for equal attrs")
+	}
+	h2 := c.Handler([]slog.Attr{slog.String("req", "2")})
+	if h2 == h1 {
+		t.Fatal("expected new handler for changed attrs")
+	}
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+	r.AddAttrs(slog.String("c", "3"))
+	if err := h2.Handle(context.Background(), r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "level=INFO msg=test req=2 g.b=2 g.c=3\n"; buf.String() != want {
+		t.Fatalf("unexpected log output: %s", buf.String())
+	}
+}
+
+func TestPrefixCache_anyValues(t *testing.T) {
+	type tenant struct {
+		ID   int
+		Tags any
+	}
+	c := slogutils.NewPrefixCache(slog.NewTextHandler(io.Discard, nil), []slogutils.GroupOrAttrs{{Group: "g"}})
+
+	h1 := c.Handler([]slog.Attr{slog.Any("tenant", tenant{ID: 1})})
+	if h2 := c.Handler([]slog.Attr{slog.Any("tenant", tenant{ID: 1})}); h2 != h1 {
+		t.Fatal("expected cached handler for equal comparable values")
+	}
+	if h2 := c.Handler([]slog.Attr{slog.Any("tenant", tenant{ID: 2})}); h2 == h1 {
+		t.Fatal("expected new handler for changed values")
+	}
+
+	// Comparing values with non-comparable dynamic values must not panic
+	h1 = c.Handler([]slog.Attr{slog.Any("tenant", tenant{ID: 1, Tags: []string{"a"}})})
+	if h2 := c.Handler([]slog.Attr{slog.Any("tenant", tenant{ID: 1, Tags: []string{"a"}})}); h2 == h1 {
+		t.Fatal("expected new handler for non-comparable values")
+	}
+}
+
+func TestCloneRecordWithAttrs(t *testing.T) {
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+	r.AddAttrs(slog.String("a", "1"))
+
+	r2 := slogutils.CloneRecordWithAttrs(r, slog.Bool("replayed", true))
+	r.AddAttrs(slog.String("b", "2"))
+
+	attrs := slogutils.RecordAttrs(r2)
+	if len(attrs) != 2 || attrs[0].Key != "a" || attrs[1].Key != "replayed" {
+		t.Fatalf("unexpected attrs of clone: %v", attrs)
+	}
+	if r.NumAttrs() != 2 {
+		t.Fatalf("expected original record to be unchanged by clone, got %d attrs", r.NumAttrs())
+	}
+}
@@ -0,0 +1,294 @@
+// Package redact provides a handler that redacts sensitive attributes before delegating to another handler.
+package redact
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"log/slog"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/networkteam/slogutils"
+)
+
+// DefaultReplacement is the value redacted attributes or value matches are replaced with.
+const DefaultReplacement = "[REDACTED]"
+
+// Patterns for common sensitive values to be used in Options.ValuePatterns.
+var (
+	EmailPattern      = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	CreditCardPattern = regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)
+)
+
+// DefaultKeys are key patterns that are redacted if Options.Keys is nil.
+var DefaultKeys = []string{"password", "*_password", "secret", "*_secret", "token", "*_token", "authorization", "api_key"}
+
+// Options are options for a Handler.
+type Options struct {
+	// Keys are patterns for attribute keys whose values are redacted completely.
+	// Patterns are matched case-insensitive with path.Match syntax, e.g. "*_token".
+	// If Keys is nil, DefaultKeys are used.
+	Keys []string
+
+	// ValuePatterns are applied to all string values (and values rendered as strings),
+	// matches are replaced with the replacement.
+	ValuePatterns []*regexp.Regexp
+
+	// Replacement is used instead of redacted values, defaults to DefaultReplacement.
+	Replacement string
+}
+
+// Handler redacts attributes of records and attributes added by WithAttrs before delegating to the wrapped handler.
+type Handler struct {
+	next slog.Handler
+	opts Options
+}
+
+var (
+	_ slog.Handler           = (*Handler)(nil)
+	_ slogutils.AttrsEnabler = (*Handler)(nil)
+	_ slogutils.Wrapper      = (*Handler)(nil)
+)
+
+// NewHandler creates a new redacting handler.
+func NewHandler(next slog.Handler, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	o := *opts
+	if o.Keys == nil {
+		o.Keys = DefaultKeys
+	}
+	keys := make([]string, len(o.Keys))
+	for i, key := range o.Keys {
+		keys[i] = strings.ToLower(key)
+	}
+	o.Keys = keys
+	if o.Replacement == "" {
+		o.Replacement = DefaultReplacement
+	}
+
+	return &Handler{next: next, opts: o}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *Handler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *Handler) EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+	return slogutils.EnabledForAttrs(ctx, h.next, level, attrs)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	r2 := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		r2.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, r2)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(a)
+	}
+	return &Handler{next: h.next.WithAttrs(redacted), opts: h.opts}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), opts: h.opts}
+}
+
+func (h *Handler) redactAttr(a slog.Attr) slog.Attr {
+	return h.redactResolvedAttr(slogutils.ResolveAttr(a, 0))
+}
+
+func (h *Handler) redactResolvedAttr(a slog.Attr) slog.Attr {
+	if h.matchesKey(a.Key) {
+		return slog.String(a.Key, h.opts.Replacement)
+	}
+
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		groupAttrs := v.Group()
+		redacted := make([]slog.Attr, len(groupAttrs))
+		for i, ga := range groupAttrs {
+			redacted[i] = h.redactResolvedAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindString:
+		return slog.String(a.Key, h.redactString(v.String()))
+	case slog.KindAny:
+		if redacted, ok := h.redactComposite(v.Any()); ok {
+			return slog.Any(a.Key, redacted)
+		}
+		if len(h.opts.ValuePatterns) == 0 {
+			return slog.Attr{Key: a.Key, Value: v}
+		}
+		// Values of other types could contain sensitive data in their string representation
+		if s, ok := stringOf(v.Any()); ok {
+			if redacted := h.redactString(s); redacted != s {
+				return slog.String(a.Key, redacted)
+			}
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+// redactComposite redacts maps, structs and slices by their JSON representation: map keys and field names are
+// matched against the key patterns, strings against the value patterns. It returns the redacted JSON representation
+// and reports whether it replaces the value. The original value is only kept if nothing was redacted and the JSON
+// representation contains all of its data, since handlers might render it with fmt (including unexported fields).
+// Values that cannot be marshalled are replaced completely.
+func (h *Handler) redactComposite(v any) (any, bool) {
+	if !isComposite(v) {
+		return nil, false
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return h.opts.Replacement, true
+	}
+	var generic any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	// Keep numbers as they are, a float64 would lose precision of large integers
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return h.opts.Replacement, true
+	}
+	redacted, changed := h.redactJSON(generic)
+	if !changed && jsonVisible(reflect.TypeOf(v), nil) {
+		return nil, false
+	}
+	return redacted, true
+}
+
+func (h *Handler) redactJSON(v any) (any, bool) {
+	changed := false
+	switch x := v.(type) {
+	case map[string]any:
+		for k, e := range x {
+			if h.matchesKey(k) {
+				x[k] = h.opts.Replacement
+				changed = true
+			} else if redacted, ok := h.redactJSON(e); ok {
+				x[k] = redacted
+				changed = true
+			}
+		}
+	case []any:
+		for i, e := range x {
+			if redacted, ok := h.redactJSON(e); ok {
+				x[i] = redacted
+				changed = true
+			}
+		}
+	case string:
+		if redacted := h.redactString(x); redacted != x {
+			return redacted, true
+		}
+	}
+	return v, changed
+}
+
+// isComposite reports whether v is a map, struct, slice or array (or a pointer to one) that is not rendered as a
+// string (like errors, time.Time or []byte).
+func isComposite(v any) bool {
+	if _, ok := stringOf(v); ok {
+		return false
+	}
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Struct, reflect.Array:
+		return true
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// jsonVisible reports whether the JSON representation of values of type t contains all of their data.
+// Unexported and ignored fields, custom marshalers and interfaces (whose dynamic type is not known) are not visible.
+func jsonVisible(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return true
+	}
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return jsonVisible(t.Elem(), seen)
+	case reflect.Map:
+		return jsonVisible(t.Key(), seen) && jsonVisible(t.Elem(), seen)
+	case reflect.Struct:
+		if seen == nil {
+			seen = make(map[reflect.Type]bool)
+		}
+		// Recursive types are visible if their other fields are
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("json") == "-" || !jsonVisible(f.Type, seen) {
+				return false
+			}
+		}
+		return true
+	case reflect.Interface, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return false
+	}
+	return true
+}
+
+func (h *Handler) matchesKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range h.opts.Keys {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Handler) redactString(s string) string {
+	for _, pattern := range h.opts.ValuePatterns {
+		s = pattern.ReplaceAllLiteralString(s, h.opts.Replacement)
+	}
+	return s
+}
+
+func stringOf(v any) (string, bool) {
+	switch x := v.(type) {
+	case []byte:
+		return string(x), true
+	case error:
+		return x.Error(), true
+	case interface{ String() string }:
+		return x.String(), true
+	}
+	return "", false
+}
@@ -0,0 +1,126 @@
+package redact_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/redact"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *redact.Options
+		f    func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "default keys are redacted",
+			f: func(l *slog.Logger) {
+				l.Info("test", "user", "jane", "password", "secret", "refresh_token", "abc", "Authorization", "Bearer xyz")
+			},
+			want: `level=INFO msg=test user=jane password=[REDACTED] refresh_token=[REDACTED] Authorization=[REDACTED]`,
+		},
+		{
+			name: "custom keys",
+			opts: &redact.Options{
+				Keys: []string{"ssn"},
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test", "ssn", "123", "password", "secret")
+			},
+			want: `level=INFO msg=test ssn=[REDACTED] password=secret`,
+		},
+		{
+			name: "nested groups and handler attrs",
+			f: func(l *slog.Logger) {
+				l.With(slog.Group("db", "password", "secret")).WithGroup("req").Info("test", slog.Group("auth", "token", "abc", "user", "jane"))
+			},
+			want: `level=INFO msg=test db.password=[REDACTED] req.auth.token=[REDACTED] req.auth.user=jane`,
+		},
+		{
+			name: "value patterns",
+			opts: &redact.Options{
+				ValuePatterns: []*regexp.Regexp{redact.EmailPattern, redact.CreditCardPattern},
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test", "note", "contact jane@example.com", "card", "4111 1111 1111 1111", slogutils.Err(errors.New("invalid user jane@example.com")))
+			},
+			want: `level=INFO msg=test note="contact [REDACTED]" card=[REDACTED] err="invalid user [REDACTED]"`,
+		},
+		{
+			name: "maps and structs",
+			opts: &redact.Options{
+				ValuePatterns: []*regexp.Regexp{redact.EmailPattern},
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test",
+					"headers", map[string]string{"Authorization": "Bearer xyz", "Accept": "*/*"},
+					"user", struct {
+						Email    string
+						Password string
+						Age      int
+					}{Email: "jane@example.com", Password: "secret", Age: 42},
+					"ids", []int{1, 2},
+				)
+			},
+			want: `level=INFO msg=test headers="map[Accept:*/* Authorization:[REDACTED]]" user="map[Age:42 Email:[REDACTED] Password:[REDACTED]]" ids="[1 2]"`,
+		},
+		{
+			name: "values not fully visible as JSON",
+			opts: &redact.Options{
+				ValuePatterns: []*regexp.Regexp{redact.EmailPattern},
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test",
+					"user", struct {
+						Name  string
+						token string
+					}{Name: "jane", token: "abc"},
+					"hidden", struct {
+						Name   string
+						Secret string `json:"-"`
+					}{Name: "jane", Secret: "abc"},
+					"invalid", struct{ C chan int }{},
+					"raw", []byte("mail to jane@example.com"),
+				)
+			},
+			want: `level=INFO msg=test user=map[Name:jane] hidden=map[Name:jane] invalid=[REDACTED] raw="mail to [REDACTED]"`,
+		},
+		{
+			name: "custom replacement",
+			opts: &redact.Options{
+				Replacement: "***",
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test", "password", "secret")
+			},
+			want: `level=INFO msg=test password=***`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			h := slog.NewTextHandler(buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+			tt.f(slog.New(redact.NewHandler(h, tt.opts)))
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package slogutils
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+// DefaultMaxDepth is the default maximum nesting depth of groups when resolving attributes.
+const DefaultMaxDepth = 16
+
+// Markers for values replaced by ResolveAttr.
+const (
+	TruncatedValue = "[truncated]"
+	CycleValue     = "[cycle]"
+)
+
+// ResolveAttr resolves all slog.LogValuer values of an attribute, including values nested in groups.
+// Groups nested deeper than maxDepth are replaced by TruncatedValue and LogValuers returning themselves (directly or
+// nested in groups) by CycleValue, so handlers can safely walk the result recursively, even for adversarial values.
+// If maxDepth is <= 0, DefaultMaxDepth is used.
+func ResolveAttr(a slog.Attr, maxDepth int) slog.Attr {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	a.Value = resolveValue(a.Value, maxDepth, 0, nil)
+	return a
+}
+
+func resolveValue(v slog.Value, maxDepth, depth int, path []valuerID) slog.Value {
+	if v.Kind() == slog.KindLogValuer {
+		if id, ok := identityOf(v.Any()); ok {
+			for _, seen := range path {
+				if seen == id {
+					return slog.StringValue(CycleValue)
+				}
+			}
+			path = append(path, id)
+		}
+		v = v.Resolve()
+	}
+
+	if v.Kind() != slog.KindGroup {
+		return v
+	}
+	if depth >= maxDepth {
+		return slog.StringValue(TruncatedValue)
+	}
+
+	groupAttrs := v.Group()
+	var resolved []slog.Attr
+	for i, ga := range groupAttrs {
+		rv := resolveValue(ga.Value, maxDepth, depth+1, path)
+		// Only copy the group if a value changed
+		if resolved == nil && !sameValue(rv, ga.Value) {
+			resolved = make([]slog.Attr, len(groupAttrs))
+			copy(resolved, groupAttrs[:i])
+		}
+		if resolved != nil {
+			resolved[i] = slog.Attr{Key: ga.Key, Value: rv}
+		}
+	}
+	if resolved == nil {
+		return v
+	}
+	return slog.GroupValue(resolved...)
+}
+
+// valuerID identifies a pointer-shaped LogValuer, the type is part of the identity since pointers to zero-sized
+// values may be equal.
+type valuerID struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// identityOf returns the identity of a pointer-shaped value. Other values have no identity (comparing them might
+// panic for non-comparable fields), a value type returning itself is bounded by the maximum depth instead.
+func identityOf(v any) (valuerID, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if rv.IsNil() {
+			return valuerID{}, false
+		}
+		return valuerID{typ: rv.Type(), ptr: rv.Pointer()}, true
+	}
+	return valuerID{}, false
+}
+
+// sameValue reports whether a resolved value is unchanged, groups are compared by identity of their attributes.
+func sameValue(a, b slog.Value) bool {
+	if a.Kind() != b.Kind() {
+		return false
+	}
+	if a.Kind() == slog.KindGroup {
+		ga, gb := a.Group(), b.Group()
+		return len(ga) == len(gb) && (len(ga) == 0 || &ga[0] == &gb[0])
+	}
+	if a.Kind() == slog.KindAny {
+		return true
+	}
+	return a.Equal(b)
+}
@@ -0,0 +1,95 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+// selfValuer returns a group containing itself.
+type selfValuer struct {
+	name string
+}
+
+func (v *selfValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("name", v.name), slog.Any("self", v))
+}
+
+// deepValuer returns a new group with a nested valuer on every call.
+type deepValuer struct {
+	depth int
+}
+
+func (v deepValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("depth", v.depth), slog.Any("next", deepValuer{depth: v.depth + 1}))
+}
+
+// treeValuer is a value type with a non-comparable field.
+type treeValuer struct {
+	name  string
+	extra any
+	child *treeValuer
+}
+
+func (v treeValuer) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("name", v.name)}
+	if v.child != nil {
+		attrs = append(attrs, slog.Any("child", *v.child))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func TestResolveAttr(t *testing.T) {
+	tests := []struct {
+		name     string
+		attr     slog.Attr
+		maxDepth int
+		want     string
+	}{
+		{
+			name: "plain value",
+			attr: slog.String("key", "val"),
+			want: `key=val`,
+		},
+		{
+			name: "cycle",
+			attr: slog.Any("v", &selfValuer{name: "a"}),
+			want: `v.name=a v.self=[cycle]`,
+		},
+		{
+			name: "non-comparable value type",
+			attr: slog.Any("v", treeValuer{name: "a", extra: []int{1}, child: &treeValuer{name: "b", extra: []int{2}}}),
+			want: `v.name=a v.child.name=b`,
+		},
+		{
+			name:     "depth",
+			attr:     slog.Any("v", deepValuer{}),
+			maxDepth: 2,
+			want:     `v.depth=0 v.next.depth=1 v.next.next=[truncated]`,
+		},
+		{
+			name:     "nested groups",
+			attr:     slog.Group("a", slog.Group("b", slog.Group("c", "d", 1))),
+			maxDepth: 2,
+			want:     `a.b.c=[truncated]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			h := slog.NewTextHandler(buf, &slog.HandlerOptions{
+				ReplaceAttr: drop(slog.TimeKey, slog.LevelKey, slog.MessageKey),
+			})
+			slog.New(h).Info("", slogutils.ResolveAttr(tt.attr, tt.maxDepth))
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package rotatingfile
+
+import "time"
+
+// SetNow replaces the function returning the current time.
+func SetNow(f func() time.Time) (restore func()) {
+	prev := now
+	now = f
+	return func() {
+		now = prev
+	}
+}
@@ -0,0 +1,306 @@
+// Package rotatingfile provides a log file writer with size- and age-based rotation.
+package rotatingfile
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/logfile"
+)
+
+const (
+	defaultMaxSize = 100 * 1024 * 1024
+
+	// backupTimeFormat is used for names of rotated files, it sorts lexicographically
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+)
+
+// now returns the current time, it is replaced in tests.
+var now = time.Now
+
+// Options are options for a Writer.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// MaxSize is the maximum size of the log file in bytes before it is rotated, defaults to 100 MiB.
+	MaxSize int64
+
+	// MaxAge is the maximum age of the log file before it is rotated. If MaxAge is zero, files are not rotated by age.
+	MaxAge time.Duration
+
+	// MaxBackups is the maximum number of rotated files to keep, the oldest files are removed.
+	// If MaxBackups is zero, all rotated files are kept.
+	MaxBackups int
+
+	// Compress compresses rotated files with the codec, e.g. logfile.Gzip.
+	// Files are compressed in the background, so writes are not blocked by the compression.
+	Compress logfile.Codec
+
+	// Lock coordinates writes of multiple processes with an advisory lock, see logfile.Options.
+	// The size of the file is tracked per process, so rotation by size is approximate with multiple processes.
+	Lock bool
+
+	// Perm is the permission of a newly created file, defaults to 0644.
+	Perm fs.FileMode
+
+	// OnError is called with errors of compressing and removing rotated files in the background.
+	// If OnError is nil, these errors are discarded.
+	OnError func(err error)
+}
+
+// Writer appends to a log file and rotates it by size and age. Rotated files are named like the log file with
+// a timestamp suffix, e.g. "app.log.2023-08-01T12-00-00.000" (with the codec extension if compressed).
+type Writer struct {
+	opts Options
+
+	mu   sync.Mutex
+	w    *logfile.Writer
+	size int64
+	// openedAt is the time the log file was created, it is zero until the first write if unknown
+	openedAt time.Time
+
+	// cleanupMu serializes compressing and removing rotated files in the background
+	cleanupMu sync.Mutex
+	cleanupWg sync.WaitGroup
+}
+
+var _ io.WriteCloser = (*Writer)(nil)
+
+// Open opens or creates the log file at path for appending.
+func Open(path string, opts *Options) (*Writer, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	o := *opts
+	if o.MaxSize <= 0 {
+		o.MaxSize = defaultMaxSize
+	}
+
+	// Rotated files are compressed by the writer, so it is not blocked by the compression
+	w, err := logfile.Open(path, &logfile.Options{
+		Lock: o.Lock,
+		Perm: o.Perm,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rw := &Writer{opts: o, w: w}
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		rw.size = info.Size()
+		// The modification time is updated by every write, the file was created by the last rotation
+		rw.openedAt = rw.lastRotation()
+	}
+	return rw, nil
+}
+
+// OpenJSONHandler opens a rotating log file and returns a slog.JSONHandler writing to it.
+// Control attributes (see slogutils.Channel) are dropped before a ReplaceAttr function of handlerOpts is called.
+// The returned writer must be closed when the handler is not used anymore.
+func OpenJSONHandler(path string, opts *Options, handlerOpts *slog.HandlerOptions) (*slog.JSONHandler, *Writer, error) {
+	w, err := Open(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var o slog.HandlerOptions
+	if handlerOpts != nil {
+		o = *handlerOpts
+	}
+	replaceAttr := o.ReplaceAttr
+	o.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		if slogutils.IsControlAttr(a) {
+			return slog.Attr{}
+		}
+		if replaceAttr != nil {
+			return replaceAttr(groups, a)
+		}
+		return a
+	}
+	return slog.NewJSONHandler(w, &o), w, nil
+}
+
+// Path returns the path of the log file.
+func (w *Writer) Path() string {
+	return w.w.Path()
+}
+
+// Write appends p to the log file and rotates the file before if p would exceed the maximum size
+// or the file is older than the maximum age.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	if w.openedAt.IsZero() {
+		w.openedAt = now()
+	}
+	n, err := w.w.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *Writer) shouldRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.size+n > w.opts.MaxSize {
+		return true
+	}
+	return w.opts.MaxAge > 0 && !w.openedAt.IsZero() && now().Sub(w.openedAt) >= w.opts.MaxAge
+}
+
+// lastRotation returns the time of the newest rotated file or the zero time if there is none.
+func (w *Writer) lastRotation() time.Time {
+	backups, err := w.Backups()
+	if err != nil || len(backups) == 0 {
+		return time.Time{}
+	}
+	suffix := strings.TrimPrefix(filepath.Base(backups[len(backups)-1]), filepath.Base(w.w.Path())+".")
+	t, _ := time.ParseInLocation(backupTimeFormat, suffix[:len(backupTimeFormat)], time.Local)
+	return t
+}
+
+// Rotate rotates the log file immediately.
+// If Compress is set, the rotated file is compressed in the background and errors are reported to OnError.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.rotate()
+}
+
+func (w *Writer) rotate() error {
+	backupPath := w.backupPath()
+	err := w.w.Rotate(backupPath)
+	if err != nil && !w.backupExists(backupPath) {
+		return err
+	}
+	// Reset as soon as the file was renamed (even if reopening failed), so it is not rotated again on every write
+	w.size = 0
+	w.openedAt = now()
+	if err != nil {
+		return err
+	}
+
+	if w.opts.Compress == nil {
+		return w.removeBackups()
+	}
+
+	w.cleanupWg.Add(1)
+	go func() {
+		defer w.cleanupWg.Done()
+
+		w.cleanupMu.Lock()
+		defer w.cleanupMu.Unlock()
+
+		if err := logfile.CompressFile(backupPath, w.opts.Compress, w.opts.Perm); err != nil {
+			w.reportError(err)
+		}
+		if err := w.removeBackups(); err != nil {
+			w.reportError(err)
+		}
+	}()
+	return nil
+}
+
+func (w *Writer) reportError(err error) {
+	if w.opts.OnError != nil {
+		w.opts.OnError(err)
+	}
+}
+
+// backupPath returns a path for a rotated file that does not exist yet.
+func (w *Writer) backupPath() string {
+	base := w.w.Path() + "." + now().Format(backupTimeFormat)
+	path := base
+	for i := 1; w.backupExists(path); i++ {
+		path = fmt.Sprintf("%s-%d", base, i)
+	}
+	return path
+}
+
+func (w *Writer) backupExists(path string) bool {
+	if _, err := os.Lstat(path); err == nil {
+		return true
+	}
+	if w.opts.Compress != nil {
+		if _, err := os.Lstat(path + w.opts.Compress.Ext()); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Backups returns the paths of rotated files sorted from oldest to newest.
+func (w *Writer) Backups() ([]string, error) {
+	path := w.w.Path()
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("listing rotated log files: %w", err)
+	}
+
+	prefix := filepath.Base(path) + "."
+	var backups []string
+	for _, e := range entries {
+		if e.IsDir() || !isBackupName(e.Name(), prefix) {
+			continue
+		}
+		backups = append(backups, filepath.Join(filepath.Dir(path), e.Name()))
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// isBackupName checks if the name starts with the prefix followed by a timestamp.
+func isBackupName(name, prefix string) bool {
+	suffix, ok := strings.CutPrefix(name, prefix)
+	if !ok || len(suffix) < len(backupTimeFormat) {
+		return false
+	}
+	_, err := time.Parse(backupTimeFormat, suffix[:len(backupTimeFormat)])
+	return err == nil
+}
+
+func (w *Writer) removeBackups() error {
+	if w.opts.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := w.Backups()
+	if err != nil {
+		return err
+	}
+	for len(backups) > w.opts.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return fmt.Errorf("removing rotated log file: %w", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// Sync commits the contents of the log file to stable storage.
+func (w *Writer) Sync() error {
+	return w.w.Sync()
+}
+
+// Close closes the log file and waits until rotated files are compressed.
+func (w *Writer) Close() error {
+	err := w.w.Close()
+	w.cleanupWg.Wait()
+	return err
+}
@@ -0,0 +1,197 @@
+package rotatingfile_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils/logfile"
+	"github.com/networkteam/slogutils/rotatingfile"
+)
+
+func TestWriter_maxSize(t *testing.T) {
+	clock := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	defer rotatingfile.SetNow(func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	})()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := rotatingfile.Open(path, &rotatingfile.Options{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	for i := 0; i < 4; i++ {
+		_, _ = fmt.Fprintf(w, "line %d\n", i)
+	}
+
+	backups, err := w.Backups()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %v", backups)
+	}
+	if got := readAll(t, backups[0]); got != "line 1\n" {
+		t.Errorf("unexpected content of oldest backup: %q", got)
+	}
+	if got := readAll(t, path); got != "line 3\n" {
+		t.Errorf("unexpected content of log file: %q", got)
+	}
+}
+
+func TestWriter_maxAge(t *testing.T) {
+	clock := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	defer rotatingfile.SetNow(func() time.Time {
+		return clock
+	})()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := rotatingfile.Open(path, &rotatingfile.Options{MaxAge: time.Hour, Compress: logfile.Gzip})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	_, _ = fmt.Fprintln(w, "first")
+	clock = clock.Add(30 * time.Minute)
+	_, _ = fmt.Fprintln(w, "second")
+	clock = clock.Add(30 * time.Minute)
+	_, _ = fmt.Fprintln(w, "third")
+
+	// Wait for the compression in the background
+	_ = w.Close()
+
+	backups, _ := w.Backups()
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], "app.log.2023-08-01T13-00-00.000.gz") {
+		t.Fatalf("expected one compressed backup, got %v", backups)
+	}
+	if got := readAll(t, backups[0]); got != "first\nsecond\n" {
+		t.Errorf("unexpected content of backup: %q", got)
+	}
+}
+
+func TestWriter_maxAgeAfterReopen(t *testing.T) {
+	clock := time.Date(2023, 8, 1, 12, 0, 0, 0, time.Local)
+	defer rotatingfile.SetNow(func() time.Time {
+		return clock
+	})()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	opts := &rotatingfile.Options{MaxAge: time.Hour}
+	w, err := rotatingfile.Open(path, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = fmt.Fprintln(w, "first")
+	clock = clock.Add(time.Hour)
+	_, _ = fmt.Fprintln(w, "second")
+	_ = w.Close()
+
+	// The file was created by the rotation, not by the last write
+	clock = clock.Add(30 * time.Minute)
+	w, err = rotatingfile.Open(path, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	_, _ = fmt.Fprintln(w, "third")
+	clock = clock.Add(30 * time.Minute)
+	_, _ = fmt.Fprintln(w, "fourth")
+
+	backups, _ := w.Backups()
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %v", backups)
+	}
+	if got := readAll(t, backups[1]); got != "second\nthird\n" {
+		t.Errorf("unexpected content of newest backup: %q", got)
+	}
+}
+
+func TestWriter_compressError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	errs := make(chan error, 1)
+	w, err := rotatingfile.Open(path, &rotatingfile.Options{
+		MaxSize:  10,
+		Compress: failingCodec{},
+		OnError: func(err error) {
+			errs <- err
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := fmt.Fprintf(w, "line %d\n", i); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i == 1 {
+			if err := <-errs; err == nil || !strings.Contains(err.Error(), "codec failed") {
+				t.Fatalf("expected codec error, got %v", err)
+			}
+		}
+	}
+
+	// Writes are not affected by failing compressions, uncompressed rotated files are kept
+	if got := readAll(t, path); got != "line 2\n" {
+		t.Errorf("unexpected content of log file: %q", got)
+	}
+	_ = w.Close()
+	backups, _ := w.Backups()
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %v", backups)
+	}
+}
+
+type failingCodec struct{}
+
+func (failingCodec) Ext() string { return ".fail" }
+
+func (failingCodec) NewWriter(io.Writer) (io.WriteCloser, error) {
+	return nil, errors.New("codec failed")
+}
+
+func (failingCodec) NewReader(io.Reader) (io.ReadCloser, error) {
+	return nil, errors.New("codec failed")
+}
+
+func TestOpenJSONHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	h, w, err := rotatingfile.OpenJSONHandler(path, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	slog.New(h).Info("test")
+	_ = w.Close()
+
+	if got := readAll(t, path); !strings.Contains(got, `"msg":"test"`) {
+		t.Fatalf("unexpected content: %q", got)
+	}
+}
+
+func readAll(t *testing.T, path string) string {
+	t.Helper()
+
+	r, err := logfile.OpenReader(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(content)
+}
@@ -0,0 +1,152 @@
+package slogutils
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	// SectionKey is the key for the section attribute of loggers in a section.
+	SectionKey = "section"
+	// FailedSectionsKey is the key for the number of failed nested sections of a failed section.
+	FailedSectionsKey = "failed_sections"
+)
+
+// sectionMarker is the value of the section attribute. It resolves to the name of the section for handlers that
+// do not know about sections and carries the nesting depth for the CLIHandler.
+type sectionMarker struct {
+	name  string
+	depth int
+}
+
+func (m *sectionMarker) LogValue() slog.Value {
+	return slog.StringValue(m.name)
+}
+
+func sectionMarkerOf(a slog.Attr) (*sectionMarker, bool) {
+	if a.Key != SectionKey || a.Value.Kind() != slog.KindLogValuer {
+		return nil, false
+	}
+	m, ok := a.Value.Any().(*sectionMarker)
+	return m, ok
+}
+
+type sectionContextKey struct{}
+
+// Section is a phase of a long-running command, e.g. a build step of a deploy tool.
+// It logs a record when started and when done (with the duration and errors).
+// Records logged with the logger of the section are indented by the CLIHandler.
+type Section struct {
+	name   string
+	start  time.Time
+	parent *Section
+	ctx    context.Context
+
+	// logger is the logger that was active when the section was started, begin and end records are logged with it
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	childErrs []error
+	done      bool
+}
+
+// log logs a record with the logger of the section's parent and the given caller PC, so sources point to the caller
+// of StartSection and Done.
+func (s *Section) log(ctx context.Context, pc uintptr, level slog.Level, msg string, args ...any) {
+	if !s.logger.Enabled(ctx, level) {
+		return
+	}
+	r := slog.NewRecord(time.Now(), level, msg, pc)
+	r.Add(args...)
+	_ = s.logger.Handler().Handle(ctx, r)
+}
+
+// StartSection starts a new section with the given name and logs a record for it with the logger from the context.
+// Sections can be nested by starting a section with the context of another section (see Section.Context).
+func StartSection(ctx context.Context, name string, args ...any) *Section {
+	logger := FromContext(ctx)
+	parent, _ := ctx.Value(sectionContextKey{}).(*Section)
+
+	s := &Section{
+		name:   name,
+		start:  time.Now(),
+		parent: parent,
+		logger: logger,
+	}
+	sectionLogger := logger.With(slog.Any(SectionKey, &sectionMarker{name: name, depth: s.depth()}))
+	s.ctx = context.WithValue(WithLogger(ctx, sectionLogger), sectionContextKey{}, s)
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers and StartSection
+	runtime.Callers(2, pcs[:])
+	s.log(ctx, pcs[0], slog.LevelInfo, name, args...)
+
+	return s
+}
+
+func (s *Section) depth() int {
+	if s.parent == nil {
+		return 1
+	}
+	return s.parent.depth() + 1
+}
+
+// Context returns a context with the logger of the section, which should be used for work done in the section.
+func (s *Section) Context() context.Context {
+	return s.ctx
+}
+
+// Logger returns the logger of the section.
+func (s *Section) Logger() *slog.Logger {
+	return FromContext(s.ctx)
+}
+
+// Done ends the section and logs a record with the duration.
+// If err is not nil or a nested section failed, the record is logged as an error and the joined errors are returned.
+// Errors of nested sections were already logged by them, so the record only contains err and the number of failed
+// nested sections (see FailedSectionsKey). Calling Done more than once has no effect.
+func (s *Section) Done(err error) error {
+	s.mu.Lock()
+	if s.done {
+		s.mu.Unlock()
+		return nil
+	}
+	s.done = true
+	childErrs := s.childErrs
+	s.mu.Unlock()
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers and Done
+	runtime.Callers(2, pcs[:])
+
+	duration := time.Since(s.start)
+	joined := errors.Join(append([]error{err}, childErrs...)...)
+	if joined == nil {
+		s.log(s.ctx, pcs[0], slog.LevelInfo, s.name+" done", slog.Duration(DurationKey, duration))
+		return nil
+	}
+
+	args := []any{slog.Duration(DurationKey, duration)}
+	if err != nil {
+		args = append(args, Err(err))
+	}
+	if len(childErrs) > 0 {
+		args = append(args, slog.Int(FailedSectionsKey, len(childErrs)))
+	}
+	s.log(s.ctx, pcs[0], slog.LevelError, s.name+" failed", args...)
+	if s.parent != nil {
+		s.parent.addChildErr(joined)
+	}
+	return joined
+}
+
+func (s *Section) addChildErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.childErrs = append(s.childErrs, err)
+}
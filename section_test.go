@@ -0,0 +1,91 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestSection(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slogutils.NewCLIHandler(buf, &slogutils.CLIHandlerOptions{
+		ReplaceAttr: drop("duration"),
+	}))
+	ctx := slogutils.WithLogger(context.Background(), logger)
+
+	deploy := slogutils.StartSection(ctx, "Deploying", "env", "prod")
+	build := slogutils.StartSection(deploy.Context(), "Building images")
+	build.Logger().Info("Built image", "image", "app")
+	_ = build.Done(errors.New("push failed"))
+	slogutils.FromContext(deploy.Context()).Info("Cleaning up")
+	err := deploy.Done(nil)
+
+	if err == nil || err.Error() != "push failed" {
+		t.Fatalf("expected error of nested section, got: %v", err)
+	}
+
+	want := strings.Join([]string{
+		`  • Deploying                 env=prod`,
+		`    • Building images        `,
+		`      • Built image           image=app`,
+		`    ✕ Building images failed  err="push failed"`,
+		`    • Cleaning up            `,
+		`  ✕ Deploying failed          failed_sections=1`,
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+func TestSection_attrForOtherHandlers(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: drop(slog.TimeKey, "duration"),
+	}))
+	ctx := slogutils.WithLogger(context.Background(), logger)
+
+	s := slogutils.StartSection(ctx, "Building")
+	s.Logger().Info("Step")
+	_ = s.Done(nil)
+
+	want := "level=INFO msg=Building\nlevel=INFO msg=Step section=Building\nlevel=INFO msg=\"Building done\"\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected log output: %s", buf.String())
+	}
+}
+
+func TestSection_source(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		AddSource: true,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.TimeKey, "duration":
+				return slog.Attr{}
+			case slog.SourceKey:
+				src := a.Value.Any().(*slog.Source)
+				return slog.String(slog.SourceKey, fmt.Sprintf("%s:%d", filepath.Base(src.File), src.Line))
+			}
+			return a
+		},
+	}))
+	ctx := slogutils.WithLogger(context.Background(), logger)
+
+	_, _, line, _ := runtime.Caller(0)
+	s := slogutils.StartSection(ctx, "Building")
+	_ = s.Done(nil)
+
+	want := fmt.Sprintf("level=INFO source=section_test.go:%d msg=Building\nlevel=INFO source=section_test.go:%d msg=\"Building done\"\n", line+1, line+2)
+	if buf.String() != want {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, buf.String())
+	}
+}
@@ -0,0 +1,53 @@
+package slogutils
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// ToggleLevelOnSignal installs a signal handler that flips the level of levelVar between its current level and
+// the given level (e.g. slog.LevelDebug) every time the signal is received:
+//
+//	stop := slogutils.ToggleLevelOnSignal(levelVar, syscall.SIGUSR1, slog.LevelDebug)
+//	defer stop()
+//
+// This is handy for long-running daemons without an HTTP admin port (see LevelHandler).
+// The returned function uninstalls the signal handler.
+func ToggleLevelOnSignal(levelVar *slog.LevelVar, sig os.Signal, level slog.Level) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		configured := levelVar.Level()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ch:
+				if current := levelVar.Level(); current == level {
+					levelVar.Set(configured)
+				} else {
+					// The level might have been changed by other means since the last signal
+					configured = current
+					levelVar.Set(level)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+			wg.Wait()
+		})
+	}
+}
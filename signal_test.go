@@ -0,0 +1,34 @@
+//go:build unix
+
+package slogutils_test
+
+import (
+	"log/slog"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestToggleLevelOnSignal(t *testing.T) {
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(slog.LevelWarn)
+
+	stop := slogutils.ToggleLevelOnSignal(levelVar, syscall.SIGUSR1, slog.LevelDebug)
+	defer stop()
+
+	for _, want := range []slog.Level{slog.LevelDebug, slog.LevelWarn, slog.LevelDebug} {
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for levelVar.Level() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected level %v, got %v", want, levelVar.Level())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
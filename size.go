@@ -0,0 +1,98 @@
+package slogutils
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+const (
+	// recordOverhead is the estimated size of the fixed fields of a record (time, level, PC).
+	recordOverhead = 48
+	// attrOverhead is the estimated size of an attribute besides its key and value.
+	attrOverhead = 16
+	// fixedValueSize is the estimated size of numeric, bool, duration and time values.
+	fixedValueSize = 8
+)
+
+// EstimateSize estimates the size in bytes of a record together with additional attributes
+// (e.g. attributes collected by WithAttrs of a handler).
+// The estimation is cheap and roughly corresponds to the size of an encoded record, so it can be used to enforce
+// memory or byte budgets consistently. Values of kind slog.KindAny are only formatted if they are errors or implement
+// fmt.Stringer, other values are estimated by their kind and length without formatting them.
+func EstimateSize(r slog.Record, attrs []slog.Attr) int {
+	size := recordOverhead + len(r.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		size += EstimateAttrSize(a)
+		return true
+	})
+	for _, a := range attrs {
+		size += EstimateAttrSize(a)
+	}
+	return size
+}
+
+// EstimateAttrSize estimates the size in bytes of an attribute, see EstimateSize.
+// The attribute is resolved with ResolveAttr, so cyclic or deeply nested LogValuers are estimated safely.
+func EstimateAttrSize(a slog.Attr) int {
+	return estimateResolvedAttrSize(ResolveAttr(a, 0))
+}
+
+func estimateResolvedAttrSize(a slog.Attr) int {
+	return attrOverhead + len(a.Key) + estimateValueSize(a.Value)
+}
+
+func estimateValueSize(v slog.Value) int {
+	switch v.Kind() {
+	case slog.KindString:
+		return len(v.String())
+	case slog.KindGroup:
+		size := 0
+		for _, a := range v.Group() {
+			size += estimateResolvedAttrSize(a)
+		}
+		return size
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case nil:
+			return fixedValueSize
+		case error:
+			return len(x.Error())
+		case fmt.Stringer:
+			return len(x.String())
+		case []byte:
+			return len(x)
+		default:
+			return estimateAnySize(reflect.ValueOf(x))
+		}
+	default:
+		return fixedValueSize
+	}
+}
+
+// estimateAnySize estimates the size of a value by its kind and length. Elements and fields are estimated with
+// a fixed size, so the estimation does not depend on the depth of the value.
+func estimateAnySize(v reflect.Value) int {
+	// Limit dereferencing, a pointer might point to itself
+	for i := 0; i < 8 && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface); i++ {
+		if v.IsNil() {
+			return fixedValueSize
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.Len()
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Len()
+		}
+		return v.Len() * fixedValueSize
+	case reflect.Map:
+		return 2 * v.Len() * fixedValueSize
+	case reflect.Struct:
+		return v.NumField() * fixedValueSize
+	default:
+		return fixedValueSize
+	}
+}
@@ -0,0 +1,74 @@
+package slogutils_test
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestEstimateSize(t *testing.T) {
+	newRecord := func(msg string, args ...any) slog.Record {
+		r := slog.NewRecord(time.Now(), slog.LevelInfo, msg, 0)
+		r.Add(args...)
+		return r
+	}
+
+	tests := []struct {
+		record slog.Record
+		attrs  []slog.Attr
+		want   int
+	}{
+		{
+			record: newRecord(""),
+			want:   48,
+		},
+		{
+			record: newRecord("test"),
+			want:   52,
+		},
+		{
+			// 52 + (16 + 3 + 3)
+			record: newRecord("test", "key", "val"),
+			want:   74,
+		},
+		{
+			// 52 + (16 + 3 + 8) + (16 + 3 + 4)
+			record: newRecord("test", "num", 42),
+			attrs:  []slog.Attr{slogutils.Err(errors.New("fail"))},
+			want:   102,
+		},
+		{
+			// 52 + (16 + 1 + (16 + 1 + 1) + (16 + 1 + 8))
+			record: newRecord("test", slog.Group("g", "a", "b", "c", true)),
+			want:   112,
+		},
+		{
+			// 52 + (16 + 5 + 3 * 8)
+			record: newRecord("test", "slice", []int{1, 2, 3}),
+			want:   97,
+		},
+		{
+			// 52 + (16 + 4 + 2 * 8)
+			record: newRecord("test", "user", &struct{ Name, Email string }{"jane", "jane@example.com"}),
+			want:   88,
+		},
+		{
+			// 52 + (16 + 1 + (16 + 4 + 1) + (16 + 4 + len("[cycle]")))
+			record: newRecord("test", "v", &selfValuer{name: "a"}),
+			want:   117,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			got := slogutils.EstimateSize(tt.record, tt.attrs)
+			if got != tt.want {
+				t.Fatalf("expected size %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
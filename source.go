@@ -0,0 +1,88 @@
+package slogutils
+
+import (
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const defaultSourceSegments = 2
+
+// SourceOptions control how slog.Source values are shortened by CLIHandler.
+// A zero SourceOptions consists entirely of default values.
+type SourceOptions struct {
+	// TrimPrefix is removed from file paths, e.g. the directory of the module.
+	TrimPrefix string
+
+	// Segments is the number of trailing path segments of the file that are rendered (e.g. 2 for "pkg/file.go").
+	// If Segments is 0, it defaults to 2, unless TrimPrefix is set: then the full path after the prefix is rendered.
+	// If Segments is negative, the full path is rendered.
+	Segments int
+}
+
+// format renders the source as "file:line" with a shortened file path.
+func (o *SourceOptions) format(src *slog.Source) string {
+	if o == nil {
+		o = &SourceOptions{}
+	}
+
+	file := filepath.ToSlash(src.File)
+	segments := o.Segments
+	if o.TrimPrefix != "" {
+		prefix := strings.TrimSuffix(filepath.ToSlash(o.TrimPrefix), "/") + "/"
+		if trimmed, ok := strings.CutPrefix(file, prefix); ok {
+			file = trimmed
+			if segments == 0 {
+				segments = -1
+			}
+		}
+	}
+	if segments == 0 {
+		segments = defaultSourceSegments
+	}
+	if segments > 0 {
+		file = lastSegments(file, segments)
+	}
+
+	return file + ":" + strconv.Itoa(src.Line)
+}
+
+// lastSegments returns the last n segments of a slash separated path.
+func lastSegments(path string, n int) string {
+	i := len(path)
+	for ; n > 0; n-- {
+		i = strings.LastIndexByte(path[:i], '/')
+		if i == -1 {
+			return path
+		}
+	}
+	return path[i+1:]
+}
+
+// sourceOf returns the source of a value of type slog.Source or *slog.Source.
+func sourceOf(v slog.Value) (*slog.Source, bool) {
+	if v.Kind() != slog.KindAny {
+		return nil, false
+	}
+	switch src := v.Any().(type) {
+	case *slog.Source:
+		return src, src != nil
+	case slog.Source:
+		return &src, true
+	default:
+		return nil, false
+	}
+}
+
+// recordSource returns the source of the program counter of a record.
+func recordSource(r slog.Record) *slog.Source {
+	frames := runtime.CallersFrames([]uintptr{r.PC})
+	frame, _ := frames.Next()
+	return &slog.Source{
+		Function: frame.Function,
+		File:     frame.File,
+		Line:     frame.Line,
+	}
+}
@@ -0,0 +1,117 @@
+// Package stdlog bridges the standard library log package (and other io.Writer based loggers) to slog.
+// Every line is logged as a record, the level is detected from a prefix of the line (e.g. "ERROR: ...").
+//
+// Third-party libraries that only accept a *log.Logger can be wired with NewLogger:
+//
+//	srv := &http.Server{ErrorLog: stdlog.NewLogger(logger, &stdlog.Options{Level: slog.LevelError})}
+package stdlog
+
+import (
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Rule detects the level of a line starting with a prefix.
+type Rule struct {
+	// Prefix is matched case-insensitively at the start of the line (ignoring leading spaces). The prefix must not
+	// be followed by a letter or digit, so "INFO" does not match "Information".
+	Prefix string
+	// Level of records for matching lines.
+	Level slog.Level
+}
+
+// DefaultRules are the rules used if Options.Rules is nil.
+var DefaultRules = []Rule{
+	{Prefix: "[TRACE]", Level: slogutils.LevelTrace},
+	{Prefix: "[DEBUG]", Level: slog.LevelDebug},
+	{Prefix: "[INFO]", Level: slog.LevelInfo},
+	{Prefix: "[WARN]", Level: slog.LevelWarn},
+	{Prefix: "[WARNING]", Level: slog.LevelWarn},
+	{Prefix: "[ERROR]", Level: slog.LevelError},
+	{Prefix: "[ERR]", Level: slog.LevelError},
+	{Prefix: "[FATAL]", Level: slog.LevelError},
+	{Prefix: "TRACE", Level: slogutils.LevelTrace},
+	{Prefix: "DEBUG", Level: slog.LevelDebug},
+	{Prefix: "INFO", Level: slog.LevelInfo},
+	{Prefix: "WARN", Level: slog.LevelWarn},
+	{Prefix: "WARNING", Level: slog.LevelWarn},
+	{Prefix: "ERROR", Level: slog.LevelError},
+	{Prefix: "ERR", Level: slog.LevelError},
+	{Prefix: "FATAL", Level: slog.LevelError},
+	{Prefix: "PANIC", Level: slog.LevelError},
+}
+
+// Options are options for a Writer.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Level is the level of lines not matching a rule, defaults to info.
+	Level slog.Level
+
+	// Rules detect the level of a line by its prefix, the first matching rule is used and the prefix is removed
+	// from the message. If Rules is nil, DefaultRules are used. Use an empty slice to disable level detection.
+	Rules []Rule
+
+	// MaxLineLength is the maximum length of a line in bytes, defaults to slogutils.DefaultMaxLineLength.
+	// Longer lines are logged in parts.
+	MaxLineLength int
+}
+
+// Writer logs every line written to it as a record, see slogutils.Writer.
+// Partial lines are buffered until a newline is written or Flush is called.
+type Writer struct {
+	*slogutils.Writer
+
+	level slog.Level
+	rules []Rule
+}
+
+var _ io.Writer = (*Writer)(nil)
+
+// NewWriter creates a new writer logging to the given logger.
+func NewWriter(logger *slog.Logger, opts *Options) *Writer {
+	if opts == nil {
+		opts = &Options{}
+	}
+	rules := opts.Rules
+	if rules == nil {
+		rules = DefaultRules
+	}
+	w := &Writer{
+		level: opts.Level,
+		rules: rules,
+	}
+	w.Writer = slogutils.NewWriterWithOptions(logger, &slogutils.WriterOptions{
+		LevelOf:       w.detectLevel,
+		MaxLineLength: opts.MaxLineLength,
+	})
+	return w
+}
+
+// NewLogger creates a *log.Logger without flags that writes to a new Writer.
+// Existing loggers can be redirected with log.Logger.SetOutput, but should not use date or time flags
+// (or use log.Lmsgprefix), since a prefix of the line would prevent the level detection.
+func NewLogger(logger *slog.Logger, opts *Options) *log.Logger {
+	return log.New(NewWriter(logger, opts), "", 0)
+}
+
+// detectLevel returns the level of the first matching rule and the line without the prefix.
+func (w *Writer) detectLevel(line string) (slog.Level, string) {
+	trimmed := strings.TrimLeft(line, " \t")
+	for _, rule := range w.rules {
+		if len(trimmed) < len(rule.Prefix) || !strings.EqualFold(trimmed[:len(rule.Prefix)], rule.Prefix) {
+			continue
+		}
+		rest := trimmed[len(rule.Prefix):]
+		if r, _ := utf8.DecodeRuneInString(rest); unicode.IsLetter(r) || unicode.IsDigit(r) {
+			continue
+		}
+		return rule.Level, strings.TrimLeft(rest, ": \t")
+	}
+	return w.level, line
+}
@@ -0,0 +1,99 @@
+package stdlog_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/stdlog"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *stdlog.Options
+		in   []string
+		want []string
+	}{
+		{
+			name: "level detection",
+			in:   []string{"ERROR: connection lost\n", "[warn] slow request\n", "Information only\n", "debug\tdetails\n", "TRACE\n"},
+			want: []string{
+				`level=ERROR msg="connection lost"`,
+				`level=WARN msg="slow request"`,
+				`level=INFO msg="Information only"`,
+				`level=DEBUG msg=details`,
+				`level=DEBUG-4 msg=""`,
+			},
+		},
+		{
+			name: "partial writes and multiple lines",
+			in:   []string{"first ", "line\nsecond line\n\nthird", " line"},
+			want: []string{
+				`level=INFO msg="first line"`,
+				`level=INFO msg="second line"`,
+				`level=INFO msg="third line"`,
+			},
+		},
+		{
+			name: "custom level and rules",
+			opts: &stdlog.Options{
+				Level: slog.LevelWarn,
+				Rules: []stdlog.Rule{{Prefix: "E", Level: slog.LevelError}},
+			},
+			in: []string{"E failed\n", "ERROR: not matched\n"},
+			want: []string{
+				`level=ERROR msg=failed`,
+				`level=WARN msg="ERROR: not matched"`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				Level: slogutils.LevelTrace,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+
+			w := stdlog.NewWriter(logger, test.opts)
+			for _, s := range test.in {
+				_, _ = w.Write([]byte(s))
+			}
+			_ = w.Flush()
+
+			want := strings.Join(test.want, "\n")
+			got := strings.TrimRight(buf.String(), "\n")
+			if want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+			}
+		})
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	stdlog.NewLogger(logger, nil).Printf("ERROR: handshake failed for %s", "10.0.0.1")
+
+	want := `level=ERROR msg="handshake failed for 10.0.0.1"`
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
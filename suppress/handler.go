@@ -0,0 +1,163 @@
+// Package suppress provides a handler that drops or demotes records based on their source.
+//
+// Rules are matched against the package and function that created a record (resolved from the PC of the record),
+// which allows to silence noisy third-party packages without changing their code.
+package suppress
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Action is the action to take for a record matched by a rule.
+// The zero value is Demote, so a rule without an explicit action never discards records.
+type Action int
+
+const (
+	// Demote changes the level of the record to the level of the rule.
+	Demote Action = iota
+	// Drop discards the record.
+	Drop
+)
+
+// Rule matches records by their source.
+type Rule struct {
+	// Package is the import path of the package that created the record.
+	// A trailing "/..." also matches all sub packages.
+	Package string
+
+	// Function optionally restricts the rule to a function of the package,
+	// e.g. "Dial" or "(*Client).Do".
+	Function string
+
+	// Below optionally restricts the rule to records with a level below the given level.
+	// If Below is nil, the rule applies to records of all levels.
+	Below slog.Leveler
+
+	// Action is the action to take for a matched record, defaults to Demote.
+	Action Action
+
+	// Level is the new level for records matched by a rule with the Demote action.
+	Level slog.Level
+}
+
+func (rule Rule) matches(src source, level slog.Level) bool {
+	if rule.Below != nil && level >= rule.Below.Level() {
+		return false
+	}
+	if rule.Function != "" && rule.Function != src.function {
+		return false
+	}
+	if pkg, ok := strings.CutSuffix(rule.Package, "/..."); ok {
+		return src.pkg == pkg || strings.HasPrefix(src.pkg, pkg+"/")
+	}
+	return src.pkg == rule.Package
+}
+
+// Handler wraps a handler and applies suppression rules to records before delegating to it.
+type Handler struct {
+	next  slog.Handler
+	rules []Rule
+
+	sources *sync.Map // map[uintptr]source
+}
+
+var (
+	_ slog.Handler           = (*Handler)(nil)
+	_ slogutils.AttrsEnabler = (*Handler)(nil)
+	_ slogutils.Wrapper      = (*Handler)(nil)
+)
+
+// NewHandler creates a handler that applies the rules in order, the first matching rule wins.
+func NewHandler(next slog.Handler, rules ...Rule) *Handler {
+	return &Handler{
+		next:    next,
+		rules:   rules,
+		sources: &sync.Map{},
+	}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	// The source is not known yet, so we cannot apply rules here.
+	return h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *Handler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *Handler) EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+	return slogutils.EnabledForAttrs(ctx, h.next, level, attrs)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if r.PC == 0 || len(h.rules) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	src := h.source(r.PC)
+	for _, rule := range h.rules {
+		if !rule.matches(src, r.Level) {
+			continue
+		}
+
+		if rule.Action == Drop {
+			return nil
+		}
+
+		r.Level = rule.Level
+		if !h.next.Enabled(ctx, r.Level) {
+			return nil
+		}
+		break
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithAttrs(attrs)
+	return &h2
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	return &h2
+}
+
+type source struct {
+	pkg      string
+	function string
+}
+
+func (h *Handler) source(pc uintptr) source {
+	if src, ok := h.sources.Load(pc); ok {
+		return src.(source)
+	}
+
+	frames := runtime.CallersFrames([]uintptr{pc})
+	frame, _ := frames.Next()
+	src := splitFunction(frame.Function)
+	h.sources.Store(pc, src)
+	return src
+}
+
+// splitFunction splits a fully qualified function name like "github.com/foo/bar.(*T).Method" into
+// the package path and function name.
+func splitFunction(fn string) source {
+	lastSlash := strings.LastIndex(fn, "/")
+	dot := strings.Index(fn[lastSlash+1:], ".")
+	if dot < 0 {
+		return source{pkg: fn}
+	}
+	dot += lastSlash + 1
+	return source{pkg: fn[:dot], function: fn[dot+1:]}
+}
@@ -0,0 +1,129 @@
+package suppress_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils/suppress"
+)
+
+const testPackage = "github.com/networkteam/slogutils/suppress_test"
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []suppress.Rule
+		f     func(l *slog.Logger)
+		want  string
+	}{
+		{
+			name: "no matching rule",
+			rules: []suppress.Rule{
+				{Package: "github.com/other/pkg", Action: suppress.Drop},
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			want: `level=INFO msg=test`,
+		},
+		{
+			name: "package is dropped",
+			rules: []suppress.Rule{
+				{Package: testPackage, Action: suppress.Drop},
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			want: ``,
+		},
+		{
+			name: "sub packages are matched",
+			rules: []suppress.Rule{
+				{Package: "github.com/networkteam/...", Action: suppress.Drop},
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			want: ``,
+		},
+		{
+			name: "function is matched",
+			rules: []suppress.Rule{
+				{Package: testPackage, Function: "noisy", Action: suppress.Drop},
+			},
+			f: func(l *slog.Logger) {
+				noisy(l)
+				l.Info("test")
+			},
+			want: `level=INFO msg=test`,
+		},
+		{
+			name: "only records below level are matched",
+			rules: []suppress.Rule{
+				{Package: testPackage, Below: slog.LevelWarn, Action: suppress.Drop},
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test")
+				l.Warn("important")
+			},
+			want: `level=WARN msg=important`,
+		},
+		{
+			name: "records are demoted",
+			rules: []suppress.Rule{
+				{Package: testPackage, Action: suppress.Demote, Level: slog.LevelDebug},
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			want: `level=DEBUG msg=test`,
+		},
+		{
+			name: "rules without action demote records",
+			rules: []suppress.Rule{
+				{Package: testPackage},
+			},
+			f: func(l *slog.Logger) {
+				l.Warn("test")
+			},
+			want: `level=INFO msg=test`,
+		},
+		{
+			name: "demoted records below handler level are dropped",
+			rules: []suppress.Rule{
+				{Package: testPackage, Action: suppress.Demote, Level: slog.LevelDebug - 4},
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			want: ``,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			h := slog.NewTextHandler(buf, &slog.HandlerOptions{
+				Level: slog.LevelDebug,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+			tt.f(slog.New(suppress.NewHandler(h, tt.rules...)))
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func noisy(l *slog.Logger) {
+	l.Info("noisy")
+}
@@ -0,0 +1,63 @@
+package syslog
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// localSocketPaths are tried in order to connect to the local syslog daemon.
+var localSocketPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// Dial connects to a syslog server and returns a handler writing to it.
+// The network is "udp", "tcp" or "unix" / "unixgram" as for net.Dial.
+// If network is empty, the handler connects to the local syslog daemon via a unix socket.
+// Messages on stream connections (tcp and unix) are framed by octet counting as specified in RFC 6587,
+// messages on datagram connections (udp and unixgram) are sent unframed.
+func Dial(network, addr string, opts *Options) (*Handler, error) {
+	dial := func() (net.Conn, error) {
+		if network == "" {
+			return dialLocal()
+		}
+		return net.Dial(network, addr)
+	}
+
+	conn, err := dial()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to syslog: %w", err)
+	}
+
+	return newHandler(&syncWriter{
+		w:      conn,
+		framed: isStream(conn),
+		redial: dial,
+	}, opts), nil
+}
+
+// isStream reports whether conn is a stream connection, whose messages must be framed.
+// The local syslog daemon might be reached by either a datagram or a stream socket, so this is decided per connection.
+func isStream(conn net.Conn) bool {
+	addr := conn.RemoteAddr()
+	if addr == nil {
+		return false
+	}
+	switch addr.Network() {
+	case "udp", "udp4", "udp6", "unixgram":
+		return false
+	}
+	return true
+}
+
+func dialLocal() (net.Conn, error) {
+	var errs []error
+	for _, path := range localSocketPaths {
+		for _, network := range []string{"unixgram", "unix"} {
+			conn, err := net.Dial(network, path)
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+		}
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,386 @@
+// Package syslog provides a handler writing RFC 5424 syslog messages to a local or remote syslog server.
+package syslog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Facility is a syslog facility.
+// Facilities are numbered from one, so the zero value means unset and FacilityKern can be used in Options.
+type Facility int
+
+// Syslog facilities
+const (
+	FacilityKern Facility = iota + 1
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+	_
+	_
+	_
+	_
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// code returns the numerical code of the facility as specified in RFC 5424.
+func (f Facility) code() int {
+	return int(f) - 1
+}
+
+// Severity is a syslog severity.
+type Severity int
+
+// Syslog severities
+const (
+	SeverityEmergency Severity = iota
+	SeverityAlert
+	SeverityCritical
+	SeverityError
+	SeverityWarning
+	SeverityNotice
+	SeverityInformational
+	SeverityDebug
+)
+
+// DefaultSDID is the default ID of the structured data element for attributes.
+// 32473 is the private enterprise number reserved for documentation by RFC 5612.
+const DefaultSDID = "slog@32473"
+
+const nilValue = "-"
+
+// Options are options for a Handler.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Level reports the minimum record level that will be logged.
+	// If Level is nil, the handler assumes slog.LevelInfo.
+	Level slog.Leveler
+
+	// Facility of messages, defaults to FacilityUser.
+	Facility Facility
+
+	// AppName of messages, defaults to the base name of the executable.
+	AppName string
+
+	// Hostname of messages, defaults to os.Hostname.
+	Hostname string
+
+	// SDID is the ID of the structured data element for attributes, defaults to DefaultSDID.
+	SDID string
+
+	// ToSeverity maps a level to a syslog severity, defaults to ToSeverity.
+	ToSeverity func(level slog.Level) Severity
+
+	// GroupKeys controls how keys of attributes in groups are joined to parameter names, defaults to a dot.
+	GroupKeys slogutils.GroupKeys
+
+	// OnError is called with the record and the error if writing a message fails (after a reconnect for Dial).
+	// The error is also returned by Handle, but discarded by slog.Logger.
+	OnError func(r slog.Record, err error)
+}
+
+// ToSeverity is the default mapping of levels to syslog severities.
+// Levels below debug (like slogutils.LevelTrace) are mapped to SeverityDebug, levels above error to SeverityCritical.
+func ToSeverity(level slog.Level) Severity {
+	switch {
+	case level >= slog.LevelError+4:
+		return SeverityCritical
+	case level >= slog.LevelError:
+		return SeverityError
+	case level >= slog.LevelWarn:
+		return SeverityWarning
+	case level >= slog.LevelInfo:
+		return SeverityInformational
+	default:
+		return SeverityDebug
+	}
+}
+
+// Handler writes records as RFC 5424 messages, attributes are written as structured data.
+type Handler struct {
+	w    *syncWriter
+	opts Options
+	goas []slogutils.GroupOrAttrs
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler creates a handler writing one message per Write call to w.
+// Use Dial to write to a syslog server.
+func NewHandler(w io.Writer, opts *Options) *Handler {
+	return newHandler(&syncWriter{w: w}, opts)
+}
+
+func newHandler(w *syncWriter, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	o := *opts
+	if o.Level == nil {
+		o.Level = slog.LevelInfo
+	}
+	if o.Facility == 0 {
+		o.Facility = FacilityUser
+	}
+	if o.AppName == "" {
+		o.AppName = filepath.Base(os.Args[0])
+	}
+	if o.Hostname == "" {
+		o.Hostname, _ = os.Hostname()
+	}
+	if o.SDID == "" {
+		o.SDID = DefaultSDID
+	}
+	if o.ToSeverity == nil {
+		o.ToSeverity = ToSeverity
+	}
+
+	return &Handler{w: w, opts: o}
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	r, _ = slogutils.ExtractControls(r)
+
+	buf := new(bytes.Buffer)
+
+	pri := h.opts.Facility.code()*8 + int(h.opts.ToSeverity(r.Level))
+	ts := nilValue
+	if !r.Time.IsZero() {
+		ts = r.Time.Format(time.RFC3339Nano)
+	}
+
+	// HEADER: <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID
+	_, _ = fmt.Fprintf(buf, "<%d>1 %s %s %s %d %s ",
+		pri,
+		ts,
+		headerField(h.opts.Hostname, 255),
+		headerField(h.opts.AppName, 48),
+		os.Getpid(),
+		nilValue,
+	)
+
+	h.appendStructuredData(buf, r)
+
+	if r.Message != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(r.Message)
+	}
+
+	err := h.w.writeMessage(buf.Bytes())
+	if err != nil && h.opts.OnError != nil {
+		h.opts.OnError(r, err)
+	}
+	return err
+}
+
+func (h *Handler) appendStructuredData(buf *bytes.Buffer, r slog.Record) {
+	params := new(bytes.Buffer)
+
+	prefix := ""
+	sep := h.opts.GroupKeys.Separator()
+	for _, goa := range h.goas {
+		if goa.Group != "" {
+			prefix += goa.Group + sep
+			continue
+		}
+		for _, a := range goa.Attrs {
+			appendParam(params, prefix, sep, slogutils.ResolveAttr(a, 0))
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		appendParam(params, prefix, sep, slogutils.ResolveAttr(a, 0))
+		return true
+	})
+
+	if params.Len() == 0 {
+		buf.WriteString(nilValue)
+		return
+	}
+	buf.WriteByte('[')
+	buf.WriteString(h.opts.SDID)
+	_, _ = params.WriteTo(buf)
+	buf.WriteByte(']')
+}
+
+func appendParam(buf *bytes.Buffer, prefix, sep string, a slog.Attr) {
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + sep
+		}
+		for _, ga := range a.Value.Group() {
+			appendParam(buf, groupPrefix, sep, ga)
+		}
+		return
+	}
+
+	buf.WriteByte(' ')
+	buf.WriteString(paramName(prefix + a.Key))
+	buf.WriteString(`="`)
+	buf.WriteString(paramValue(valueString(a.Value)))
+	buf.WriteByte('"')
+}
+
+func valueString(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindFloat64:
+		return strconv.FormatFloat(v.Float64(), 'g', -1, 64)
+	default:
+		return v.String()
+	}
+}
+
+// paramName returns a valid SD-NAME: at most 32 printable US-ASCII characters except '=', ' ', ']' and '"'.
+func paramName(name string) string {
+	if name == "" {
+		return "_"
+	}
+	var sb strings.Builder
+	for i := 0; i < len(name) && sb.Len() < 32; i++ {
+		c := name[i]
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// paramValue escapes '"', '\' and ']' in a PARAM-VALUE.
+func paramValue(value string) string {
+	return paramValueReplacer.Replace(value)
+}
+
+var paramValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// headerField returns a valid header field of printable US-ASCII characters with a maximum length.
+func headerField(value string, maxLen int) string {
+	if value == "" {
+		return nilValue
+	}
+	var sb strings.Builder
+	for i := 0; i < len(value) && sb.Len() < maxLen; i++ {
+		c := value[i]
+		if c <= ' ' || c > '~' {
+			c = '_'
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs) *Handler {
+	h2 := *h // Copy handler
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs, _ = slogutils.StripControlAttrs(attrs)
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs})
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name})
+}
+
+// Close closes the connection to the syslog server if the handler was created by Dial.
+func (h *Handler) Close() error {
+	return h.w.close()
+}
+
+// syncWriter serializes writes of messages and is shared by all handlers derived with WithAttrs and WithGroup.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+	// framed enables octet-counting framing (RFC 6587) for stream connections
+	framed bool
+	// redial reconnects after a failed write if not nil
+	redial func() (net.Conn, error)
+}
+
+func (sw *syncWriter) writeMessage(msg []byte) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	err := sw.write(msg)
+	if err == nil || sw.redial == nil {
+		return err
+	}
+
+	// Try to reconnect once, e.g. after the syslog server was restarted
+	if c, ok := sw.w.(io.Closer); ok {
+		_ = c.Close()
+	}
+	conn, dialErr := sw.redial()
+	if dialErr != nil {
+		return fmt.Errorf("reconnecting to syslog: %w (after write error: %v)", dialErr, err)
+	}
+	sw.w = conn
+	sw.framed = isStream(conn)
+	return sw.write(msg)
+}
+
+func (sw *syncWriter) write(msg []byte) error {
+	if sw.framed {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+	_, err := sw.w.Write(msg)
+	return err
+}
+
+func (sw *syncWriter) close() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if c, ok := sw.w.(io.Closer); ok && sw.redial != nil {
+		return c.Close()
+	}
+	return nil
+}
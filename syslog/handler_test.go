@@ -0,0 +1,178 @@
+package syslog_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/syslog"
+)
+
+func TestHandler(t *testing.T) {
+	pid := os.Getpid()
+
+	tests := []struct {
+		name string
+		opts *syslog.Options
+		f    func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "message without attributes",
+			f: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			want: fmt.Sprintf(`<14>1 TIME host app %d - - test`, pid),
+		},
+		{
+			name: "attributes as structured data",
+			f: func(l *slog.Logger) {
+				l.With("component", "db").WithGroup("query").Warn("slow query", "sql", `SELECT "x"`, "took", 2*time.Second)
+			},
+			want: fmt.Sprintf(`<12>1 TIME host app %d - [slog@32473 component="db" query.sql="SELECT \"x\"" query.took="2s"] slow query`, pid),
+		},
+		{
+			name: "trace level and facility",
+			opts: &syslog.Options{
+				Level:    slogutils.LevelTrace,
+				Facility: syslog.FacilityLocal0,
+			},
+			f: func(l *slog.Logger) {
+				l.Log(context.Background(), slogutils.LevelTrace, "trace")
+			},
+			want: fmt.Sprintf(`<135>1 TIME host app %d - - trace`, pid),
+		},
+		{
+			name: "kernel facility",
+			opts: &syslog.Options{
+				Facility: syslog.FacilityKern,
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			want: fmt.Sprintf(`<6>1 TIME host app %d - - test`, pid),
+		},
+		{
+			name: "invalid param names are sanitized",
+			f: func(l *slog.Logger) {
+				l.Error("test", "a key]", "val")
+			},
+			want: fmt.Sprintf(`<11>1 TIME host app %d - [slog@32473 a_key_="val"] test`, pid),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &syslog.Options{}
+			if tt.opts != nil {
+				opts = tt.opts
+			}
+			opts.Hostname = "host"
+			opts.AppName = "app"
+
+			buf := new(bytes.Buffer)
+			tt.f(slog.New(syslog.NewHandler(buf, opts)))
+
+			got := replaceTimestamp(buf.String())
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDial_tcp(t *testing.T) {
+	testDialStream(t, "tcp", "127.0.0.1:0")
+}
+
+func TestDial_unix(t *testing.T) {
+	testDialStream(t, "unix", filepath.Join(t.TempDir(), "log"))
+}
+
+// testDialStream checks that messages on stream connections are framed by octet counting.
+func testDialStream(t *testing.T, network, addr string) {
+	t.Helper()
+
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var size int
+		r := bufio.NewReader(conn)
+		if _, err := fmt.Fscanf(r, "%d ", &size); err != nil {
+			return
+		}
+		msg := make([]byte, size)
+		if _, err := r.Read(msg); err != nil {
+			return
+		}
+		received <- string(msg)
+	}()
+
+	h, err := syslog.Dial(network, ln.Addr().String(), &syslog.Options{Hostname: "host", AppName: "app"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer h.Close()
+
+	slog.New(h).Info("test")
+
+	select {
+	case msg := <-received:
+		if want := fmt.Sprintf(`<14>1 TIME host app %d - - test`, os.Getpid()); replaceTimestamp(msg) != want {
+			t.Fatalf("(-want +got)\n- %s\n+ %s", want, replaceTimestamp(msg))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for message")
+	}
+}
+
+// replaceTimestamp replaces the timestamp (second field) of a message.
+func replaceTimestamp(msg string) string {
+	parts := strings.SplitN(msg, " ", 3)
+	if len(parts) < 3 {
+		return msg
+	}
+	return parts[0] + " TIME " + parts[2]
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestHandler_OnError(t *testing.T) {
+	var gotErr error
+	h := syslog.NewHandler(failingWriter{}, &syslog.Options{
+		OnError: func(r slog.Record, err error) {
+			gotErr = err
+		},
+	})
+
+	err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0))
+
+	if err == nil || gotErr != err {
+		t.Fatalf("expected error to be returned and passed to OnError, got %v and %v", err, gotErr)
+	}
+}
@@ -0,0 +1,95 @@
+package slogutils
+
+import (
+	"log/slog"
+
+	"github.com/fatih/color"
+)
+
+// Theme is a set of colors for a CLIHandler.
+// Colors can use the base palette of fatih/color or 256 and true colors (see Color256 and TrueColor).
+type Theme struct {
+	// LevelColors are the colors of level prefixes and attribute keys, it must contain all levels.
+	LevelColors map[slog.Level]*color.Color
+	// DryRun is the color of the prefixes of records in a dry run.
+	DryRun *color.Color
+	// Badge is the color of badge attributes.
+	Badge *color.Color
+	// Time is the color of the time if it is rendered.
+	Time *color.Color
+	// ErrorLine is the color of lines of joined errors.
+	ErrorLine *color.Color
+}
+
+// Theme presets for a CLIHandler.
+var (
+	// ThemeDark is the default theme for terminals with a dark background.
+	ThemeDark = &Theme{
+		LevelColors: map[slog.Level]*color.Color{
+			LevelTrace:      color.New(color.Faint),
+			slog.LevelDebug: color.New(color.FgWhite, color.Faint),
+			slog.LevelInfo:  color.New(color.FgBlue),
+			slog.LevelWarn:  color.New(color.FgYellow),
+			slog.LevelError: color.New(color.FgRed),
+		},
+		DryRun:    color.New(color.FgMagenta),
+		Badge:     color.New(color.FgCyan),
+		Time:      color.New(color.Faint),
+		ErrorLine: color.New(color.Faint),
+	}
+
+	// ThemeLight is a theme for terminals with a light background, it avoids faint, white and yellow colors.
+	ThemeLight = &Theme{
+		LevelColors: map[slog.Level]*color.Color{
+			LevelTrace:      Color256(246),
+			slog.LevelDebug: Color256(242),
+			slog.LevelInfo:  color.New(color.FgBlue),
+			slog.LevelWarn:  Color256(130),
+			slog.LevelError: color.New(color.FgRed),
+		},
+		DryRun:    color.New(color.FgMagenta),
+		Badge:     Color256(30),
+		Time:      Color256(242),
+		ErrorLine: Color256(242),
+	}
+
+	// ThemeMonochrome is a theme without colors, levels are distinguished by intensity.
+	ThemeMonochrome = &Theme{
+		LevelColors: map[slog.Level]*color.Color{
+			LevelTrace:      color.New(color.Faint),
+			slog.LevelDebug: color.New(color.Faint),
+			slog.LevelInfo:  color.New(color.Reset),
+			slog.LevelWarn:  color.New(color.Bold),
+			slog.LevelError: color.New(color.Bold, color.Underline),
+		},
+		DryRun:    color.New(color.Italic),
+		Badge:     color.New(color.Bold),
+		Time:      color.New(color.Faint),
+		ErrorLine: color.New(color.Faint),
+	}
+
+	// ThemeHighContrast is a theme with bright and bold colors.
+	ThemeHighContrast = &Theme{
+		LevelColors: map[slog.Level]*color.Color{
+			LevelTrace:      color.New(color.FgHiWhite),
+			slog.LevelDebug: color.New(color.FgHiCyan),
+			slog.LevelInfo:  color.New(color.FgHiBlue, color.Bold),
+			slog.LevelWarn:  color.New(color.FgHiYellow, color.Bold),
+			slog.LevelError: color.New(color.FgHiRed, color.Bold),
+		},
+		DryRun:    color.New(color.FgHiMagenta, color.Bold),
+		Badge:     color.New(color.FgHiCyan, color.Bold),
+		Time:      color.New(color.FgHiWhite),
+		ErrorLine: color.New(color.FgHiRed),
+	}
+)
+
+// Color256 returns a foreground color of the 256 color palette.
+func Color256(n uint8) *color.Color {
+	return color.New(38, 5, color.Attribute(n))
+}
+
+// TrueColor returns a 24-bit foreground color.
+func TrueColor(r, g, b uint8) *color.Color {
+	return color.New(38, 2, color.Attribute(r), color.Attribute(g), color.Attribute(b))
+}
@@ -0,0 +1,45 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestTrueColor(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	if got := slogutils.TrueColor(255, 128, 0).Sprint("x"); got != "\x1b[38;2;255;128;0mx\x1b[0m" {
+		t.Fatalf("unexpected true color: %q", got)
+	}
+	if got := slogutils.Color256(130).Sprint("x"); got != "\x1b[38;5;130mx\x1b[0m" {
+		t.Fatalf("unexpected 256 color: %q", got)
+	}
+}
+
+func TestCLIHandler_Theme(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	for _, theme := range []*slogutils.Theme{slogutils.ThemeDark, slogutils.ThemeLight, slogutils.ThemeMonochrome, slogutils.ThemeHighContrast} {
+		var buf bytes.Buffer
+		logger := slog.New(slogutils.NewCLIHandler(&buf, &slogutils.CLIHandlerOptions{
+			Level: slogutils.LevelTrace,
+			Theme: theme,
+		}))
+		logger.Warn("test", "key", "val")
+
+		want := theme.LevelColors[slog.LevelWarn].Sprint("  ▲")
+		if got := buf.String(); !strings.HasPrefix(got, want) {
+			t.Fatalf("expected prefix in theme color %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// TimeOptions control how times are normalized before they are rendered.
+type TimeOptions struct {
+	// Location is the location times are rendered in.
+	// If Location is nil, UTC is used.
+	Location *time.Location
+
+	// Skew optionally returns an offset that is added to the time of records,
+	// e.g. the offset to a reference clock reported by an external time source (NTP, cloud metadata).
+	// It is called for every record and should be cheap.
+	Skew func() time.Duration
+}
+
+func (o *TimeOptions) location() *time.Location {
+	if o.Location == nil {
+		return time.UTC
+	}
+	return o.Location
+}
+
+// recordTime returns the normalized time of a record with the skew offset applied.
+func (o *TimeOptions) recordTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	if o.Skew != nil {
+		t = t.Add(o.Skew())
+	}
+	return t.In(o.location())
+}
+
+// attrTime returns the normalized time of a time attribute.
+// The skew offset is not applied, since the time might originate from a different clock.
+func (o *TimeOptions) attrTime(t time.Time) time.Time {
+	return t.In(o.location())
+}
+
+func (o *TimeOptions) normalizeAttr(a slog.Attr) slog.Attr {
+	return o.normalizeResolvedAttr(ResolveAttr(a, 0))
+}
+
+func (o *TimeOptions) normalizeResolvedAttr(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindTime:
+		a.Value = slog.TimeValue(o.attrTime(a.Value.Time()))
+	case slog.KindGroup:
+		groupAttrs := a.Value.Group()
+		normalized := make([]slog.Attr, len(groupAttrs))
+		for i, ga := range groupAttrs {
+			normalized[i] = o.normalizeResolvedAttr(ga)
+		}
+		a.Value = slog.GroupValue(normalized...)
+	}
+	return a
+}
+
+// TimeHandler normalizes the time of records and time attributes before delegating to another handler.
+// It can be used in front of any handler (e.g. slog.JSONHandler) that renders times as is.
+type TimeHandler struct {
+	next slog.Handler
+	opts *TimeOptions
+}
+
+var (
+	_ slog.Handler = (*TimeHandler)(nil)
+	_ AttrsEnabler = (*TimeHandler)(nil)
+	_ Wrapper      = (*TimeHandler)(nil)
+)
+
+// NewTimeHandler creates a new TimeHandler. If opts is nil, times are rendered in UTC.
+func NewTimeHandler(next slog.Handler, opts *TimeOptions) *TimeHandler {
+	if opts == nil {
+		opts = &TimeOptions{}
+	}
+	return &TimeHandler{next: next, opts: opts}
+}
+
+func (h *TimeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *TimeHandler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *TimeHandler) EnabledForAttrs(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+	return EnabledForAttrs(ctx, h.next, level, attrs)
+}
+
+func (h *TimeHandler) Handle(ctx context.Context, r slog.Record) error {
+	r2 := slog.NewRecord(h.opts.recordTime(r.Time), r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		r2.AddAttrs(h.opts.normalizeAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, r2)
+}
+
+func (h *TimeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	normalized := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		normalized[i] = h.opts.normalizeAttr(a)
+	}
+	return &TimeHandler{next: h.next.WithAttrs(normalized), opts: h.opts}
+}
+
+func (h *TimeHandler) WithGroup(name string) slog.Handler {
+	return &TimeHandler{next: h.next.WithGroup(name), opts: h.opts}
+}
@@ -0,0 +1,93 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestTimeHandler(t *testing.T) {
+	cet := time.FixedZone("CET", 3600)
+	recordTime := time.Date(2023, 8, 1, 12, 0, 0, 0, cet)
+
+	tests := []struct {
+		name string
+		opts *slogutils.TimeOptions
+		f    func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "record time is rendered in UTC by default",
+			f: func(l *slog.Logger) {
+				l.Info("test")
+			},
+			want: `time=2023-08-01T11:00:00.000Z level=INFO msg=test`,
+		},
+		{
+			name: "time attributes are rendered in location",
+			opts: &slogutils.TimeOptions{
+				Location: time.FixedZone("EST", -5*3600),
+			},
+			f: func(l *slog.Logger) {
+				l.With("started", recordTime).WithGroup("g").Info("test", slog.Group("h", "finished", recordTime))
+			},
+			want: `time=2023-08-01T06:00:00.000-05:00 level=INFO msg=test started=2023-08-01T06:00:00.000-05:00 g.h.finished=2023-08-01T06:00:00.000-05:00`,
+		},
+		{
+			name: "skew is applied to record time",
+			opts: &slogutils.TimeOptions{
+				Skew: func() time.Duration {
+					return -1500 * time.Millisecond
+				},
+			},
+			f: func(l *slog.Logger) {
+				l.Info("test", "at", recordTime)
+			},
+			want: `time=2023-08-01T10:59:58.500Z level=INFO msg=test at=2023-08-01T11:00:00.000Z`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			h := &fixedTimeHandler{
+				next: slogutils.NewTimeHandler(slog.NewTextHandler(buf, nil), tt.opts),
+				time: recordTime,
+			}
+			tt.f(slog.New(h))
+
+			got := strings.TrimRight(buf.String(), "\n")
+			if tt.want != got {
+				t.Fatalf("(-want +got)\n- %s\n+ %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// fixedTimeHandler sets a fixed time on records before delegating to the next handler.
+type fixedTimeHandler struct {
+	next slog.Handler
+	time time.Time
+}
+
+func (h *fixedTimeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *fixedTimeHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.Time = h.time
+	return h.next.Handle(ctx, r)
+}
+
+func (h *fixedTimeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &fixedTimeHandler{next: h.next.WithAttrs(attrs), time: h.time}
+}
+
+func (h *fixedTimeHandler) WithGroup(name string) slog.Handler {
+	return &fixedTimeHandler{next: h.next.WithGroup(name), time: h.time}
+}
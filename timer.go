@@ -0,0 +1,79 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// Keys for timer attributes.
+const (
+	// StartKey is the key for the wall clock start time of a timer.
+	StartKey = "start"
+	// DurationKey is the key for the duration of a timer.
+	DurationKey = "duration"
+)
+
+// Timer measures the duration of an operation with the monotonic clock.
+type Timer struct {
+	ctx   context.Context
+	start time.Time
+	stop  time.Time
+}
+
+// StartTimer starts a timer for an operation. The context is used to get the logger for StopAndLog.
+func StartTimer(ctx context.Context) *Timer {
+	return &Timer{ctx: ctx, start: time.Now()}
+}
+
+// Start returns the wall clock start time (without a monotonic clock reading).
+func (t *Timer) Start() time.Time {
+	return t.start.Round(0)
+}
+
+// Elapsed returns the duration since the start based on the monotonic clock,
+// or the duration until the timer was stopped.
+func (t *Timer) Elapsed() time.Duration {
+	if !t.stop.IsZero() {
+		return t.stop.Sub(t.start)
+	}
+	return time.Since(t.start)
+}
+
+// Stop stops the timer and returns the duration. Calling Stop again has no effect.
+func (t *Timer) Stop() time.Duration {
+	if t.stop.IsZero() {
+		t.stop = time.Now()
+	}
+	return t.Elapsed()
+}
+
+// Attrs returns the start time and duration attributes of the timer.
+func (t *Timer) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.Time(StartKey, t.Start()),
+		slog.Duration(DurationKey, t.Elapsed()),
+	}
+}
+
+// StopAndLog stops the timer and logs a record with the start time and duration attributes
+// with the logger from the context of the timer. It returns the duration.
+func (t *Timer) StopAndLog(level slog.Level, msg string, args ...any) time.Duration {
+	duration := t.Stop()
+
+	logger := FromContext(t.ctx)
+	if !logger.Enabled(t.ctx, level) {
+		return duration
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers and StopAndLog
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(t.Attrs()...)
+	r.Add(args...)
+	_ = logger.Handler().Handle(t.ctx, r)
+
+	return duration
+}
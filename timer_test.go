@@ -0,0 +1,47 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestTimer(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.TimeKey:
+				return slog.Attr{}
+			case slogutils.StartKey:
+				if a.Value.Time().IsZero() {
+					t.Error("expected start time to be set")
+				}
+				return slog.String(a.Key, "START")
+			case slogutils.DurationKey:
+				if a.Value.Duration() < 10*time.Millisecond {
+					t.Errorf("expected duration of at least 10ms, got %s", a.Value.Duration())
+				}
+				return slog.String(a.Key, "DURATION")
+			}
+			return a
+		},
+	}))
+	ctx := slogutils.WithLogger(context.Background(), logger)
+
+	timer := slogutils.StartTimer(ctx)
+	time.Sleep(10 * time.Millisecond)
+	duration := timer.StopAndLog(slog.LevelInfo, "Operation done", "items", 3)
+
+	if duration != timer.Elapsed() {
+		t.Errorf("expected elapsed to be fixed after stop")
+	}
+	if got := strings.TrimRight(buf.String(), "\n"); got != "level=INFO msg=\"Operation done\" start=START duration=DURATION items=3" {
+		t.Fatalf("unexpected log output: %s", got)
+	}
+}
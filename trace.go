@@ -0,0 +1,39 @@
+package slogutils
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// Trace logs a message at LevelTrace with the logger of the context (see FromContext).
+// The source of the record points to the caller of Trace.
+func Trace(ctx context.Context, msg string, args ...any) {
+	logTrace(ctx, FromContext(ctx), msg, args, nil)
+}
+
+// TraceAttrs is a more efficient version of Trace that accepts only attributes, see slog.Logger.LogAttrs.
+func TraceAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	logTrace(ctx, FromContext(ctx), msg, nil, attrs)
+}
+
+// TraceLogger logs a message at LevelTrace with the given logger and context.
+// The source of the record points to the caller of TraceLogger.
+func TraceLogger(ctx context.Context, logger *slog.Logger, msg string, args ...any) {
+	logTrace(ctx, logger, msg, args, nil)
+}
+
+func logTrace(ctx context.Context, logger *slog.Logger, msg string, args []any, attrs []slog.Attr) {
+	if !logger.Enabled(ctx, LevelTrace) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers, logTrace and the exported function
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(time.Now(), LevelTrace, msg, pcs[0])
+	r.Add(args...)
+	r.AddAttrs(attrs...)
+	_ = logger.Handler().Handle(ctx, r)
+}
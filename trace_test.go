@@ -0,0 +1,59 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestTrace(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		AddSource: true,
+		Level:     slogutils.LevelTrace,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch a.Key {
+			case slog.TimeKey:
+				return slog.Attr{}
+			case slog.SourceKey:
+				src := a.Value.Any().(*slog.Source)
+				return slog.String(slog.SourceKey, fmt.Sprintf("%s:%d", filepath.Base(src.File), src.Line))
+			}
+			return a
+		},
+	}))
+	ctx := slogutils.WithLogger(context.Background(), logger.With("component", "test"))
+
+	_, _, line, _ := runtime.Caller(0)
+	slogutils.Trace(ctx, "Trace message", "foo", "bar")
+	slogutils.TraceAttrs(ctx, "Trace attrs", slog.Int("n", 1))
+	slogutils.TraceLogger(ctx, logger, "Trace logger")
+
+	want := strings.Join([]string{
+		fmt.Sprintf(`level=DEBUG-4 source=trace_test.go:%d msg="Trace message" component=test foo=bar`, line+1),
+		fmt.Sprintf(`level=DEBUG-4 source=trace_test.go:%d msg="Trace attrs" component=test n=1`, line+2),
+		fmt.Sprintf(`level=DEBUG-4 source=trace_test.go:%d msg="Trace logger"`, line+3),
+	}, "\n")
+	got := strings.TrimRight(buf.String(), "\n")
+	if want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+func TestTrace_Disabled(t *testing.T) {
+	buf := new(bytes.Buffer)
+	ctx := slogutils.WithLogger(context.Background(), slog.New(slog.NewTextHandler(buf, nil)))
+
+	slogutils.Trace(ctx, "Trace message")
+
+	if buf.Len() > 0 {
+		t.Fatalf("expected no output, got %s", buf.String())
+	}
+}
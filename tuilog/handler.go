@@ -0,0 +1,157 @@
+// Package tuilog exposes log records as a stream of rendered lines for TUI applications.
+//
+// The Handler renders records with a regular handler (the CLIHandler by default) and sends them as Line values
+// to a bounded channel. The package does not depend on Bubble Tea, a small adapter turns the channel into a tea.Cmd
+// (a func() any is not assignable to tea.Cmd, since tea.Msg is a distinct type), so a log pane can be fed by the
+// same handlers as the rest of the application:
+//
+//	func waitForLine(logs *tuilog.Handler) tea.Cmd {
+//		return func() tea.Msg {
+//			return <-logs.Lines()
+//		}
+//	}
+//
+//	func (m model) Init() tea.Cmd {
+//		return waitForLine(m.logs)
+//	}
+//
+//	func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+//		switch msg := msg.(type) {
+//		case tuilog.Line:
+//			m.lines = append(m.lines, msg)
+//			return m, waitForLine(m.logs)
+//		}
+//		// ...
+//	}
+package tuilog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+const defaultBufferSize = 1000
+
+// Line is a rendered record with metadata for filtering.
+type Line struct {
+	Time  time.Time
+	Level slog.Level
+	// Message is the unrendered message of the record
+	Message string
+	// Text is the rendered record without a trailing newline
+	Text string
+}
+
+// Options are options for a Handler.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// BufferSize is the capacity of the channel of lines, defaults to 1000.
+	// Lines are dropped if the channel is full (see Handler.Dropped).
+	BufferSize int
+
+	// NewHandler creates the handler used to render records to w.
+	// If NewHandler is nil, a slogutils.CLIHandler with slogutils.LevelTrace is used.
+	NewHandler func(w io.Writer) slog.Handler
+}
+
+// Handler renders records and sends them as lines to a bounded channel.
+type Handler struct {
+	inner  slog.Handler
+	stream *stream
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// stream is shared by all handlers derived with WithAttrs and WithGroup.
+type stream struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	lines   chan Line
+	dropped atomic.Int64
+}
+
+// NewHandler creates a new handler streaming rendered lines.
+func NewHandler(opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+	bufferSize := opts.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	newHandler := opts.NewHandler
+	if newHandler == nil {
+		newHandler = func(w io.Writer) slog.Handler {
+			return slogutils.NewCLIHandler(w, &slogutils.CLIHandlerOptions{Level: slogutils.LevelTrace})
+		}
+	}
+
+	s := &stream{lines: make(chan Line, bufferSize)}
+	return &Handler{
+		inner:  newHandler(&s.buf),
+		stream: s,
+	}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.stream
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf.Reset()
+	if err := h.inner.Handle(ctx, r); err != nil {
+		return err
+	}
+
+	line := Line{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Text:    strings.TrimRight(s.buf.String(), "\n"),
+	}
+	select {
+	case s.lines <- line:
+	default:
+		s.dropped.Add(1)
+	}
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{inner: h.inner.WithAttrs(attrs), stream: h.stream}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{inner: h.inner.WithGroup(name), stream: h.stream}
+}
+
+// Lines returns the channel of rendered lines.
+func (h *Handler) Lines() <-chan Line {
+	return h.stream.lines
+}
+
+// Wait returns a function that blocks until the next line is available and returns it.
+// It is not assignable to Bubble Tea's tea.Cmd, wrap Lines in a func() tea.Msg instead (see the package documentation).
+func (h *Handler) Wait() func() any {
+	return func() any {
+		return <-h.stream.lines
+	}
+}
+
+// Dropped returns the number of lines dropped because the channel was full.
+func (h *Handler) Dropped() int64 {
+	return h.stream.dropped.Load()
+}
@@ -0,0 +1,56 @@
+package tuilog_test
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/networkteam/slogutils/tuilog"
+)
+
+func TestHandler(t *testing.T) {
+	h := tuilog.NewHandler(&tuilog.Options{BufferSize: 2})
+	logger := slog.New(h)
+
+	logger.With("component", "db").Info("Connected", "host", "localhost")
+	logger.Warn("Slow")
+	logger.Error("Dropped")
+
+	line := h.Wait()().(tuilog.Line)
+	if line.Level != slog.LevelInfo || line.Message != "Connected" {
+		t.Fatalf("unexpected line: %+v", line)
+	}
+	if want := "  • Connected                 component=db host=localhost"; line.Text != want {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, line.Text)
+	}
+
+	line = <-h.Lines()
+	if line.Level != slog.LevelWarn || line.Text != "  ▲ Slow                     " {
+		t.Fatalf("unexpected line: %+v", line)
+	}
+
+	if h.Dropped() != 1 {
+		t.Fatalf("expected 1 dropped line, got %d", h.Dropped())
+	}
+}
+
+func TestHandler_customHandler(t *testing.T) {
+	h := tuilog.NewHandler(&tuilog.Options{
+		NewHandler: func(w io.Writer) slog.Handler {
+			return slog.NewJSONHandler(w, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+		},
+	})
+	slog.New(h).Info("test")
+
+	line := <-h.Lines()
+	if want := `{"level":"INFO","msg":"test"}`; line.Text != want {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, line.Text)
+	}
+}
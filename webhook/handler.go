@@ -0,0 +1,420 @@
+// Package webhook provides a handler notifying a webhook (e.g. Slack or Microsoft Teams) about high-severity records.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/networkteam/slogutils"
+)
+
+// Format is the format of the request body.
+type Format int
+
+const (
+	// FormatText posts the rendered template as {"text": "..."}, which is understood by incoming webhooks of
+	// Slack, Microsoft Teams and Mattermost.
+	FormatText Format = iota
+	// FormatJSON posts the notification as a JSON object with time, level, message, attrs and suppressed.
+	FormatJSON
+)
+
+const (
+	defaultRateLimit    = 10
+	defaultRateInterval = time.Minute
+	defaultQueueSize    = 100
+	defaultTimeout      = 10 * time.Second
+	defaultFlushTimeout = 5 * time.Second
+)
+
+// DefaultTemplate is the default template of notification texts.
+var DefaultTemplate = template.Must(template.New("notification").Parse(
+	"*{{.Level}}*: {{.Message}}{{range .Attrs}}\n• {{.Key}}: {{.Value}}{{end}}" +
+		"{{if .Suppressed}}\n_{{.Suppressed}} more notifications were suppressed_{{end}}",
+))
+
+// Options are options for a Handler.
+// A zero Options consists entirely of default values.
+type Options struct {
+	// Level is the minimum level of records sent to the webhook.
+	// If Level is nil, slog.LevelError is used.
+	Level slog.Leveler
+
+	// Format of the request body, defaults to FormatText.
+	Format Format
+
+	// Template renders the text of a Notification for FormatText, defaults to DefaultTemplate.
+	Template *template.Template
+
+	// RateLimit is the maximum number of notifications per RateInterval, defaults to 10.
+	// Further notifications are suppressed and counted in the next notification.
+	RateLimit int
+
+	// RateInterval is the interval of the rate limit, defaults to one minute.
+	RateInterval time.Duration
+
+	// QueueSize is the number of notifications that are queued for delivery, defaults to 100.
+	// Notifications are suppressed if the queue is full.
+	QueueSize int
+
+	// Client is the HTTP client for requests, defaults to a client with a timeout of 10 seconds.
+	Client *http.Client
+
+	// FlushTimeout is the maximum duration Close waits for queued notifications to be delivered, defaults to
+	// 5 seconds. Remaining notifications are dropped when the timeout is exceeded.
+	FlushTimeout time.Duration
+
+	// Header is added to every request.
+	Header http.Header
+
+	// OnError is called with the record and the error if the notification of the record could not be delivered.
+	// If OnError is nil, the error is written to os.Stderr.
+	OnError func(r slog.Record, err error)
+
+	// GroupKeys controls how keys of attributes in groups are joined, defaults to a dot.
+	GroupKeys slogutils.GroupKeys
+}
+
+// Notification is the data of a notification that is passed to the template.
+type Notification struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// Attrs are the attributes of the record with keys qualified by group names separated by dots
+	Attrs []slog.Attr
+	// Suppressed is the number of notifications that were suppressed since the last notification
+	Suppressed int
+}
+
+// Handler sends records with a minimum level asynchronously to a webhook and passes all records to the wrapped
+// handler (if not nil). Close must be called to deliver queued notifications before the program exits.
+type Handler struct {
+	next     slog.Handler
+	opts     *Options
+	notifier *notifier
+	goas     []slogutils.GroupOrAttrs
+}
+
+var (
+	_ slog.Handler      = (*Handler)(nil)
+	_ slogutils.Wrapper = (*Handler)(nil)
+)
+
+// NewHandler creates a handler sending notifications to url and wrapping the given handler, which can be nil.
+func NewHandler(next slog.Handler, url string, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	o := *opts
+	if o.Level == nil {
+		o.Level = slog.LevelError
+	}
+	if o.Template == nil {
+		o.Template = DefaultTemplate
+	}
+	if o.RateLimit <= 0 {
+		o.RateLimit = defaultRateLimit
+	}
+	if o.RateInterval <= 0 {
+		o.RateInterval = defaultRateInterval
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = defaultQueueSize
+	}
+	if o.Client == nil {
+		o.Client = &http.Client{Timeout: defaultTimeout}
+	}
+	if o.FlushTimeout <= 0 {
+		o.FlushTimeout = defaultFlushTimeout
+	}
+	if o.OnError == nil {
+		o.OnError = func(_ slog.Record, err error) {
+			_, _ = fmt.Fprintf(os.Stderr, "webhook: %v\n", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &notifier{
+		url:    url,
+		opts:   &o,
+		ctx:    ctx,
+		cancel: cancel,
+		queue:  make(chan queued, o.QueueSize),
+		doneCh: make(chan struct{}),
+	}
+	go n.run()
+
+	return &Handler{next: next, opts: &o, notifier: n}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level >= h.opts.Level.Level() {
+		return true
+	}
+	return h.next != nil && h.next.Enabled(ctx, level)
+}
+
+// Unwrap returns the wrapped handler.
+func (h *Handler) Unwrap() slog.Handler {
+	return h.next
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	r, _ = slogutils.ExtractControls(r)
+
+	if r.Level >= h.opts.Level.Level() {
+		h.notifier.notify(r, h.notification(r))
+	}
+	if h.next != nil && h.next.Enabled(ctx, r.Level) {
+		return h.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (h *Handler) notification(r slog.Record) Notification {
+	n := Notification{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+	}
+
+	prefix := ""
+	sep := h.opts.GroupKeys.Separator()
+	for _, goa := range h.goas {
+		if goa.Group != "" {
+			prefix += goa.Group + sep
+			continue
+		}
+		for _, a := range goa.Attrs {
+			n.Attrs = appendAttr(n.Attrs, prefix, sep, slogutils.ResolveAttr(a, 0))
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		n.Attrs = appendAttr(n.Attrs, prefix, sep, slogutils.ResolveAttr(a, 0))
+		return true
+	})
+
+	return n
+}
+
+func appendAttr(attrs []slog.Attr, prefix, sep string, a slog.Attr) []slog.Attr {
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		a.Key = prefix + a.Key
+		return append(attrs, a)
+	}
+
+	groupPrefix := prefix
+	if a.Key != "" {
+		groupPrefix += a.Key + sep
+	}
+	for _, ga := range a.Value.Group() {
+		attrs = appendAttr(attrs, groupPrefix, sep, ga)
+	}
+	return attrs
+}
+
+func (h *Handler) withGroupOrAttrs(goa slogutils.GroupOrAttrs, next slog.Handler) *Handler {
+	h2 := *h // Copy handler
+	h2.next = next
+	h2.goas = make([]slogutils.GroupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs, _ = slogutils.StripControlAttrs(attrs)
+	if len(attrs) == 0 {
+		return h
+	}
+	var next slog.Handler
+	if h.next != nil {
+		next = h.next.WithAttrs(attrs)
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Attrs: attrs}, next)
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	var next slog.Handler
+	if h.next != nil {
+		next = h.next.WithGroup(name)
+	}
+	return h.withGroupOrAttrs(slogutils.GroupOrAttrs{Group: name}, next)
+}
+
+// Close delivers queued notifications and stops the handler (and all handlers derived with WithAttrs and WithGroup).
+// It waits at most for the configured flush timeout, a pending request and remaining notifications are dropped when
+// the timeout is exceeded. Notifications of records handled after Close are dropped.
+func (h *Handler) Close() error {
+	n := h.notifier
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	timer := time.NewTimer(h.opts.FlushTimeout)
+	defer timer.Stop()
+	select {
+	case <-n.doneCh:
+		return nil
+	case <-timer.C:
+		n.cancel()
+		<-n.doneCh
+		return errors.New("closing timed out")
+	}
+}
+
+// notifier rate limits and delivers notifications, it is shared by all handlers derived with WithAttrs and WithGroup.
+type notifier struct {
+	url  string
+	opts *Options
+	// ctx is cancelled to abort delivery when Close times out
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu          sync.Mutex
+	closed      bool
+	windowStart time.Time
+	windowCount int
+	suppressed  int
+
+	queue  chan queued
+	doneCh chan struct{}
+}
+
+// queued is a notification and the record it was created from.
+type queued struct {
+	record       slog.Record
+	notification Notification
+}
+
+func (n *notifier) notify(r slog.Record, notification Notification) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.closed {
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(n.windowStart) >= n.opts.RateInterval {
+		n.windowStart = now
+		n.windowCount = 0
+	}
+	if n.windowCount >= n.opts.RateLimit {
+		n.suppressed++
+		return
+	}
+
+	notification.Suppressed = n.suppressed
+	select {
+	// The record is cloned, since it is passed to OnError after Handle returned
+	case n.queue <- queued{record: r.Clone(), notification: notification}:
+		n.windowCount++
+		n.suppressed = 0
+	default:
+		n.suppressed++
+	}
+}
+
+func (n *notifier) run() {
+	defer close(n.doneCh)
+	defer n.cancel()
+
+	for q := range n.queue {
+		if n.ctx.Err() != nil {
+			continue
+		}
+		if err := n.send(q.notification); err != nil {
+			n.opts.OnError(q.record, err)
+		}
+	}
+}
+
+func (n *notifier) send(notification Notification) error {
+	body, err := n.body(notification)
+	if err != nil {
+		return fmt.Errorf("encoding notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range n.opts.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.opts.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("sending notification: %w", errors.New(resp.Status+": "+strings.TrimSpace(string(msg))))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (n *notifier) body(notification Notification) ([]byte, error) {
+	if n.opts.Format == FormatJSON {
+		attrs := make(map[string]any, len(notification.Attrs))
+		for _, a := range notification.Attrs {
+			attrs[a.Key] = jsonValue(a.Value)
+		}
+		return json.Marshal(struct {
+			Time       time.Time      `json:"time"`
+			Level      string         `json:"level"`
+			Message    string         `json:"message"`
+			Attrs      map[string]any `json:"attrs"`
+			Suppressed int            `json:"suppressed"`
+		}{
+			Time:       notification.Time,
+			Level:      notification.Level.String(),
+			Message:    notification.Message,
+			Attrs:      attrs,
+			Suppressed: notification.Suppressed,
+		})
+	}
+
+	text := new(strings.Builder)
+	if err := n.opts.Template.Execute(text, notification); err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Text string `json:"text"`
+	}{Text: text.String()})
+}
+
+func jsonValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindString, slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindBool, slog.KindTime:
+		return v.Any()
+	default:
+		return v.String()
+	}
+}
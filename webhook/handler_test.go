@@ -0,0 +1,136 @@
+package webhook_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/networkteam/slogutils"
+	"github.com/networkteam/slogutils/webhook"
+)
+
+type server struct {
+	mu     sync.Mutex
+	bodies []map[string]any
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.bodies = append(s.bodies, body)
+}
+
+func TestHandler(t *testing.T) {
+	srv := &server{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	buf := new(bytes.Buffer)
+	h := webhook.NewHandler(slog.NewTextHandler(buf, nil), ts.URL, &webhook.Options{RateLimit: 2})
+	logger := slog.New(h).With("service", "api")
+
+	logger.Info("Started")
+	logger.WithGroup("db").Error("Query failed", slogutils.Err(errors.New("timeout")))
+	logger.Error("Second")
+	logger.Error("Suppressed")
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := strings.Count(buf.String(), "\n"); n != 4 {
+		t.Fatalf("expected all 4 records to be passed to the wrapped handler, got %d", n)
+	}
+
+	want := []string{
+		"*ERROR*: Query failed\n• service: api\n• db.err: timeout",
+		"*ERROR*: Second\n• service: api",
+	}
+	if len(srv.bodies) != len(want) {
+		t.Fatalf("expected %d notifications, got %d", len(want), len(srv.bodies))
+	}
+	for i, text := range want {
+		if got := srv.bodies[i]["text"]; got != text {
+			t.Errorf("expected text %q, got %q", text, got)
+		}
+	}
+}
+
+func TestHandler_JSON(t *testing.T) {
+	srv := &server{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	h := webhook.NewHandler(nil, ts.URL, &webhook.Options{Format: webhook.FormatJSON})
+	slog.New(h).Error("test", "count", 3)
+	_ = h.Close()
+
+	body := srv.bodies[0]
+	if body["message"] != "test" || body["level"] != "ERROR" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+	if attrs, _ := body["attrs"].(map[string]any); attrs["count"] != float64(3) {
+		t.Fatalf("unexpected attrs: %v", body["attrs"])
+	}
+}
+
+func TestHandler_CloseTimeout(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	h := webhook.NewHandler(nil, ts.URL, &webhook.Options{
+		FlushTimeout: 50 * time.Millisecond,
+		OnError:      func(slog.Record, error) {},
+	})
+	slog.New(h).Error("one")
+	slog.New(h).Error("two")
+
+	start := time.Now()
+	if err := h.Close(); err == nil {
+		t.Fatal("expected error after timeout")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("expected Close to return after flush timeout, took %s", d)
+	}
+}
+
+func TestHandler_OnError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	var messages []string
+	h := webhook.NewHandler(nil, ts.URL, &webhook.Options{
+		OnError: func(r slog.Record, err error) {
+			messages = append(messages, r.Message+": "+err.Error())
+		},
+	})
+	slog.New(h).Error("Payment failed", "order", 42)
+	_ = h.Close()
+
+	want := []string{"Payment failed: sending notification: 403 Forbidden: invalid token"}
+	if len(messages) != 1 || messages[0] != want[0] {
+		t.Fatalf("expected %v, got %v", want, messages)
+	}
+}
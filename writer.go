@@ -0,0 +1,132 @@
+package slogutils
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultMaxLineLength is the default maximum length of a line buffered by a Writer.
+const DefaultMaxLineLength = 64 * 1024
+
+// WriterOptions are options for a Writer.
+// A zero WriterOptions consists entirely of default values.
+type WriterOptions struct {
+	// Level is the level of records, defaults to info.
+	Level slog.Level
+
+	// LevelOf returns the level and message of a line, e.g. by detecting a prefix of the line.
+	// If LevelOf is nil, lines are logged at Level.
+	LevelOf func(line string) (slog.Level, string)
+
+	// MaxLineLength is the maximum length of a line in bytes, defaults to DefaultMaxLineLength.
+	// Longer lines are logged in parts, so output without newlines does not grow the buffer indefinitely.
+	MaxLineLength int
+}
+
+// Writer logs every line written to it as a record at a fixed level, e.g. for exec.Cmd Stdout and Stderr or other
+// APIs that only accept an io.Writer. Partial lines are buffered until a newline is written or Flush is called.
+// See package stdlog for a writer detecting the level from a prefix of lines.
+type Writer struct {
+	logger        *slog.Logger
+	level         slog.Level
+	levelOf       func(line string) (slog.Level, string)
+	maxLineLength int
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+var _ io.Writer = (*Writer)(nil)
+
+// NewWriter creates a new writer logging lines to the logger at the given level.
+func NewWriter(logger *slog.Logger, level slog.Level) *Writer {
+	return NewWriterWithOptions(logger, &WriterOptions{Level: level})
+}
+
+// NewWriterWithOptions creates a new writer logging lines to the logger.
+func NewWriterWithOptions(logger *slog.Logger, opts *WriterOptions) *Writer {
+	if opts == nil {
+		opts = &WriterOptions{}
+	}
+	maxLineLength := opts.MaxLineLength
+	if maxLineLength <= 0 {
+		maxLineLength = DefaultMaxLineLength
+	}
+	return &Writer{
+		logger:        logger,
+		level:         opts.Level,
+		levelOf:       opts.LevelOf,
+		maxLineLength: maxLineLength,
+	}
+}
+
+// Write logs all complete lines in p. Lines exceeding the maximum line length are logged in parts.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i >= 0 && i <= w.maxLineLength {
+			w.log(string(w.buf[:i]))
+			w.buf = w.buf[i+1:]
+			continue
+		}
+		if len(w.buf) <= w.maxLineLength {
+			break
+		}
+		n := w.maxLineLength
+		// Do not split a multibyte character
+		for n > 0 && !utf8.RuneStart(w.buf[n]) {
+			n--
+		}
+		if n == 0 {
+			n = w.maxLineLength
+		}
+		w.log(string(w.buf[:n]))
+		w.buf = w.buf[n:]
+	}
+	if len(w.buf) == 0 {
+		// Release the memory of long lines
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Flush logs a buffered partial line, e.g. after a command exited.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.log(string(w.buf))
+		w.buf = nil
+	}
+	return nil
+}
+
+func (w *Writer) log(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	level, msg := w.level, line
+	if w.levelOf != nil {
+		level, msg = w.levelOf(line)
+	}
+
+	ctx := context.Background()
+	if !w.logger.Enabled(ctx, level) {
+		return
+	}
+	r := slog.NewRecord(time.Now(), level, msg, 0)
+	_ = w.logger.Handler().Handle(ctx, r)
+}
@@ -0,0 +1,51 @@
+package slogutils_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/networkteam/slogutils"
+)
+
+func TestWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: drop(slog.TimeKey),
+	})).With("cmd", "make")
+
+	w := slogutils.NewWriter(logger, slog.LevelWarn)
+	_, _ = fmt.Fprint(w, "first line\r\nsecond ")
+	_, _ = fmt.Fprint(w, "line\n\n")
+	_, _ = fmt.Fprint(w, "partial")
+
+	want := "level=WARN msg=\"first line\" cmd=make\nlevel=WARN msg=\"second line\" cmd=make\n"
+	if got := buf.String(); want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+
+	_ = w.Flush()
+
+	want += "level=WARN msg=partial cmd=make\n"
+	if got := buf.String(); want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}
+
+func TestWriter_MaxLineLength(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: drop(slog.TimeKey, slog.LevelKey),
+	}))
+
+	w := slogutils.NewWriterWithOptions(logger, &slogutils.WriterOptions{MaxLineLength: 4})
+	_, _ = fmt.Fprint(w, "abcdefghij\nxy")
+	_, _ = fmt.Fprint(w, "zäbc")
+	_ = w.Flush()
+
+	want := "msg=abcd\nmsg=efgh\nmsg=ij\nmsg=xyz\nmsg=äbc\n"
+	if got := buf.String(); want != got {
+		t.Fatalf("(-want +got)\n- %s\n+ %s", want, got)
+	}
+}